@@ -0,0 +1,251 @@
+package ahocorasick
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+// ErrEmptyPattern is returned by New when one of the patterns is empty
+var ErrEmptyPattern = errors.New("empty pattern")
+
+// MatchKind selects which matches are reported when patterns overlap
+type MatchKind int
+
+const (
+	// Overlapping reports every occurrence of every pattern, ordered by end offset
+	Overlapping MatchKind = iota
+	// LeftmostLongest reports non-overlapping matches, preferring the earliest start and then the longest pattern
+	LeftmostLongest
+)
+
+type Config struct {
+	MatchKind MatchKind
+	// CaseInsensitive makes ASCII letters match regardless of case
+	CaseInsensitive bool
+	MetricsEnabled  bool
+}
+
+// Match is an occurrence of a pattern in the searched text
+type Match struct {
+	Pattern int // Index of the pattern in the slice passed to New
+	Start   int
+	End     int // Exclusive
+}
+
+// Matcher is an Aho-Corasick automaton finding many patterns in a single pass over the text
+// The trie is stored as a double array: the child of state s on symbol c is
+// base[s]+c when check[base[s]+c] == s. Bytes not occurring in any pattern
+// share symbol 0, which has no transitions. A Matcher is immutable and safe
+// for concurrent use.
+type Matcher struct {
+	classes  [256]int32 // Symbol of every input byte
+	base     []int32
+	check    []int32
+	fail     []int32
+	output   []int32 // Pattern ending at the state, -1 when none
+	dict     []int32 // Nearest state on the failure chain with an output, -1 when none
+	depth    []int32
+	lengths  []int // Length of every pattern
+	patterns int
+	config   Config
+
+	bytesCounter   metrics.Counter
+	matchesCounter metrics.Counter
+}
+
+const root = 0
+
+// trieNode is a node of the pointer trie built before packing it into the double array
+type trieNode struct {
+	children map[int32]int
+	output   int32
+	depth    int32
+}
+
+// New builds a matcher for patterns
+// When a pattern is repeated the lowest index is reported.
+// The config parameter selects the match kind, case folding and enables metrics collection
+func New(patterns []string, config Config) (*Matcher, error) {
+	m := &Matcher{lengths: make([]int, len(patterns)), patterns: len(patterns), config: config}
+
+	// Number the bytes used by the patterns, symbol 0 is every other byte
+	var codes [256]int32
+	symbols := int32(0)
+	for i, pattern := range patterns {
+		if len(pattern) == 0 {
+			return nil, ErrEmptyPattern
+		}
+		m.lengths[i] = len(pattern)
+		for j := 0; j < len(pattern); j++ {
+			if c := m.fold(pattern[j]); codes[c] == 0 {
+				symbols++
+				codes[c] = symbols
+			}
+		}
+	}
+	for b := range m.classes {
+		m.classes[b] = codes[m.fold(byte(b))]
+	}
+
+	nodes := []trieNode{{children: map[int32]int{}, output: -1}}
+	for i, pattern := range patterns {
+		n := 0
+		for j := 0; j < len(pattern); j++ {
+			c := m.classes[pattern[j]]
+			child, ok := nodes[n].children[c]
+			if !ok {
+				child = len(nodes)
+				nodes = append(nodes, trieNode{children: map[int32]int{}, output: -1, depth: nodes[n].depth + 1})
+				nodes[n].children[c] = child
+			}
+			n = child
+		}
+		if nodes[n].output < 0 {
+			nodes[n].output = int32(i)
+		}
+	}
+	m.pack(nodes, symbols)
+
+	// Initialize the metrics only if enabled in the config
+	if m.config.MetricsEnabled {
+		m.bytesCounter = metrics.NewCounter()
+		m.matchesCounter = metrics.NewCounter()
+		metrics.DefaultRegistry.Register("ahocorasick.bytes", m.bytesCounter)
+		metrics.DefaultRegistry.Register("ahocorasick.matches", m.matchesCounter)
+	}
+
+	return m, nil
+}
+
+// fold lowercases ASCII letters when matching case-insensitively
+func (m *Matcher) fold(b byte) byte {
+	if m.config.CaseInsensitive && 'A' <= b && b <= 'Z' {
+		return b + 'a' - 'A'
+	}
+	return b
+}
+
+// pack lays the trie out as a double array and computes the failure and dictionary links
+// Nodes are placed breadth first so that failure links always point to placed states.
+func (m *Matcher) pack(nodes []trieNode, symbols int32) {
+	m.grow(len(nodes) + int(symbols) + 1)
+	m.check[root] = root
+	m.output[root] = nodes[root].output
+
+	type entry struct {
+		node  int
+		state int32
+	}
+	queue := []entry{{root, root}}
+	firstFree, last := 1, 0
+	for len(queue) > 0 {
+		e := queue[0]
+		queue = queue[1:]
+		children := nodes[e.node].children
+		if len(children) == 0 {
+			continue
+		}
+		codes := make([]int32, 0, len(children))
+		for c := range children {
+			codes = append(codes, c)
+		}
+		slices.Sort(codes)
+
+		// First fit: the lowest base placing every child on a free slot
+		for firstFree < len(m.check) && m.check[firstFree] >= 0 {
+			firstFree++
+		}
+		base := max(int32(firstFree)-codes[0], 0)
+		for ; ; base++ {
+			m.grow(int(base + symbols + 1))
+			if !slices.ContainsFunc(codes, func(c int32) bool { return m.check[base+c] >= 0 }) {
+				break
+			}
+		}
+		m.base[e.state] = base
+
+		for _, c := range codes {
+			node, t := children[c], base+c
+			m.check[t] = e.state
+			m.output[t] = nodes[node].output
+			m.depth[t] = nodes[node].depth
+			last = max(last, int(t))
+
+			// The failure link is the longest proper suffix present in the trie
+			m.fail[t] = root
+			if e.state != root {
+				f := m.fail[e.state]
+				for {
+					if next, ok := m.next(f, c); ok {
+						m.fail[t] = next
+						break
+					}
+					if f == root {
+						break
+					}
+					f = m.fail[f]
+				}
+			}
+			if f := m.fail[t]; m.output[f] >= 0 {
+				m.dict[t] = f
+			} else {
+				m.dict[t] = m.dict[f]
+			}
+			queue = append(queue, entry{node, t})
+		}
+	}
+
+	n := last + 1
+	m.base, m.check, m.fail = slices.Clip(m.base[:n]), slices.Clip(m.check[:n]), slices.Clip(m.fail[:n])
+	m.output, m.dict, m.depth = slices.Clip(m.output[:n]), slices.Clip(m.dict[:n]), slices.Clip(m.depth[:n])
+}
+
+// grow extends the double array to at least n slots, new slots are free
+func (m *Matcher) grow(n int) {
+	for len(m.check) < n {
+		m.base = append(m.base, 0)
+		m.check = append(m.check, -1)
+		m.fail = append(m.fail, root)
+		m.output = append(m.output, -1)
+		m.dict = append(m.dict, -1)
+		m.depth = append(m.depth, 0)
+	}
+}
+
+// next returns the child of state s on symbol c
+func (m *Matcher) next(s, c int32) (int32, bool) {
+	if c == 0 {
+		return 0, false
+	}
+	t := m.base[s] + c
+	if int(t) < len(m.check) && m.check[t] == s && t != root {
+		return t, true
+	}
+	return 0, false
+}
+
+// step follows the goto and failure transitions of the automaton on byte b
+func (m *Matcher) step(s int32, b byte) int32 {
+	c := m.classes[b]
+	for {
+		if t, ok := m.next(s, c); ok {
+			return t
+		}
+		if s == root {
+			return root
+		}
+		s = m.fail[s]
+	}
+}
+
+// Patterns returns the number of patterns the matcher was built from
+func (m *Matcher) Patterns() int {
+	return m.patterns
+}
+
+// States returns the number of slots of the double array, a measure of its memory use
+func (m *Matcher) States() int {
+	return len(m.check)
+}
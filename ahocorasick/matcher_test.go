@@ -0,0 +1,73 @@
+package ahocorasick
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// checkDoubleArray verifies that every state is reachable from its parent's base
+func checkDoubleArray(t *testing.T, m *Matcher) {
+	for s := 1; s < len(m.check); s++ {
+		if parent := m.check[s]; parent >= 0 {
+			c := int32(s) - m.base[parent]
+			next, ok := m.next(parent, c)
+			assert.True(t, ok)
+			assert.Equal(t, int32(s), next)
+			assert.Equal(t, m.depth[parent]+1, m.depth[s])
+			assert.Less(t, m.depth[m.fail[s]], m.depth[s])
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	m, err := New([]string{"he", "she", "his", "hers"}, Config{})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, m.Patterns())
+	checkDoubleArray(t, m)
+
+	_, err = New([]string{"a", ""}, Config{})
+	assert.Equal(t, ErrEmptyPattern, err)
+
+	m, err = New(nil, Config{})
+	assert.NoError(t, err)
+	assert.Empty(t, m.FindAll([]byte("anything")))
+}
+
+func TestDoubleArrayCompact(t *testing.T) {
+	patterns := make([]string, 2000)
+	for i := range patterns {
+		patterns[i] = fmt.Sprintf("keyword-%d", i*7919)
+	}
+	m, err := New(patterns, Config{})
+	assert.NoError(t, err)
+	checkDoubleArray(t, m)
+
+	// Count the trie nodes and make sure the packed layout does not waste much room
+	nodes := 1
+	for s := 1; s < len(m.check); s++ {
+		if m.check[s] >= 0 {
+			nodes++
+		}
+	}
+	assert.Less(t, m.States(), 2*nodes)
+}
+
+func TestCaseInsensitive(t *testing.T) {
+	m, err := New([]string{"ERROR", "warn"}, Config{CaseInsensitive: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []Match{
+		{Pattern: 0, Start: 0, End: 5},
+		{Pattern: 1, Start: 6, End: 10},
+		{Pattern: 0, Start: 11, End: 16},
+	}, m.FindAll([]byte("error WARN ErRoR")))
+
+	m, _ = New([]string{"ERROR"}, Config{})
+	assert.Empty(t, m.FindAll([]byte("error")))
+}
+
+func TestDuplicatePatterns(t *testing.T) {
+	m, _ := New([]string{"ab", "x", "ab"}, Config{})
+	assert.Equal(t, []Match{{Pattern: 0, Start: 1, End: 3}}, m.FindAll([]byte("cab")))
+}
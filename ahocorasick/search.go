@@ -0,0 +1,131 @@
+package ahocorasick
+
+import (
+	"io"
+	"iter"
+	"slices"
+)
+
+const readBufferSize = 32 * 1024
+
+// searcher holds the state of a search so that the text can be fed in chunks
+// In LeftmostLongest mode the automaton still finds every match. A match is
+// reported once no partial match starting at or before it is alive, which the
+// depth of the current state tells, and matches overlapping it are dropped.
+type searcher struct {
+	m          *Matcher
+	state      int32
+	offset     int     // Offset of the next byte to feed
+	cursor     int     // End of the last reported match in LeftmostLongest mode
+	candidates []Match // Unreported LeftmostLongest matches, at most one per start
+}
+
+// feed scans chunk and calls yield for every match it settles, it returns false when yield does
+func (s *searcher) feed(chunk []byte, yield func(Match) bool) bool {
+	m := s.m
+	// Track metrics if enabled
+	if m.config.MetricsEnabled {
+		m.bytesCounter.Inc(int64(len(chunk)))
+	}
+
+	for _, b := range chunk {
+		s.state = m.step(s.state, b)
+		s.offset++
+		for t := s.state; t >= 0; t = m.dict[t] {
+			if p := m.output[t]; p >= 0 {
+				match := Match{Pattern: int(p), Start: s.offset - m.lengths[p], End: s.offset}
+				if m.config.MatchKind == Overlapping {
+					if !s.emit(match, yield) {
+						return false
+					}
+				} else {
+					s.candidate(match)
+				}
+			}
+		}
+		if m.config.MatchKind == LeftmostLongest && !s.settle(s.offset-int(m.depth[s.state]), yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// flush reports the matches still pending at the end of the text
+func (s *searcher) flush(yield func(Match) bool) bool {
+	return s.settle(s.offset+1, yield)
+}
+
+// candidate records a LeftmostLongest match unless it overlaps a reported one
+// Matches are found by increasing end, so a match replaces a candidate with the same start.
+func (s *searcher) candidate(match Match) {
+	if match.Start < s.cursor {
+		return
+	}
+	for i, c := range s.candidates {
+		if c.Start == match.Start {
+			if match.End > c.End {
+				s.candidates[i] = match
+			}
+			return
+		}
+	}
+	s.candidates = append(s.candidates, match)
+}
+
+// settle reports the leftmost candidates starting before horizon, the earliest start of a live partial match
+func (s *searcher) settle(horizon int, yield func(Match) bool) bool {
+	for len(s.candidates) > 0 {
+		best := slices.MinFunc(s.candidates, func(a, b Match) int { return a.Start - b.Start })
+		if best.Start >= horizon {
+			return true
+		}
+		s.cursor = best.End
+		s.candidates = slices.DeleteFunc(s.candidates, func(c Match) bool { return c.Start < s.cursor })
+		if !s.emit(best, yield) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *searcher) emit(match Match, yield func(Match) bool) bool {
+	// Track metrics if enabled
+	if s.m.config.MetricsEnabled {
+		s.m.matchesCounter.Inc(1)
+	}
+	return yield(match)
+}
+
+// All returns an iterator over the matches in text
+func (m *Matcher) All(text []byte) iter.Seq[Match] {
+	return func(yield func(Match) bool) {
+		s := &searcher{m: m}
+		_ = s.feed(text, yield) && s.flush(yield)
+	}
+}
+
+// FindAll returns the matches in text
+func (m *Matcher) FindAll(text []byte) []Match {
+	return slices.Collect(m.All(text))
+}
+
+// FindReader streams r through the matcher and calls fn for every match until fn returns false
+// Match offsets count bytes from the start of the stream. Only the partial
+// matches in flight are kept, so memory use does not depend on the stream length.
+func (m *Matcher) FindReader(r io.Reader, fn func(Match) bool) error {
+	s := &searcher{m: m}
+	buf := make([]byte, readBufferSize)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 && !s.feed(buf[:n], fn) {
+			return nil
+		}
+		if err == io.EOF {
+			s.flush(fn)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
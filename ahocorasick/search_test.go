@@ -0,0 +1,170 @@
+package ahocorasick
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// naiveOverlapping lists every occurrence by end offset, longest first
+func naiveOverlapping(patterns []string, text string) []Match {
+	var matches []Match
+	for end := 1; end <= len(text); end++ {
+		for length := end; length > 0; length-- {
+			for i, p := range patterns {
+				if len(p) == length && text[end-length:end] == p {
+					matches = append(matches, Match{Pattern: i, Start: end - length, End: end})
+					break
+				}
+			}
+		}
+	}
+	return matches
+}
+
+// naiveLeftmostLongest repeatedly takes the earliest and then longest occurrence after the previous one
+func naiveLeftmostLongest(patterns []string, text string) []Match {
+	var matches []Match
+	for start := 0; start < len(text); start++ {
+		best := Match{Pattern: -1}
+		for i, p := range patterns {
+			if strings.HasPrefix(text[start:], p) && (best.Pattern < 0 || len(p) > best.End-best.Start) {
+				best = Match{Pattern: i, Start: start, End: start + len(p)}
+			}
+		}
+		if best.Pattern >= 0 {
+			matches = append(matches, best)
+			start = best.End - 1
+		}
+	}
+	return matches
+}
+
+func TestOverlapping(t *testing.T) {
+	m, _ := New([]string{"he", "she", "his", "hers"}, Config{})
+	assert.Equal(t, []Match{
+		{Pattern: 1, Start: 1, End: 4},
+		{Pattern: 0, Start: 2, End: 4},
+		{Pattern: 3, Start: 2, End: 6},
+	}, m.FindAll([]byte("ushers")))
+}
+
+func TestLeftmostLongest(t *testing.T) {
+	m, _ := New([]string{"he", "she", "his", "hers"}, Config{MatchKind: LeftmostLongest})
+	assert.Equal(t, []Match{{Pattern: 1, Start: 1, End: 4}}, m.FindAll([]byte("ushers")))
+
+	// A longer partial match that fails must not hide the shorter matches it contains
+	m, _ = New([]string{"ab", "abcx", "c"}, Config{MatchKind: LeftmostLongest})
+	assert.Equal(t, []Match{
+		{Pattern: 0, Start: 0, End: 2},
+		{Pattern: 2, Start: 2, End: 3},
+	}, m.FindAll([]byte("abcy")))
+
+	m, _ = New([]string{"abcde", "bcd"}, Config{MatchKind: LeftmostLongest})
+	assert.Equal(t, []Match{{Pattern: 1, Start: 1, End: 4}}, m.FindAll([]byte("abcdx")))
+	assert.Equal(t, []Match{{Pattern: 0, Start: 0, End: 5}}, m.FindAll([]byte("abcde")))
+}
+
+func TestRandomized(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	randomString := func(n int) string {
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = byte('a' + rng.IntN(3))
+		}
+		return string(b)
+	}
+
+	for round := 0; round < 200; round++ {
+		patterns := make([]string, 1+rng.IntN(10))
+		for i := range patterns {
+			patterns[i] = randomString(1 + rng.IntN(5))
+		}
+		text := randomString(rng.IntN(200))
+
+		m, err := New(patterns, Config{})
+		assert.NoError(t, err)
+		assert.Equal(t, naiveOverlapping(patterns, text), m.FindAll([]byte(text)))
+
+		m, err = New(patterns, Config{MatchKind: LeftmostLongest})
+		assert.NoError(t, err)
+		assert.Equal(t, naiveLeftmostLongest(patterns, text), m.FindAll([]byte(text)))
+	}
+}
+
+func TestFindReader(t *testing.T) {
+	text := strings.Repeat("INFO ok\nWARN disk\nERROR failed\n", 3000)
+	patterns := []string{"warn", "error", "error failed", "fail"}
+	for _, kind := range []MatchKind{Overlapping, LeftmostLongest} {
+		m, _ := New(patterns, Config{MatchKind: kind, CaseInsensitive: true})
+		expected := m.FindAll([]byte(text))
+
+		var streamed []Match
+		err := m.FindReader(iotest.OneByteReader(strings.NewReader(text)), func(match Match) bool {
+			streamed = append(streamed, match)
+			return true
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, expected, streamed)
+
+		// Across read buffer boundaries
+		streamed = nil
+		err = m.FindReader(bytes.NewReader([]byte(text)), func(match Match) bool {
+			streamed = append(streamed, match)
+			return true
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, expected, streamed)
+	}
+
+	// Stopping early
+	m, _ := New(patterns, Config{})
+	count := 0
+	err := m.FindReader(strings.NewReader(text), func(Match) bool {
+		count++
+		return count < 3
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	failure := errors.New("read failed")
+	err = m.FindReader(iotest.ErrReader(failure), func(Match) bool { return true })
+	assert.Equal(t, failure, err)
+}
+
+func TestMetrics(t *testing.T) {
+	m, _ := New([]string{"a"}, Config{MetricsEnabled: true})
+	m.FindAll([]byte("banana"))
+	assert.Equal(t, int64(6), m.bytesCounter.Count())
+	assert.Equal(t, int64(3), m.matchesCounter.Count())
+}
+
+func benchmarkMatcher(b *testing.B, kind MatchKind) {
+	patterns := make([]string, 5000)
+	for i := range patterns {
+		patterns[i] = fmt.Sprintf("keyword%d", i*7919)
+	}
+	m, _ := New(patterns, Config{MatchKind: kind})
+	text := []byte(strings.Repeat("some log line mentioning keyword15838 and other words\n", 2000))
+
+	b.SetBytes(int64(len(text)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for range m.All(text) {
+		}
+	}
+}
+
+func BenchmarkOverlapping(b *testing.B) {
+	benchmarkMatcher(b, Overlapping)
+}
+
+func BenchmarkLeftmostLongest(b *testing.B) {
+	benchmarkMatcher(b, LeftmostLongest)
+}
@@ -2,6 +2,7 @@ package array
 
 import (
     "errors"
+    "slices"
     "sync"
     "github.com/rcrowley/go-metrics"
 )
@@ -12,13 +13,17 @@ type ArrayConfig struct {
 
 // Array is a generic array structure that can hold elements of any type
 type Array[T any] struct {
-    data           []T
-    size           int
-    mu             sync.RWMutex
-    config         ArrayConfig
-    appendCounter  metrics.Counter
-    getCounter     metrics.Counter
-    resizeCounter  metrics.Counter
+    data            []T
+    size            int
+    shared          bool // data is referenced by a snapshot and must be copied before being modified
+    mu              sync.RWMutex
+    config          ArrayConfig
+    appendCounter   metrics.Counter
+    getCounter      metrics.Counter
+    resizeCounter   metrics.Counter
+    snapshotCounter metrics.Counter
+    copyCounter     metrics.Counter
+    bulkCounter     metrics.Counter
 }
 
 // NewArray creates a new generic array with a fixed capacity
@@ -35,9 +40,15 @@ func NewArray[T any](capacity int, config ArrayConfig) *Array[T] {
         arr.appendCounter = metrics.NewCounter()
         arr.getCounter = metrics.NewCounter()
         arr.resizeCounter = metrics.NewCounter()
+        arr.snapshotCounter = metrics.NewCounter()
+        arr.copyCounter = metrics.NewCounter()
+        arr.bulkCounter = metrics.NewCounter()
         metrics.DefaultRegistry.Register("array.append", arr.appendCounter)
         metrics.DefaultRegistry.Register("array.get", arr.getCounter)
         metrics.DefaultRegistry.Register("array.resize", arr.resizeCounter)
+        metrics.DefaultRegistry.Register("array.snapshot", arr.snapshotCounter)
+        metrics.DefaultRegistry.Register("array.snapshot.copy", arr.copyCounter)
+        metrics.DefaultRegistry.Register("array.bulk", arr.bulkCounter)
     }
 
     return arr
@@ -61,6 +72,31 @@ func (a *Array[T]) Append(value T) error {
     return nil
 }
 
+// Insert adds a new element at the specified index and shifts subsequent elements
+func (a *Array[T]) Insert(index int, value T) error {
+    a.mu.Lock() // Lock for writing
+    defer a.mu.Unlock()
+
+    if index < 0 || index > a.size {
+        return errors.New("index out of bounds")
+    }
+    if a.size >= len(a.data) {
+        return errors.New("array is full")
+    }
+    a.copyIfShared()
+
+    // Shift elements to the right to make room
+    copy(a.data[index+1:a.size+1], a.data[index:a.size])
+    a.data[index] = value
+    a.size++
+
+    // Track metrics if enabled
+    if a.config.MetricsEnabled {
+        a.appendCounter.Inc(1)
+    }
+    return nil
+}
+
 // Get retrieves an element at the specified index
 func (a *Array[T]) Get(index int) (T, error) {
     a.mu.RLock() // Lock for reading
@@ -86,6 +122,24 @@ func (a *Array[T]) Length() int {
     return a.size
 }
 
+// Capacity returns the number of elements the array can hold without resizing
+func (a *Array[T]) Capacity() int {
+    a.mu.RLock() // Lock for reading
+    defer a.mu.RUnlock()
+
+    return len(a.data)
+}
+
+// Values returns a copy of the elements, in order
+// Unlike a snapshot it does not share the backing slice, so later writes do not copy it.
+func (a *Array[T]) Values() []T {
+    a.mu.RLock() // Lock for reading
+    defer a.mu.RUnlock()
+
+    a.countBulk()
+    return slices.Clone(a.data[:a.size])
+}
+
 // Resize resizes the array to a new capacity
 func (a *Array[T]) Resize(newCapacity int) error {
     a.mu.Lock() // Lock for writing
@@ -98,6 +152,7 @@ func (a *Array[T]) Resize(newCapacity int) error {
     newData := make([]T, newCapacity)
     copy(newData, a.data)
     a.data = newData
+    a.shared = false // Snapshots keep the old backing slice
 
     // Track metrics if enabled
     if a.config.MetricsEnabled {
@@ -115,6 +170,7 @@ func (a *Array[T]) Delete(index int) error {
     if index < 0 || index >= a.size {
         return errors.New("index out of bounds")
     }
+    a.copyIfShared()
 
     // Shift elements to the left to fill the gap
     copy(a.data[index:], a.data[index+1:a.size])
@@ -131,3 +187,26 @@ func (a *Array[T]) Delete(index int) error {
 
     return nil
 }
+
+// Replace swaps the elements and capacity of the array for values and newCapacity in a single step
+// Readers see either the old or the new elements, never a mix of both.
+func (a *Array[T]) Replace(values []T, newCapacity int) error {
+    if newCapacity < len(values) {
+        return errors.New("new capacity must be greater than or equal to the current size")
+    }
+    newData := make([]T, newCapacity)
+    copy(newData, values)
+
+    a.mu.Lock() // Lock for writing
+    defer a.mu.Unlock()
+
+    a.data = newData
+    a.size = len(values)
+    a.shared = false // Snapshots keep the old backing slice
+
+    // Track metrics if enabled
+    if a.config.MetricsEnabled {
+        a.resizeCounter.Inc(1)
+    }
+    return nil
+}
@@ -1,7 +1,11 @@
 package array
 
 import (
+	"cmp"
+	"context"
+	"slices"
 	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -51,6 +55,34 @@ func TestAppendFullArray(t *testing.T) {
     assert.Equal(t, "array is full", err.Error())
 }
 
+func TestInsert(t *testing.T) {
+    config := ArrayConfig{MetricsEnabled: false}
+    arr := NewArray[int](4, config)
+
+    arr.Append(10)
+    arr.Append(30)
+
+    // Insert in the middle, at the front and at the end
+    assert.NoError(t, arr.Insert(1, 20))
+    assert.NoError(t, arr.Insert(0, 0))
+    for i := 0; i < 4; i++ {
+        value, err := arr.Get(i)
+        assert.NoError(t, err)
+        assert.Equal(t, i*10, value)
+    }
+
+    err := arr.Insert(4, 40)
+    assert.Error(t, err)
+    assert.Equal(t, "array is full", err.Error())
+
+    arr.Delete(3)
+    err = arr.Insert(4, 40)
+    assert.Error(t, err)
+    assert.Equal(t, "index out of bounds", err.Error())
+    assert.NoError(t, arr.Insert(3, 30))
+    assert.Equal(t, 4, arr.Length())
+}
+
 func TestGet(t *testing.T) {
     config := ArrayConfig{MetricsEnabled: false}
     arr := NewArray[int](5, config)
@@ -99,6 +131,37 @@ func TestLength(t *testing.T) {
     assert.Equal(t, 5, arr.Length())
 }
 
+func TestCapacity(t *testing.T) {
+    config := ArrayConfig{MetricsEnabled: false}
+    arr := NewArray[int](5, config)
+
+    // Capacity is independent of the number of elements
+    arr.Append(10)
+    assert.Equal(t, 5, arr.Capacity())
+
+    arr.Resize(8)
+    assert.Equal(t, 8, arr.Capacity())
+}
+
+func TestValues(t *testing.T) {
+    config := ArrayConfig{MetricsEnabled: true}
+    arr := NewArray[int](4, config)
+    assert.Empty(t, arr.Values())
+
+    arr.Append(1)
+    arr.Append(2)
+    arr.Append(3)
+    values := arr.Values()
+    assert.Equal(t, []int{1, 2, 3}, values)
+
+    // The copy is not shared with the array, so writes do not copy the data
+    values[0] = 10
+    arr.Delete(0)
+    assert.Equal(t, []int{2, 3}, arr.Values())
+    assert.Equal(t, int64(0), arr.copyCounter.Count())
+    assert.Equal(t, int64(0), arr.snapshotCounter.Count())
+}
+
 func TestResize(t *testing.T) {
     config := ArrayConfig{MetricsEnabled: false}
     arr := NewArray[int](5, config)
@@ -138,6 +201,31 @@ func TestResize(t *testing.T) {
     assert.Equal(t, "new capacity must be greater than or equal to the current size", err.Error())
 }
 
+func TestReplace(t *testing.T) {
+    config := ArrayConfig{MetricsEnabled: false}
+    arr := NewArray[int](2, config)
+    arr.Append(10)
+    snap := arr.Snapshot()
+
+    // Replace the elements and the capacity at once
+    err := arr.Replace([]int{1, 2, 3}, 4)
+    assert.NoError(t, err)
+    assert.Equal(t, 3, arr.Length())
+    assert.Equal(t, 4, arr.Capacity())
+    value, _ := arr.Get(2)
+    assert.Equal(t, 3, value)
+
+    // Snapshots keep the elements they were taken with
+    value, _ = snap.Get(0)
+    assert.Equal(t, 10, value)
+
+    // The capacity must hold the elements
+    err = arr.Replace([]int{1, 2, 3}, 2)
+    assert.Error(t, err)
+    assert.Equal(t, "new capacity must be greater than or equal to the current size", err.Error())
+    assert.Equal(t, 3, arr.Length())
+}
+
 func TestDelete(t *testing.T) {
     config := ArrayConfig{MetricsEnabled: false}
     arr := NewArray[int](5, config)
@@ -248,6 +336,80 @@ func BenchmarkGetConcurrent(b *testing.B) {
     wg.Wait()
 }
 
+// parallelBenchmarkSize is the number of elements of the arrays used by the parallel benchmarks
+const parallelBenchmarkSize = 1 << 22
+
+// BenchmarkParallelMap benchmarks ParallelMap against the sequential Map.
+func BenchmarkParallelMap(b *testing.B) {
+    arr := randomArray(parallelBenchmarkSize, ArrayConfig{MetricsEnabled: false})
+    double := func(v int) int { return v * 2 }
+
+    b.Run("Sequential", func(b *testing.B) {
+        for i := 0; i < b.N; i++ {
+            Map(arr, double)
+        }
+    })
+    b.Run("Parallel", func(b *testing.B) {
+        for i := 0; i < b.N; i++ {
+            ParallelMap(context.Background(), arr, double, ParallelConfig{})
+        }
+    })
+}
+
+// BenchmarkParallelReduce benchmarks ParallelReduce against the sequential Reduce.
+func BenchmarkParallelReduce(b *testing.B) {
+    arr := randomArray(parallelBenchmarkSize, ArrayConfig{MetricsEnabled: false})
+    sum := func(acc, v int) int { return acc + v }
+
+    b.Run("Sequential", func(b *testing.B) {
+        for i := 0; i < b.N; i++ {
+            Reduce(arr, 0, sum)
+        }
+    })
+    b.Run("Parallel", func(b *testing.B) {
+        for i := 0; i < b.N; i++ {
+            ParallelReduce(context.Background(), arr, 0, sum, sum, ParallelConfig{})
+        }
+    })
+}
+
+// BenchmarkParallelForEach benchmarks visiting every element with ParallelForEach.
+func BenchmarkParallelForEach(b *testing.B) {
+    arr := randomArray(parallelBenchmarkSize, ArrayConfig{MetricsEnabled: false})
+    var total atomic.Int64
+
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        ParallelForEach(context.Background(), arr, func(_ int, v int) {
+            if v == 0 {
+                total.Add(1)
+            }
+        }, ParallelConfig{})
+    }
+}
+
+// BenchmarkParallelSort benchmarks ParallelSort against the sequential SortStable.
+func BenchmarkParallelSort(b *testing.B) {
+    unsorted := elementsOf(randomArray(parallelBenchmarkSize, ArrayConfig{MetricsEnabled: false}))
+
+    b.Run("Sequential", func(b *testing.B) {
+        for i := 0; i < b.N; i++ {
+            b.StopTimer()
+            arr := newArrayOf(slices.Clone(unsorted), ArrayConfig{})
+            b.StartTimer()
+            SortStable(arr, cmp.Compare[int])
+        }
+    })
+    b.Run("Parallel", func(b *testing.B) {
+        for i := 0; i < b.N; i++ {
+            b.StopTimer()
+            arr := newArrayOf(slices.Clone(unsorted), ArrayConfig{})
+            b.StartTimer()
+            ParallelSort(context.Background(), arr, cmp.Compare[int], ParallelConfig{})
+        }
+    })
+}
+
 // BenchmarkDelete benchmarks the Delete method for deleting an element at a specific index.
 func BenchmarkDelete(b *testing.B) {
     config := ArrayConfig{MetricsEnabled: false}
@@ -0,0 +1,165 @@
+package array
+
+import (
+    "math/rand/v2"
+    "slices"
+)
+
+// The functions below work on the whole array under a single lock acquisition
+// instead of one per element, and count as a single array.bulk event. The
+// callbacks run while the lock is held and must not call back into the array.
+
+// Map returns a new array holding fn applied to every element
+func Map[T, U any](a *Array[T], fn func(T) U) *Array[U] {
+    a.mu.RLock() // Lock for reading
+    defer a.mu.RUnlock()
+
+    mapped := make([]U, a.size)
+    for i, v := range a.data[:a.size] {
+        mapped[i] = fn(v)
+    }
+    a.countBulk()
+    return newArrayOf(mapped, a.config)
+}
+
+// Filter returns a new array holding the elements for which keep returns true, in order
+func Filter[T any](a *Array[T], keep func(T) bool) *Array[T] {
+    a.mu.RLock() // Lock for reading
+    defer a.mu.RUnlock()
+
+    var kept []T
+    for _, v := range a.data[:a.size] {
+        if keep(v) {
+            kept = append(kept, v)
+        }
+    }
+    a.countBulk()
+    return newArrayOf(kept, a.config)
+}
+
+// Reduce folds the elements in order into an accumulator starting at initial
+func Reduce[T, U any](a *Array[T], initial U, fn func(U, T) U) U {
+    a.mu.RLock() // Lock for reading
+    defer a.mu.RUnlock()
+
+    acc := initial
+    for _, v := range a.data[:a.size] {
+        acc = fn(acc, v)
+    }
+    a.countBulk()
+    return acc
+}
+
+// Partition returns two new arrays, the elements for which pred returns true and the others, both in order
+func Partition[T any](a *Array[T], pred func(T) bool) (*Array[T], *Array[T]) {
+    a.mu.RLock() // Lock for reading
+    defer a.mu.RUnlock()
+
+    var matching, rest []T
+    for _, v := range a.data[:a.size] {
+        if pred(v) {
+            matching = append(matching, v)
+        } else {
+            rest = append(rest, v)
+        }
+    }
+    a.countBulk()
+    return newArrayOf(matching, a.config), newArrayOf(rest, a.config)
+}
+
+// GroupBy returns a new array per key holding the elements key maps to it, in order
+func GroupBy[T any, K comparable](a *Array[T], key func(T) K) map[K]*Array[T] {
+    a.mu.RLock() // Lock for reading
+    defer a.mu.RUnlock()
+
+    groups := make(map[K][]T)
+    for _, v := range a.data[:a.size] {
+        k := key(v)
+        groups[k] = append(groups[k], v)
+    }
+    a.countBulk()
+
+    arrays := make(map[K]*Array[T], len(groups))
+    for k, values := range groups {
+        arrays[k] = newArrayOf(values, a.config)
+    }
+    return arrays
+}
+
+// Contains reports whether value is in the array
+func Contains[T comparable](a *Array[T], value T) bool {
+    return IndexOf(a, value) >= 0
+}
+
+// IndexOf returns the index of the first element equal to value, or -1 if there is none
+func IndexOf[T comparable](a *Array[T], value T) int {
+    a.mu.RLock() // Lock for reading
+    defer a.mu.RUnlock()
+
+    a.countBulk()
+    return slices.Index(a.data[:a.size], value)
+}
+
+// Sort sorts the array in place in the order defined by compare, as for slices.SortFunc
+func Sort[T any](a *Array[T], compare func(x, y T) int) {
+    a.mu.Lock() // Lock for writing
+    defer a.mu.Unlock()
+
+    a.copyIfShared()
+    slices.SortFunc(a.data[:a.size], compare)
+    a.countBulk()
+}
+
+// SortStable sorts the array in place like Sort, keeping the order of equal elements
+func SortStable[T any](a *Array[T], compare func(x, y T) int) {
+    a.mu.Lock() // Lock for writing
+    defer a.mu.Unlock()
+
+    a.copyIfShared()
+    slices.SortStableFunc(a.data[:a.size], compare)
+    a.countBulk()
+}
+
+// Reverse reverses the order of the elements in place
+func Reverse[T any](a *Array[T]) {
+    a.mu.Lock() // Lock for writing
+    defer a.mu.Unlock()
+
+    a.copyIfShared()
+    slices.Reverse(a.data[:a.size])
+    a.countBulk()
+}
+
+// Shuffle randomizes the order of the elements in place using rng
+// A nil rng uses the global source of math/rand/v2.
+func Shuffle[T any](a *Array[T], rng *rand.Rand) {
+    a.mu.Lock() // Lock for writing
+    defer a.mu.Unlock()
+
+    a.copyIfShared()
+    data := a.data[:a.size]
+    swap := func(i, j int) { data[i], data[j] = data[j], data[i] }
+    if rng != nil {
+        rng.Shuffle(len(data), swap)
+    } else {
+        rand.Shuffle(len(data), swap)
+    }
+    a.countBulk()
+}
+
+// newArrayOf wraps data in a full array
+func newArrayOf[T any](data []T, config ArrayConfig) *Array[T] {
+    arr := NewArray[T](0, config)
+    arr.data = data
+    arr.size = len(data)
+    return arr
+}
+
+// countBulk tracks a bulk operation
+// The caller must hold the lock
+func (a *Array[T]) countBulk() {
+    // Track metrics if enabled
+    if a.config.MetricsEnabled {
+        a.bulkCounter.Inc(1)
+    }
+}
@@ -0,0 +1,214 @@
+package array
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// elementsOf returns the elements of an array
+func elementsOf[T any](a *Array[T]) []T {
+    var result []T
+    for _, v := range a.Snapshot().All() {
+        result = append(result, v)
+    }
+    return result
+}
+
+// filled creates an array holding the given elements
+func filled[T any](config ArrayConfig, elements ...T) *Array[T] {
+    arr := NewArray[T](len(elements), config)
+    for _, v := range elements {
+        arr.Append(v)
+    }
+    return arr
+}
+
+func TestMap(t *testing.T) {
+    arr := filled(ArrayConfig{}, 1, 2, 3)
+
+    mapped := Map(arr, strconv.Itoa)
+    assert.Equal(t, []string{"1", "2", "3"}, elementsOf(mapped))
+    assert.Equal(t, 3, mapped.Capacity())
+
+    // The source array is left untouched
+    assert.Equal(t, []int{1, 2, 3}, elementsOf(arr))
+
+    empty := Map(NewArray[int](4, ArrayConfig{}), strconv.Itoa)
+    assert.Equal(t, 0, empty.Length())
+}
+
+func TestFilter(t *testing.T) {
+    arr := filled(ArrayConfig{}, 1, 2, 3, 4, 5)
+
+    even := Filter(arr, func(v int) bool { return v%2 == 0 })
+    assert.Equal(t, []int{2, 4}, elementsOf(even))
+
+    none := Filter(arr, func(v int) bool { return v > 10 })
+    assert.Equal(t, 0, none.Length())
+    assert.Error(t, none.Append(1))
+}
+
+func TestReduce(t *testing.T) {
+    arr := filled(ArrayConfig{}, "a", "b", "c")
+
+    joined := Reduce(arr, ">", func(acc string, v string) string { return acc + v })
+    assert.Equal(t, ">abc", joined)
+
+    total := Reduce(filled(ArrayConfig{}, 1, 2, 3, 4), 0, func(acc, v int) int { return acc + v })
+    assert.Equal(t, 10, total)
+}
+
+func TestPartition(t *testing.T) {
+    arr := filled(ArrayConfig{}, 5, 1, 4, 2, 3)
+
+    small, large := Partition(arr, func(v int) bool { return v < 3 })
+    assert.Equal(t, []int{1, 2}, elementsOf(small))
+    assert.Equal(t, []int{5, 4, 3}, elementsOf(large))
+}
+
+func TestGroupBy(t *testing.T) {
+    arr := filled(ArrayConfig{}, "apple", "avocado", "banana", "cherry", "blueberry")
+
+    groups := GroupBy(arr, func(v string) byte { return v[0] })
+    assert.Len(t, groups, 3)
+    assert.Equal(t, []string{"apple", "avocado"}, elementsOf(groups['a']))
+    assert.Equal(t, []string{"banana", "blueberry"}, elementsOf(groups['b']))
+    assert.Equal(t, []string{"cherry"}, elementsOf(groups['c']))
+}
+
+func TestContainsIndexOf(t *testing.T) {
+    arr := filled(ArrayConfig{}, 10, 20, 30, 20)
+
+    assert.True(t, Contains(arr, 30))
+    assert.False(t, Contains(arr, 40))
+    assert.Equal(t, 1, IndexOf(arr, 20))
+    assert.Equal(t, -1, IndexOf(arr, 40))
+
+    // Elements past the length are not considered
+    arr.Delete(3)
+    arr.Delete(2)
+    assert.False(t, Contains(arr, 30))
+}
+
+func TestSort(t *testing.T) {
+    arr := filled(ArrayConfig{}, 3, 1, 2)
+    Sort(arr, cmp.Compare[int])
+    assert.Equal(t, []int{1, 2, 3}, elementsOf(arr))
+
+    Sort(arr, func(x, y int) int { return cmp.Compare(y, x) })
+    assert.Equal(t, []int{3, 2, 1}, elementsOf(arr))
+}
+
+func TestSortStable(t *testing.T) {
+    type pair struct {
+        key   int
+        label string
+    }
+    arr := filled(ArrayConfig{}, pair{2, "a"}, pair{1, "b"}, pair{2, "c"}, pair{1, "d"})
+
+    SortStable(arr, func(x, y pair) int { return cmp.Compare(x.key, y.key) })
+    assert.Equal(t, []pair{{1, "b"}, {1, "d"}, {2, "a"}, {2, "c"}}, elementsOf(arr))
+}
+
+func TestReverse(t *testing.T) {
+    arr := NewArray[int](5, ArrayConfig{})
+    arr.Append(1)
+    arr.Append(2)
+    arr.Append(3)
+
+    Reverse(arr)
+    assert.Equal(t, []int{3, 2, 1}, elementsOf(arr))
+    assert.Equal(t, 5, arr.Capacity())
+}
+
+func TestShuffle(t *testing.T) {
+    arr := filled(ArrayConfig{}, 1, 2, 3, 4, 5, 6, 7, 8)
+    other := filled(ArrayConfig{}, 1, 2, 3, 4, 5, 6, 7, 8)
+
+    // The same seed gives the same permutation
+    Shuffle(arr, rand.New(rand.NewPCG(1, 2)))
+    Shuffle(other, rand.New(rand.NewPCG(1, 2)))
+    assert.Equal(t, elementsOf(arr), elementsOf(other))
+    assert.ElementsMatch(t, []int{1, 2, 3, 4, 5, 6, 7, 8}, elementsOf(arr))
+    assert.NotEqual(t, []int{1, 2, 3, 4, 5, 6, 7, 8}, elementsOf(arr))
+
+    Shuffle(arr, nil)
+    assert.ElementsMatch(t, []int{1, 2, 3, 4, 5, 6, 7, 8}, elementsOf(arr))
+}
+
+func TestBulkSnapshot(t *testing.T) {
+    arr := filled(ArrayConfig{}, 3, 1, 2)
+    snap := arr.Snapshot()
+
+    // In-place operations copy the data a snapshot refers to first
+    Sort(arr, cmp.Compare[int])
+    Reverse(arr)
+    assert.Equal(t, []int{3, 2, 1}, elementsOf(arr))
+
+    var seen []int
+    for _, v := range snap.All() {
+        seen = append(seen, v)
+    }
+    assert.Equal(t, []int{3, 1, 2}, seen)
+}
+
+func TestBulkMetrics(t *testing.T) {
+    config := ArrayConfig{MetricsEnabled: true}
+    arr := filled(config, 1, 2, 3, 4)
+    gets := arr.getCounter.Count()
+
+    // Every call counts once, whatever the number of elements
+    Map(arr, func(v int) int { return v * 2 })
+    Filter(arr, func(v int) bool { return v > 2 })
+    Reduce(arr, 0, func(acc, v int) int { return acc + v })
+    Partition(arr, func(v int) bool { return v > 2 })
+    GroupBy(arr, func(v int) int { return v % 2 })
+    Contains(arr, 3)
+    IndexOf(arr, 3)
+    Sort(arr, cmp.Compare[int])
+    SortStable(arr, cmp.Compare[int])
+    Reverse(arr)
+    Shuffle(arr, nil)
+    assert.Equal(t, int64(11), arr.bulkCounter.Count())
+    assert.Equal(t, gets, arr.getCounter.Count())
+}
+
+// BenchmarkMap benchmarks mapping a whole array under a single lock.
+func BenchmarkMap(b *testing.B) {
+    config := ArrayConfig{MetricsEnabled: false}
+    arr := NewArray[int](1000, config)
+
+    // Pre-fill the array
+    for i := 0; i < 1000; i++ {
+        arr.Append(i)
+    }
+
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        Map(arr, func(v int) int { return v * 2 })
+    }
+}
+
+// BenchmarkMapGet benchmarks the equivalent of Map done with one Get per element.
+func BenchmarkMapGet(b *testing.B) {
+    config := ArrayConfig{MetricsEnabled: false}
+    arr := NewArray[int](1000, config)
+
+    // Pre-fill the array
+    for i := 0; i < 1000; i++ {
+        arr.Append(i)
+    }
+
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        mapped := NewArray[int](1000, config)
+        for j := 0; j < arr.Length(); j++ {
+            v, _ := arr.Get(j)
+            mapped.Append(v * 2)
+        }
+    }
+}
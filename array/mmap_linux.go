@@ -0,0 +1,309 @@
+package array
+
+import (
+    "encoding/binary"
+    "errors"
+    "os"
+    "reflect"
+    "sync"
+    "unsafe"
+
+    "github.com/rcrowley/go-metrics"
+    "golang.org/x/sys/unix"
+)
+
+const (
+    mmapMagic = "AMAP"
+    // Header layout: magic(4) elementSize(4) length(8), padded so that elements are aligned
+    mmapHeaderLength = 64
+)
+
+// MMap is an array of plain-old-data elements stored in a memory-mapped file
+// T must not contain pointers, strings, slices, maps, channels, funcs or
+// interfaces, since its memory is written to the file as is. The file uses the
+// native byte order and is only portable between machines sharing it.
+type MMap[T any] struct {
+    file          *os.File
+    mapping       []byte // Header followed by the elements
+    data          []T    // Elements, aliasing mapping
+    mu            sync.RWMutex
+    config        ArrayConfig
+    appendCounter metrics.Counter
+    getCounter    metrics.Counter
+    resizeCounter metrics.Counter
+    syncCounter   metrics.Counter
+}
+
+// OpenMMap opens the array stored in the file at path, creating it with the given capacity if it does not exist
+// The capacity of an existing file is kept.
+func OpenMMap[T any](path string, capacity int, config ArrayConfig) (*MMap[T], error) {
+    var zero T
+    elementSize := int(unsafe.Sizeof(zero))
+    if elementSize == 0 || !isPlainData(reflect.TypeOf(zero)) {
+        return nil, errors.New("element type must be fixed-size plain data")
+    }
+    if capacity < 0 {
+        return nil, errors.New("capacity must not be negative")
+    }
+
+    file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+    if err != nil {
+        return nil, err
+    }
+    info, err := file.Stat()
+    if err != nil {
+        file.Close()
+        return nil, err
+    }
+
+    created := info.Size() == 0
+    size := info.Size()
+    if created {
+        size = int64(mmapHeaderLength + capacity*elementSize)
+        if err := file.Truncate(size); err != nil {
+            file.Close()
+            return nil, err
+        }
+    } else if size < mmapHeaderLength || (size-mmapHeaderLength)%int64(elementSize) != 0 {
+        file.Close()
+        return nil, errors.New("invalid mmap file")
+    }
+
+    mapping, err := unix.Mmap(int(file.Fd()), 0, int(size), unix.PROT_READ|unix.PROT_WRITE, unix.MAP_SHARED)
+    if err != nil {
+        file.Close()
+        return nil, err
+    }
+    if created {
+        copy(mapping, mmapMagic)
+        binary.NativeEndian.PutUint32(mapping[4:], uint32(elementSize))
+    } else if string(mapping[:4]) != mmapMagic || int(binary.NativeEndian.Uint32(mapping[4:])) != elementSize {
+        unix.Munmap(mapping)
+        file.Close()
+        return nil, errors.New("invalid mmap file")
+    }
+
+    m := &MMap[T]{file: file, config: config}
+    m.setMapping(mapping)
+    if m.length() > len(m.data) {
+        m.Close()
+        return nil, errors.New("invalid mmap file")
+    }
+
+    // Initialize the metrics only if enabled in the config
+    if m.config.MetricsEnabled {
+        m.appendCounter = metrics.NewCounter()
+        m.getCounter = metrics.NewCounter()
+        m.resizeCounter = metrics.NewCounter()
+        m.syncCounter = metrics.NewCounter()
+        metrics.DefaultRegistry.Register("array.mmap.append", m.appendCounter)
+        metrics.DefaultRegistry.Register("array.mmap.get", m.getCounter)
+        metrics.DefaultRegistry.Register("array.mmap.resize", m.resizeCounter)
+        metrics.DefaultRegistry.Register("array.mmap.sync", m.syncCounter)
+    }
+
+    return m, nil
+}
+
+// isPlainData reports whether values of type t can be copied to a file byte for byte
+func isPlainData(t reflect.Type) bool {
+    switch t.Kind() {
+    case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+        reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+        reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+        return true
+    case reflect.Array:
+        return isPlainData(t.Elem())
+    case reflect.Struct:
+        for i := 0; i < t.NumField(); i++ {
+            if !isPlainData(t.Field(i).Type) {
+                return false
+            }
+        }
+        return true
+    }
+    return false
+}
+
+func (m *MMap[T]) setMapping(mapping []byte) {
+    var zero T
+    m.mapping = mapping
+    capacity := (len(mapping) - mmapHeaderLength) / int(unsafe.Sizeof(zero))
+    m.data = unsafe.Slice((*T)(unsafe.Add(unsafe.Pointer(unsafe.SliceData(mapping)), mmapHeaderLength)), capacity)
+}
+
+// length reads the number of elements stored in the header
+func (m *MMap[T]) length() int {
+    return int(binary.NativeEndian.Uint64(m.mapping[8:]))
+}
+
+func (m *MMap[T]) setLength(length int) {
+    binary.NativeEndian.PutUint64(m.mapping[8:], uint64(length))
+}
+
+// Append adds a new element to the array
+func (m *MMap[T]) Append(value T) error {
+    m.mu.Lock() // Lock for writing
+    defer m.mu.Unlock()
+
+    if m.mapping == nil {
+        return os.ErrClosed
+    }
+    size := m.length()
+    if size >= len(m.data) {
+        return errors.New("array is full")
+    }
+    m.data[size] = value
+    m.setLength(size + 1)
+
+    // Track metrics if enabled
+    if m.config.MetricsEnabled {
+        m.appendCounter.Inc(1)
+    }
+    return nil
+}
+
+// Get retrieves an element at the specified index
+func (m *MMap[T]) Get(index int) (T, error) {
+    m.mu.RLock() // Lock for reading
+    defer m.mu.RUnlock()
+
+    if m.mapping == nil {
+        var zero T
+        return zero, os.ErrClosed
+    }
+    if index < 0 || index >= m.length() {
+        var zero T // Return a zero value of type T
+        return zero, errors.New("index out of bounds")
+    }
+
+    // Track metrics if enabled
+    if m.config.MetricsEnabled {
+        m.getCounter.Inc(1)
+    }
+    return m.data[index], nil
+}
+
+// Length returns the current number of elements in the array
+func (m *MMap[T]) Length() int {
+    m.mu.RLock() // Lock for reading
+    defer m.mu.RUnlock()
+
+    if m.mapping == nil {
+        return 0
+    }
+    return m.length()
+}
+
+// Capacity returns the number of elements the file can hold without resizing
+func (m *MMap[T]) Capacity() int {
+    m.mu.RLock() // Lock for reading
+    defer m.mu.RUnlock()
+
+    return len(m.data)
+}
+
+// Resize changes the capacity of the array, growing or shrinking the file
+func (m *MMap[T]) Resize(newCapacity int) error {
+    m.mu.Lock() // Lock for writing
+    defer m.mu.Unlock()
+
+    if m.mapping == nil {
+        return os.ErrClosed
+    }
+    if newCapacity < m.length() {
+        return errors.New("new capacity must be greater than or equal to the current size")
+    }
+
+    var zero T
+    size := mmapHeaderLength + newCapacity*int(unsafe.Sizeof(zero))
+    // Grow the file before the mapping and shrink it after, so that no mapped page is ever past the end of the file
+    if size > len(m.mapping) {
+        if err := m.file.Truncate(int64(size)); err != nil {
+            return err
+        }
+    }
+    mapping, err := unix.Mremap(m.mapping, size, unix.MREMAP_MAYMOVE)
+    if err != nil {
+        return err
+    }
+    m.setMapping(mapping)
+    if err := m.file.Truncate(int64(size)); err != nil {
+        return err
+    }
+
+    // Track metrics if enabled
+    if m.config.MetricsEnabled {
+        m.resizeCounter.Inc(1)
+    }
+
+    return nil
+}
+
+// Delete removes the element at the specified index and shifts subsequent elements
+func (m *MMap[T]) Delete(index int) error {
+    m.mu.Lock() // Lock for writing
+    defer m.mu.Unlock()
+
+    if m.mapping == nil {
+        return os.ErrClosed
+    }
+    size := m.length()
+    if index < 0 || index >= size {
+        return errors.New("index out of bounds")
+    }
+
+    // Shift elements to the left to fill the gap
+    copy(m.data[index:], m.data[index+1:size])
+
+    // Clear the last element and reduce size
+    var zero T
+    m.data[size-1] = zero
+    m.setLength(size - 1)
+
+    // Track metrics if enabled
+    if m.config.MetricsEnabled {
+        m.resizeCounter.Inc(1)
+    }
+
+    return nil
+}
+
+// Sync flushes the mapped file to stable storage
+func (m *MMap[T]) Sync() error {
+    m.mu.RLock() // Lock for reading, msync does not modify the mapping
+    defer m.mu.RUnlock()
+
+    if m.mapping == nil {
+        return os.ErrClosed
+    }
+    if err := unix.Msync(m.mapping, unix.MS_SYNC); err != nil {
+        return err
+    }
+
+    // Track metrics if enabled
+    if m.config.MetricsEnabled {
+        m.syncCounter.Inc(1)
+    }
+    return nil
+}
+
+// Close flushes and unmaps the file
+// The array must not be used afterwards.
+func (m *MMap[T]) Close() error {
+    m.mu.Lock() // Lock for writing
+    defer m.mu.Unlock()
+
+    if m.mapping == nil {
+        return nil
+    }
+    err := unix.Msync(m.mapping, unix.MS_SYNC)
+    if unmapErr := unix.Munmap(m.mapping); err == nil {
+        err = unmapErr
+    }
+    m.mapping, m.data = nil, nil
+    if closeErr := m.file.Close(); err == nil {
+        err = closeErr
+    }
+    return err
+}
@@ -0,0 +1,146 @@
+package array
+
+import (
+    "os"
+    "path/filepath"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+)
+
+type record struct {
+    ID    uint64
+    Score float64
+    Tag   [8]byte
+}
+
+func TestMMapAppendGet(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "records")
+    arr, err := OpenMMap[record](path, 3, ArrayConfig{MetricsEnabled: false})
+    assert.NoError(t, err)
+    defer arr.Close()
+
+    for i := 0; i < 3; i++ {
+        err = arr.Append(record{ID: uint64(i), Score: float64(i) / 2})
+        assert.NoError(t, err)
+    }
+    assert.Error(t, arr.Append(record{}))
+    assert.Equal(t, 3, arr.Length())
+    assert.Equal(t, 3, arr.Capacity())
+
+    value, err := arr.Get(2)
+    assert.NoError(t, err)
+    assert.Equal(t, record{ID: 2, Score: 1}, value)
+    _, err = arr.Get(3)
+    assert.Error(t, err)
+}
+
+func TestMMapReopen(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "ints")
+    arr, err := OpenMMap[int64](path, 4, ArrayConfig{})
+    assert.NoError(t, err)
+    for i := int64(1); i <= 4; i++ {
+        assert.NoError(t, arr.Append(i*10))
+    }
+    assert.NoError(t, arr.Delete(1))
+    assert.NoError(t, arr.Sync())
+    assert.NoError(t, arr.Close())
+    assert.NoError(t, arr.Close()) // Closing twice is harmless
+    assert.ErrorIs(t, arr.Append(1), os.ErrClosed)
+
+    // The capacity argument is ignored for an existing file
+    arr, err = OpenMMap[int64](path, 100, ArrayConfig{})
+    assert.NoError(t, err)
+    defer arr.Close()
+    assert.Equal(t, 4, arr.Capacity())
+    assert.Equal(t, 3, arr.Length())
+    for i, expected := range []int64{10, 30, 40} {
+        value, err := arr.Get(i)
+        assert.NoError(t, err)
+        assert.Equal(t, expected, value)
+    }
+}
+
+func TestMMapResize(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "ints")
+    arr, err := OpenMMap[int32](path, 2, ArrayConfig{})
+    assert.NoError(t, err)
+    defer arr.Close()
+
+    assert.NoError(t, arr.Append(1))
+    assert.NoError(t, arr.Append(2))
+
+    // Grow well past a page so that the mapping has to move
+    assert.NoError(t, arr.Resize(100000))
+    info, err := os.Stat(path)
+    assert.NoError(t, err)
+    assert.Equal(t, int64(mmapHeaderLength+100000*4), info.Size())
+    for i := int32(3); i <= 100000; i++ {
+        assert.NoError(t, arr.Append(i))
+    }
+    value, err := arr.Get(99999)
+    assert.NoError(t, err)
+    assert.Equal(t, int32(100000), value)
+
+    // Shrinking below the length fails, down to it works
+    assert.Error(t, arr.Resize(10))
+    for arr.Length() > 10 {
+        assert.NoError(t, arr.Delete(arr.Length()-1))
+    }
+    assert.NoError(t, arr.Resize(10))
+    assert.Equal(t, 10, arr.Capacity())
+    value, err = arr.Get(1)
+    assert.NoError(t, err)
+    assert.Equal(t, int32(2), value)
+}
+
+func TestMMapInvalid(t *testing.T) {
+    dir := t.TempDir()
+
+    // Types holding pointers cannot be stored in a file
+    _, err := OpenMMap[string](filepath.Join(dir, "strings"), 1, ArrayConfig{})
+    assert.Error(t, err)
+    _, err = OpenMMap[struct{ P *int }](filepath.Join(dir, "pointers"), 1, ArrayConfig{})
+    assert.Error(t, err)
+
+    // A file written with another element size is rejected
+    path := filepath.Join(dir, "ints")
+    arr, err := OpenMMap[int64](path, 2, ArrayConfig{})
+    assert.NoError(t, err)
+    assert.NoError(t, arr.Close())
+    _, err = OpenMMap[int32](path, 2, ArrayConfig{})
+    assert.Error(t, err)
+
+    garbage := filepath.Join(dir, "garbage")
+    assert.NoError(t, os.WriteFile(garbage, make([]byte, mmapHeaderLength+8), 0o644))
+    _, err = OpenMMap[int64](garbage, 1, ArrayConfig{})
+    assert.Error(t, err)
+}
+
+func TestMMapMetrics(t *testing.T) {
+    arr, err := OpenMMap[int](filepath.Join(t.TempDir(), "ints"), 2, ArrayConfig{MetricsEnabled: true})
+    assert.NoError(t, err)
+    defer arr.Close()
+
+    arr.Append(1)
+    arr.Get(0)
+    arr.Resize(4)
+    arr.Sync()
+    assert.Equal(t, int64(1), arr.appendCounter.Count())
+    assert.Equal(t, int64(1), arr.getCounter.Count())
+    assert.Equal(t, int64(1), arr.resizeCounter.Count())
+    assert.Equal(t, int64(1), arr.syncCounter.Count())
+}
+
+func BenchmarkMMapAppend(b *testing.B) {
+    arr, err := OpenMMap[record](filepath.Join(b.TempDir(), "records"), b.N, ArrayConfig{})
+    if err != nil {
+        b.Fatal(err)
+    }
+    defer arr.Close()
+
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        arr.Append(record{ID: uint64(i)})
+    }
+}
@@ -0,0 +1,200 @@
+package array
+
+import (
+    "context"
+    "runtime"
+    "slices"
+    "sync"
+    "sync/atomic"
+)
+
+const defaultChunkSize = 1 << 14
+
+type ParallelConfig struct {
+    // Workers is the number of goroutines sharing the work (default GOMAXPROCS)
+    Workers int
+    // ChunkSize is the number of elements a goroutine processes at a time (default 16384)
+    ChunkSize int
+}
+
+// withDefaults returns the config with the unset fields set to their defaults
+func (c ParallelConfig) withDefaults() ParallelConfig {
+    if c.Workers <= 0 {
+        c.Workers = runtime.GOMAXPROCS(0)
+    }
+    if c.ChunkSize <= 0 {
+        c.ChunkSize = defaultChunkSize
+    }
+    return c
+}
+
+// The parallel functions below split the array into chunks processed by a
+// pool of goroutines. Like the sequential bulk operations they hold the lock
+// of the array for the whole call and count as a single array.bulk event.
+// They stop taking new chunks once ctx is done and then return its error.
+
+// ParallelMap returns a new array holding fn applied to every element
+// fn is called concurrently from several goroutines.
+func ParallelMap[T, U any](ctx context.Context, a *Array[T], fn func(T) U, config ParallelConfig) (*Array[U], error) {
+    a.mu.RLock() // Lock for reading
+    defer a.mu.RUnlock()
+
+    data := a.data[:a.size]
+    mapped := make([]U, len(data))
+    err := forEachChunk(ctx, len(data), config, func(lo, hi int) {
+        for i := lo; i < hi; i++ {
+            mapped[i] = fn(data[i])
+        }
+    })
+    if err != nil {
+        return nil, err
+    }
+    a.countBulk()
+    return newArrayOf(mapped, a.config), nil
+}
+
+// ParallelReduce folds every chunk into an accumulator starting at initial, then folds the chunk results in order with combine
+// initial must be an identity for combine, and combine must be associative,
+// for the result to match Reduce.
+func ParallelReduce[T, U any](ctx context.Context, a *Array[T], initial U, fn func(U, T) U, combine func(U, U) U, config ParallelConfig) (U, error) {
+    a.mu.RLock() // Lock for reading
+    defer a.mu.RUnlock()
+
+    config = config.withDefaults()
+    data := a.data[:a.size]
+    partial := make([]U, chunks(len(data), config.ChunkSize))
+    err := forEachChunk(ctx, len(data), config, func(lo, hi int) {
+        acc := initial
+        for _, v := range data[lo:hi] {
+            acc = fn(acc, v)
+        }
+        partial[lo/config.ChunkSize] = acc
+    })
+    if err != nil {
+        var zero U
+        return zero, err
+    }
+
+    acc := initial
+    for _, p := range partial {
+        acc = combine(acc, p)
+    }
+    a.countBulk()
+    return acc, nil
+}
+
+// ParallelForEach calls fn with the index and value of every element
+// fn is called concurrently from several goroutines, in no particular order.
+func ParallelForEach[T any](ctx context.Context, a *Array[T], fn func(int, T), config ParallelConfig) error {
+    a.mu.RLock() // Lock for reading
+    defer a.mu.RUnlock()
+
+    data := a.data[:a.size]
+    err := forEachChunk(ctx, len(data), config, func(lo, hi int) {
+        for i := lo; i < hi; i++ {
+            fn(i, data[i])
+        }
+    })
+    if err != nil {
+        return err
+    }
+    a.countBulk()
+    return nil
+}
+
+// ParallelSort sorts the array in place with a parallel merge sort, keeping the order of equal elements
+// The chunks are sorted concurrently and then merged pairwise, each round of
+// merges running concurrently. The sort works on a copy of the elements, so
+// the array is left unchanged when ctx is done before it completes.
+func ParallelSort[T any](ctx context.Context, a *Array[T], compare func(x, y T) int, config ParallelConfig) error {
+    a.mu.Lock() // Lock for writing
+    defer a.mu.Unlock()
+
+    config = config.withDefaults()
+    n := a.size
+    src := slices.Clone(a.data[:n])
+    err := forEachChunk(ctx, n, config, func(lo, hi int) {
+        slices.SortStableFunc(src[lo:hi], compare)
+    })
+    if err != nil {
+        return err
+    }
+
+    dst := make([]T, n)
+    for width := config.ChunkSize; width < n; width *= 2 {
+        pairs := chunks(n, 2*width)
+        err := parallel(ctx, pairs, config.Workers, func(pair int) {
+            lo := pair * 2 * width
+            mid := min(lo+width, n)
+            hi := min(lo+2*width, n)
+            merge(dst[lo:hi], src[lo:mid], src[mid:hi], compare)
+        })
+        if err != nil {
+            return err
+        }
+        src, dst = dst, src
+    }
+
+    a.copyIfShared()
+    copy(a.data, src)
+    a.countBulk()
+    return nil
+}
+
+// merge merges the sorted slices left and right into dst, taking from left first on ties
+func merge[T any](dst, left, right []T, compare func(x, y T) int) {
+    i, j, k := 0, 0, 0
+    for i < len(left) && j < len(right) {
+        if compare(right[j], left[i]) < 0 {
+            dst[k] = right[j]
+            j++
+        } else {
+            dst[k] = left[i]
+            i++
+        }
+        k++
+    }
+    k += copy(dst[k:], left[i:])
+    copy(dst[k:], right[j:])
+}
+
+// chunks returns the number of chunks of the given size needed to cover n elements
+func chunks(n, size int) int {
+    return (n + size - 1) / size
+}
+
+// forEachChunk calls fn concurrently with the bounds of every chunk of n elements
+func forEachChunk(ctx context.Context, n int, config ParallelConfig, fn func(lo, hi int)) error {
+    config = config.withDefaults()
+    return parallel(ctx, chunks(n, config.ChunkSize), config.Workers, func(chunk int) {
+        lo := chunk * config.ChunkSize
+        fn(lo, min(lo+config.ChunkSize, n))
+    })
+}
+
+// parallel runs fn for every task on a pool of workers
+// It returns the error of ctx when ctx is done before every task ran.
+func parallel(ctx context.Context, tasks, workers int, fn func(task int)) error {
+    var next, done atomic.Int64
+    var wg sync.WaitGroup
+    for w := 0; w < min(workers, tasks); w++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            for ctx.Err() == nil {
+                task := int(next.Add(1)) - 1
+                if task >= tasks {
+                    return
+                }
+                fn(task)
+                done.Add(1)
+            }
+        }()
+    }
+    wg.Wait()
+
+    if int(done.Load()) < tasks {
+        return ctx.Err()
+    }
+    return nil
+}
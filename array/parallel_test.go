@@ -0,0 +1,157 @@
+package array
+
+import (
+	"cmp"
+	"context"
+	"math/rand/v2"
+	"slices"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// randomArray creates a full array of n pseudo-random elements
+func randomArray(n int, config ArrayConfig) *Array[int] {
+    rng := rand.New(rand.NewPCG(1, 2))
+    arr := NewArray[int](n, config)
+    for i := 0; i < n; i++ {
+        arr.Append(rng.IntN(n))
+    }
+    return arr
+}
+
+func TestParallelMap(t *testing.T) {
+    arr := randomArray(10000, ArrayConfig{})
+    config := ParallelConfig{Workers: 4, ChunkSize: 128}
+
+    mapped, err := ParallelMap(context.Background(), arr, func(v int) int { return v * 3 }, config)
+    assert.NoError(t, err)
+    assert.Equal(t, elementsOf(Map(arr, func(v int) int { return v * 3 })), elementsOf(mapped))
+
+    // The defaults apply to an empty config
+    mapped, err = ParallelMap(context.Background(), arr, func(v int) int { return v * 3 }, ParallelConfig{})
+    assert.NoError(t, err)
+    assert.Equal(t, 10000, mapped.Length())
+
+    empty, err := ParallelMap(context.Background(), NewArray[int](0, ArrayConfig{}), func(v int) int { return v }, config)
+    assert.NoError(t, err)
+    assert.Equal(t, 0, empty.Length())
+}
+
+func TestParallelReduce(t *testing.T) {
+    arr := randomArray(10000, ArrayConfig{})
+    config := ParallelConfig{Workers: 4, ChunkSize: 100}
+
+    sum := func(acc, v int) int { return acc + v }
+    total, err := ParallelReduce(context.Background(), arr, 0, sum, sum, config)
+    assert.NoError(t, err)
+    assert.Equal(t, Reduce(arr, 0, sum), total)
+
+    // Chunk results are combined in order
+    words := filled(ArrayConfig{}, "a", "b", "c", "d", "e")
+    concat := func(acc, v string) string { return acc + v }
+    joined, err := ParallelReduce(context.Background(), words, "", concat, concat, ParallelConfig{Workers: 3, ChunkSize: 2})
+    assert.NoError(t, err)
+    assert.Equal(t, "abcde", joined)
+}
+
+func TestParallelForEach(t *testing.T) {
+    arr := randomArray(10000, ArrayConfig{})
+    config := ParallelConfig{Workers: 4, ChunkSize: 64}
+
+    seen := make([]int, arr.Length())
+    var calls atomic.Int64
+    err := ParallelForEach(context.Background(), arr, func(i, v int) {
+        seen[i] = v
+        calls.Add(1)
+    }, config)
+    assert.NoError(t, err)
+    assert.Equal(t, int64(10000), calls.Load())
+    assert.Equal(t, elementsOf(arr), seen)
+}
+
+func TestParallelSort(t *testing.T) {
+    for _, n := range []int{0, 1, 100, 1000, 4099} {
+        arr := randomArray(n, ArrayConfig{})
+        expected := elementsOf(arr)
+        slices.Sort(expected)
+
+        err := ParallelSort(context.Background(), arr, cmp.Compare[int], ParallelConfig{Workers: 4, ChunkSize: 64})
+        assert.NoError(t, err)
+        assert.Equal(t, expected, elementsOf(arr), "n = %d", n)
+    }
+}
+
+func TestParallelSortStable(t *testing.T) {
+    type pair struct {
+        key   int
+        index int
+    }
+    rng := rand.New(rand.NewPCG(1, 2))
+    arr := NewArray[pair](5000, ArrayConfig{})
+    for i := 0; i < 5000; i++ {
+        arr.Append(pair{key: rng.IntN(10), index: i})
+    }
+    expected := elementsOf(arr)
+    byKey := func(x, y pair) int { return cmp.Compare(x.key, y.key) }
+    slices.SortStableFunc(expected, byKey)
+
+    err := ParallelSort(context.Background(), arr, byKey, ParallelConfig{Workers: 4, ChunkSize: 100})
+    assert.NoError(t, err)
+    assert.Equal(t, expected, elementsOf(arr))
+}
+
+func TestParallelSortSnapshot(t *testing.T) {
+    arr := filled(ArrayConfig{}, 4, 3, 2, 1)
+    snap := arr.Snapshot()
+
+    err := ParallelSort(context.Background(), arr, cmp.Compare[int], ParallelConfig{ChunkSize: 1})
+    assert.NoError(t, err)
+    assert.Equal(t, []int{1, 2, 3, 4}, elementsOf(arr))
+
+    value, _ := snap.Get(0)
+    assert.Equal(t, 4, value)
+}
+
+func TestParallelCancel(t *testing.T) {
+    arr := randomArray(10000, ArrayConfig{})
+    config := ParallelConfig{Workers: 2, ChunkSize: 10}
+
+    ctx, cancel := context.WithCancel(context.Background())
+    cancel()
+    _, err := ParallelMap(ctx, arr, func(v int) int { return v }, config)
+    assert.Equal(t, context.Canceled, err)
+    _, err = ParallelReduce(ctx, arr, 0, func(acc, v int) int { return acc + v }, func(x, y int) int { return x + y }, config)
+    assert.Equal(t, context.Canceled, err)
+
+    // Cancelling midway stops the remaining chunks
+    ctx, cancel = context.WithCancel(context.Background())
+    var calls atomic.Int64
+    err = ParallelForEach(ctx, arr, func(i, v int) {
+        if calls.Add(1) == 100 {
+            cancel()
+        }
+    }, config)
+    assert.Equal(t, context.Canceled, err)
+    assert.Less(t, calls.Load(), int64(10000))
+
+    // A cancelled sort leaves the array unchanged
+    before := elementsOf(arr)
+    err = ParallelSort(ctx, arr, cmp.Compare[int], config)
+    assert.Equal(t, context.Canceled, err)
+    assert.Equal(t, before, elementsOf(arr))
+}
+
+func TestParallelMetrics(t *testing.T) {
+    arr := randomArray(1000, ArrayConfig{MetricsEnabled: true})
+    before := arr.bulkCounter.Count()
+    config := ParallelConfig{ChunkSize: 10}
+
+    sum := func(acc, v int) int { return acc + v }
+    ParallelMap(context.Background(), arr, func(v int) int { return v }, config)
+    ParallelReduce(context.Background(), arr, 0, sum, sum, config)
+    ParallelForEach(context.Background(), arr, func(int, int) {}, config)
+    ParallelSort(context.Background(), arr, cmp.Compare[int], config)
+    assert.Equal(t, before+4, arr.bulkCounter.Count())
+}
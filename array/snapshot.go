@@ -0,0 +1,73 @@
+package array
+
+import (
+    "errors"
+    "iter"
+)
+
+// Snapshot is a read-only view of an Array at the time Snapshot was called
+// It does not hold any lock: writers keep modifying the array while the
+// snapshot keeps returning the elements it was taken with.
+type Snapshot[T any] struct {
+    data []T
+    size int
+}
+
+// Snapshot returns a consistent read-only view of the array
+// Taking a snapshot is O(1). The backing slice is shared until the next
+// write that would modify an element visible to the snapshot, which copies
+// it first. Appends only write past the snapshot's length and never copy.
+func (a *Array[T]) Snapshot() *Snapshot[T] {
+    a.mu.Lock() // Lock for writing, the shared flag is updated
+    defer a.mu.Unlock()
+
+    a.shared = true
+
+    // Track metrics if enabled
+    if a.config.MetricsEnabled {
+        a.snapshotCounter.Inc(1)
+    }
+    return &Snapshot[T]{data: a.data, size: a.size}
+}
+
+// copyIfShared gives the array its own backing slice if a snapshot references the current one
+// The caller must hold the write lock
+func (a *Array[T]) copyIfShared() {
+    if !a.shared {
+        return
+    }
+    newData := make([]T, len(a.data))
+    copy(newData, a.data[:a.size])
+    a.data = newData
+    a.shared = false
+
+    // Track metrics if enabled
+    if a.config.MetricsEnabled {
+        a.copyCounter.Inc(1)
+    }
+}
+
+// Get retrieves the element at the specified index
+func (s *Snapshot[T]) Get(index int) (T, error) {
+    if index < 0 || index >= s.size {
+        var zero T // Return a zero value of type T
+        return zero, errors.New("index out of bounds")
+    }
+    return s.data[index], nil
+}
+
+// Length returns the number of elements in the snapshot
+func (s *Snapshot[T]) Length() int {
+    return s.size
+}
+
+// All returns an iterator over the indexes and elements of the snapshot
+func (s *Snapshot[T]) All() iter.Seq2[int, T] {
+    return func(yield func(int, T) bool) {
+        for i := 0; i < s.size; i++ {
+            if !yield(i, s.data[i]) {
+                return
+            }
+        }
+    }
+}
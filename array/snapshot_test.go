@@ -0,0 +1,140 @@
+package array
+
+import (
+    "sync"
+    "testing"
+
+    "github.com/stretchr/testify/assert"
+)
+
+func TestSnapshot(t *testing.T) {
+    config := ArrayConfig{MetricsEnabled: false}
+    arr := NewArray[int](5, config)
+
+    arr.Append(10)
+    arr.Append(20)
+    arr.Append(30)
+
+    snap := arr.Snapshot()
+
+    // Writers keep going after the snapshot
+    arr.Delete(0)
+    arr.Append(40)
+    arr.Resize(10)
+    arr.Append(50)
+
+    // The snapshot still sees the original elements
+    assert.Equal(t, 3, snap.Length())
+    for i, expected := range []int{10, 20, 30} {
+        value, err := snap.Get(i)
+        assert.NoError(t, err)
+        assert.Equal(t, expected, value)
+    }
+
+    _, err := snap.Get(3)
+    assert.Error(t, err)
+    assert.Equal(t, "index out of bounds", err.Error())
+
+    // The array sees the new elements
+    assert.Equal(t, 4, arr.Length())
+    value, _ := arr.Get(0)
+    assert.Equal(t, 20, value)
+}
+
+func TestSnapshotIteration(t *testing.T) {
+    config := ArrayConfig{MetricsEnabled: false}
+    arr := NewArray[string](3, config)
+
+    arr.Append("a")
+    arr.Append("b")
+    arr.Append("c")
+
+    snap := arr.Snapshot()
+    arr.Delete(1)
+
+    var values []string
+    for i, v := range snap.All() {
+        assert.Equal(t, len(values), i)
+        values = append(values, v)
+    }
+    assert.Equal(t, []string{"a", "b", "c"}, values)
+}
+
+func TestSnapshotCopyMetric(t *testing.T) {
+    config := ArrayConfig{MetricsEnabled: true}
+    arr := NewArray[int](5, config)
+    copies := arr.copyCounter
+    before := copies.Count()
+
+    arr.Append(1)
+    arr.Append(2)
+    arr.Snapshot()
+
+    // Appends write past the snapshot and do not copy
+    arr.Append(3)
+    assert.Equal(t, before, copies.Count())
+
+    // The first delete copies, the next ones do not
+    arr.Delete(0)
+    arr.Delete(0)
+    assert.Equal(t, before+1, copies.Count())
+
+    // A new snapshot triggers a new copy
+    arr.Snapshot()
+    arr.Delete(0)
+    assert.Equal(t, before+2, copies.Count())
+}
+
+func TestSnapshotConcurrentWriters(t *testing.T) {
+    config := ArrayConfig{MetricsEnabled: false}
+    arr := NewArray[int](1000, config)
+
+    for i := 0; i < 500; i++ {
+        arr.Append(i)
+    }
+
+    var wg sync.WaitGroup
+    wg.Add(2)
+    go func() {
+        defer wg.Done()
+        for i := 0; i < 200; i++ {
+            arr.Delete(0)
+            arr.Append(-1)
+        }
+    }()
+    go func() {
+        defer wg.Done()
+        for i := 0; i < 50; i++ {
+            snap := arr.Snapshot()
+            // Every snapshot is internally consistent: no -1 before the original values
+            seenNew := false
+            for _, v := range snap.All() {
+                if v == -1 {
+                    seenNew = true
+                } else if seenNew {
+                    t.Errorf("snapshot mixes states")
+                    return
+                }
+            }
+        }
+    }()
+    wg.Wait()
+}
+
+// BenchmarkSnapshotDelete benchmarks a delete that follows a snapshot and therefore copies.
+func BenchmarkSnapshotDelete(b *testing.B) {
+    config := ArrayConfig{MetricsEnabled: false}
+    arr := NewArray[int](1000, config)
+
+    // Pre-fill the array
+    for i := 0; i < 1000; i++ {
+        arr.Append(i)
+    }
+
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        arr.Snapshot()
+        arr.Delete(0)
+        arr.Append(i)
+    }
+}
@@ -0,0 +1,274 @@
+package array
+
+import (
+    "cmp"
+    "errors"
+    "iter"
+    "slices"
+    "sort"
+    "github.com/rcrowley/go-metrics"
+)
+
+const minSortedCapacity = 8
+
+type SortedConfig struct {
+    // Unique gives the array set semantics: inserting an element equal to one already present does nothing
+    Unique         bool
+    MetricsEnabled bool
+}
+
+// Sorted is an Array keeping its elements ordered on every insert
+// Lookups are binary searches over contiguous memory and inserts shift the
+// following elements, so for read-mostly workloads it beats a balanced tree.
+// Elements comparing equal keep their insertion order. The array grows as
+// needed and shares the lock and copy-on-write snapshots of the underlying
+// Array.
+type Sorted[T any] struct {
+    arr           *Array[T]
+    compare       func(a, b T) int
+    config        SortedConfig
+    insertCounter metrics.Counter
+    searchCounter metrics.Counter
+    removeCounter metrics.Counter
+}
+
+// NewSorted creates an empty sorted array of ordered elements with the given initial capacity
+// The config parameter is used to enable set semantics and metrics collection
+func NewSorted[T cmp.Ordered](capacity int, config SortedConfig) *Sorted[T] {
+    return NewSortedFunc(capacity, cmp.Compare[T], config)
+}
+
+// NewSortedFunc creates an empty sorted array ordered by compare
+// compare returns a negative number when a sorts before b, a positive number
+// when it sorts after and zero when they are equal, like cmp.Compare.
+func NewSortedFunc[T any](capacity int, compare func(a, b T) int, config SortedConfig) *Sorted[T] {
+    s := &Sorted[T]{
+        arr: NewArray[T](capacity, ArrayConfig{}),
+        compare: compare,
+        config: config,
+    }
+
+    // Initialize the metrics only if enabled in the config
+    if s.config.MetricsEnabled {
+        s.insertCounter = metrics.NewCounter()
+        s.searchCounter = metrics.NewCounter()
+        s.removeCounter = metrics.NewCounter()
+        metrics.DefaultRegistry.Register("array.sorted.insert", s.insertCounter)
+        metrics.DefaultRegistry.Register("array.sorted.search", s.searchCounter)
+        metrics.DefaultRegistry.Register("array.sorted.remove", s.removeCounter)
+    }
+
+    return s
+}
+
+// Insert adds value at its position and returns that position
+// With set semantics an element equal to value that is already present is
+// left in place, and Insert returns its position and false.
+func (s *Sorted[T]) Insert(value T) (int, bool) {
+    a := s.arr
+    a.mu.Lock() // Lock for writing
+    defer a.mu.Unlock()
+
+    var index int
+    if s.config.Unique {
+        i, found := s.lowerBound(value)
+        if found {
+            return i, false
+        }
+        index = i
+    } else {
+        // After the equal elements, so that they keep their insertion order
+        index = s.upperBound(value)
+    }
+
+    if a.size == len(a.data) {
+        s.grow(a.size + 1)
+    }
+    if index < a.size {
+        a.copyIfShared()
+    }
+    copy(a.data[index+1:a.size+1], a.data[index:a.size])
+    a.data[index] = value
+    a.size++
+
+    // Track metrics if enabled
+    if s.config.MetricsEnabled {
+        s.insertCounter.Inc(1)
+    }
+    return index, true
+}
+
+// grow gives the underlying array room for at least n elements, doubling its capacity
+// The caller must hold the write lock
+func (s *Sorted[T]) grow(n int) {
+    a := s.arr
+    newData := make([]T, max(n, 2*len(a.data), minSortedCapacity))
+    copy(newData, a.data[:a.size])
+    a.data = newData
+    a.shared = false // Snapshots keep the old backing slice
+}
+
+// Search returns the position of the first element equal to value and whether there is one
+// When there is none the position is where value would be inserted.
+func (s *Sorted[T]) Search(value T) (int, bool) {
+    s.arr.mu.RLock() // Lock for reading
+    defer s.arr.mu.RUnlock()
+
+    s.countSearch()
+    return s.lowerBound(value)
+}
+
+// LowerBound returns the position of the first element not less than value
+func (s *Sorted[T]) LowerBound(value T) int {
+    s.arr.mu.RLock() // Lock for reading
+    defer s.arr.mu.RUnlock()
+
+    s.countSearch()
+    index, _ := s.lowerBound(value)
+    return index
+}
+
+// UpperBound returns the position of the first element greater than value
+func (s *Sorted[T]) UpperBound(value T) int {
+    s.arr.mu.RLock() // Lock for reading
+    defer s.arr.mu.RUnlock()
+
+    s.countSearch()
+    return s.upperBound(value)
+}
+
+func (s *Sorted[T]) lowerBound(value T) (int, bool) {
+    return slices.BinarySearchFunc(s.arr.data[:s.arr.size], value, s.compare)
+}
+
+func (s *Sorted[T]) upperBound(value T) int {
+    data := s.arr.data
+    return sort.Search(s.arr.size, func(i int) bool {
+        return s.compare(data[i], value) > 0
+    })
+}
+
+func (s *Sorted[T]) countSearch() {
+    // Track metrics if enabled
+    if s.config.MetricsEnabled {
+        s.searchCounter.Inc(1)
+    }
+}
+
+// RemoveValue removes the first element equal to value, it returns false when there is none
+func (s *Sorted[T]) RemoveValue(value T) bool {
+    a := s.arr
+    a.mu.Lock() // Lock for writing
+    defer a.mu.Unlock()
+
+    index, found := s.lowerBound(value)
+    if !found {
+        return false
+    }
+    a.copyIfShared()
+
+    // Shift elements to the left to fill the gap
+    copy(a.data[index:], a.data[index+1:a.size])
+    a.size--
+    var zero T // Zero value for type T
+    a.data[a.size] = zero
+
+    // Track metrics if enabled
+    if s.config.MetricsEnabled {
+        s.removeCounter.Inc(1)
+    }
+    return true
+}
+
+// Get retrieves the element at the specified index
+func (s *Sorted[T]) Get(index int) (T, error) {
+    s.arr.mu.RLock() // Lock for reading
+    defer s.arr.mu.RUnlock()
+
+    if index < 0 || index >= s.arr.size {
+        var zero T // Return a zero value of type T
+        return zero, errors.New("index out of bounds")
+    }
+    return s.arr.data[index], nil
+}
+
+// Length returns the current number of elements
+func (s *Sorted[T]) Length() int {
+    return s.arr.Length()
+}
+
+// Snapshot returns a consistent read-only view of the sorted elements
+func (s *Sorted[T]) Snapshot() *Snapshot[T] {
+    return s.arr.Snapshot()
+}
+
+// RangeIter returns an iterator over the positions and elements in [lo, hi)
+// It iterates over a snapshot taken when it starts, so the loop body may
+// modify the array.
+func (s *Sorted[T]) RangeIter(lo, hi T) iter.Seq2[int, T] {
+    return func(yield func(int, T) bool) {
+        a := s.arr
+        a.mu.Lock() // Lock for writing, the shared flag is updated
+        from, _ := s.lowerBound(lo)
+        to, _ := s.lowerBound(hi)
+        a.shared = true
+        data := a.data
+        a.mu.Unlock()
+
+        s.countSearch()
+        for i := from; i < to; i++ {
+            if !yield(i, data[i]) {
+                return
+            }
+        }
+    }
+}
+
+// Merge inserts all the elements of other in a single linear pass
+// other must be ordered the same way. Elements of the array come before the
+// equal elements of other, and with set semantics only the former are kept.
+func (s *Sorted[T]) Merge(other *Sorted[T]) {
+    // A copy of other avoids holding both locks, unlike a snapshot it leaves other unshared
+    other.arr.mu.RLock() // Lock for reading
+    theirs := slices.Clone(other.arr.data[:other.arr.size])
+    other.arr.mu.RUnlock()
+
+    a := s.arr
+    a.mu.Lock() // Lock for writing
+    defer a.mu.Unlock()
+
+    mine := a.data[:a.size]
+    merged := make([]T, 0, max(len(a.data), len(mine)+len(theirs)))
+    add := func(value T) {
+        if s.config.Unique && len(merged) > 0 && s.compare(merged[len(merged)-1], value) == 0 {
+            return
+        }
+        merged = append(merged, value)
+    }
+    i, j := 0, 0
+    for i < len(mine) && j < len(theirs) {
+        if s.compare(theirs[j], mine[i]) < 0 {
+            add(theirs[j])
+            j++
+        } else {
+            add(mine[i])
+            i++
+        }
+    }
+    for ; i < len(mine); i++ {
+        add(mine[i])
+    }
+    for ; j < len(theirs); j++ {
+        add(theirs[j])
+    }
+
+    inserted := len(merged) - len(mine)
+    a.data = merged[:cap(merged)]
+    a.size = len(merged)
+    a.shared = false
+
+    // Track metrics if enabled
+    if s.config.MetricsEnabled {
+        s.insertCounter.Inc(int64(inserted))
+    }
+}
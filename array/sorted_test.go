@@ -0,0 +1,314 @@
+package array
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortedInsert(t *testing.T) {
+    config := SortedConfig{MetricsEnabled: false}
+    sorted := NewSorted[int](0, config)
+
+    // Insert out of order, the array grows as needed
+    for _, v := range []int{30, 10, 20, 10, 40} {
+        _, inserted := sorted.Insert(v)
+        assert.True(t, inserted)
+    }
+    assert.Equal(t, 5, sorted.Length())
+
+    index, inserted := sorted.Insert(25)
+    assert.True(t, inserted)
+    assert.Equal(t, 3, index)
+
+    var values []int
+    for i := 0; i < sorted.Length(); i++ {
+        value, err := sorted.Get(i)
+        assert.NoError(t, err)
+        values = append(values, value)
+    }
+    assert.Equal(t, []int{10, 10, 20, 25, 30, 40}, values)
+
+    _, err := sorted.Get(6)
+    assert.Error(t, err)
+    assert.Equal(t, "index out of bounds", err.Error())
+}
+
+func TestSortedUnique(t *testing.T) {
+    config := SortedConfig{Unique: true}
+    sorted := NewSorted[string](4, config)
+
+    sorted.Insert("b")
+    sorted.Insert("a")
+    index, inserted := sorted.Insert("b")
+    assert.False(t, inserted)
+    assert.Equal(t, 1, index)
+    assert.Equal(t, 2, sorted.Length())
+}
+
+func TestSortedFunc(t *testing.T) {
+    type user struct {
+        name string
+        age  int
+    }
+    config := SortedConfig{MetricsEnabled: false}
+    sorted := NewSortedFunc(4, func(a, b user) int { return cmp.Compare(a.age, b.age) }, config)
+
+    sorted.Insert(user{"carol", 40})
+    sorted.Insert(user{"alice", 30})
+    sorted.Insert(user{"bob", 30})
+
+    // Equal elements keep their insertion order
+    var names []string
+    for _, u := range sorted.Snapshot().All() {
+        names = append(names, u.name)
+    }
+    assert.Equal(t, []string{"alice", "bob", "carol"}, names)
+
+    index, found := sorted.Search(user{age: 30})
+    assert.True(t, found)
+    assert.Equal(t, 0, index)
+    _, found = sorted.Search(user{age: 35})
+    assert.False(t, found)
+}
+
+func TestSortedBounds(t *testing.T) {
+    config := SortedConfig{MetricsEnabled: false}
+    sorted := NewSorted[int](8, config)
+    for _, v := range []int{1, 3, 3, 3, 5} {
+        sorted.Insert(v)
+    }
+
+    index, found := sorted.Search(3)
+    assert.True(t, found)
+    assert.Equal(t, 1, index)
+    index, found = sorted.Search(4)
+    assert.False(t, found)
+    assert.Equal(t, 4, index)
+
+    assert.Equal(t, 1, sorted.LowerBound(3))
+    assert.Equal(t, 4, sorted.UpperBound(3))
+    assert.Equal(t, 0, sorted.LowerBound(0))
+    assert.Equal(t, 5, sorted.UpperBound(5))
+}
+
+func TestSortedRemoveValue(t *testing.T) {
+    config := SortedConfig{MetricsEnabled: false}
+    sorted := NewSorted[int](8, config)
+    for _, v := range []int{1, 2, 2, 3} {
+        sorted.Insert(v)
+    }
+
+    assert.True(t, sorted.RemoveValue(2))
+    assert.True(t, sorted.RemoveValue(2))
+    assert.False(t, sorted.RemoveValue(2))
+    assert.False(t, sorted.RemoveValue(7))
+    assert.Equal(t, 2, sorted.Length())
+
+    value, _ := sorted.Get(1)
+    assert.Equal(t, 3, value)
+}
+
+func TestSortedRangeIter(t *testing.T) {
+    config := SortedConfig{MetricsEnabled: false}
+    sorted := NewSorted[int](8, config)
+    for _, v := range []int{5, 1, 4, 2, 3} {
+        sorted.Insert(v)
+    }
+
+    var indexes, values []int
+    for i, v := range sorted.RangeIter(2, 5) {
+        indexes = append(indexes, i)
+        values = append(values, v)
+    }
+    assert.Equal(t, []int{1, 2, 3}, indexes)
+    assert.Equal(t, []int{2, 3, 4}, values)
+
+    // The loop body may modify the array, the iteration is not affected
+    values = nil
+    for _, v := range sorted.RangeIter(0, 10) {
+        sorted.RemoveValue(v)
+        sorted.Insert(v * 10)
+        values = append(values, v)
+    }
+    assert.Equal(t, []int{1, 2, 3, 4, 5}, values)
+    assert.Equal(t, 1, sorted.LowerBound(20))
+
+    for range sorted.RangeIter(6, 6) {
+        t.Fatal("empty range")
+    }
+}
+
+func TestSortedMerge(t *testing.T) {
+    config := SortedConfig{MetricsEnabled: false}
+    a := NewSorted[int](2, config)
+    b := NewSorted[int](2, config)
+    for _, v := range []int{1, 4, 6} {
+        a.Insert(v)
+    }
+    for _, v := range []int{2, 4, 7, 8} {
+        b.Insert(v)
+    }
+
+    a.Merge(b)
+    assert.Equal(t, 7, a.Length())
+    assert.Equal(t, 4, b.Length())
+    var values []int
+    for _, v := range a.Snapshot().All() {
+        values = append(values, v)
+    }
+    assert.Equal(t, []int{1, 2, 4, 4, 6, 7, 8}, values)
+
+    // Merging into itself duplicates every element
+    a.Merge(a)
+    assert.Equal(t, 14, a.Length())
+}
+
+func TestSortedMergeUnique(t *testing.T) {
+    config := SortedConfig{Unique: true}
+    a := NewSortedFunc(4, strings.Compare, config)
+    b := NewSortedFunc(4, strings.Compare, config)
+    for _, v := range []string{"a", "c"} {
+        a.Insert(v)
+    }
+    for _, v := range []string{"b", "c", "d"} {
+        b.Insert(v)
+    }
+
+    a.Merge(b)
+    a.Merge(a)
+    var values []string
+    for _, v := range a.Snapshot().All() {
+        values = append(values, v)
+    }
+    assert.Equal(t, []string{"a", "b", "c", "d"}, values)
+
+    // Inserting after a merge still keeps the set semantics
+    _, inserted := a.Insert("b")
+    assert.False(t, inserted)
+}
+
+func TestSortedModel(t *testing.T) {
+    rng := rand.New(rand.NewPCG(1, 2))
+    for _, unique := range []bool{false, true} {
+        sorted := NewSorted[int](0, SortedConfig{Unique: unique})
+        var model []int
+        for i := 0; i < 2000; i++ {
+            v := rng.IntN(100)
+            switch rng.IntN(4) {
+            case 0:
+                index, found := slices.BinarySearch(model, v)
+                assert.Equal(t, found, sorted.RemoveValue(v))
+                if found {
+                    model = slices.Delete(model, index, index+1)
+                }
+            case 1:
+                other := NewSorted[int](0, SortedConfig{Unique: unique})
+                for j := rng.IntN(10); j > 0; j-- {
+                    w := rng.IntN(100)
+                    other.Insert(w)
+                    model = append(model, w)
+                }
+                sorted.Merge(other)
+                slices.Sort(model)
+                if unique {
+                    model = slices.Compact(model)
+                }
+            default:
+                _, inserted := sorted.Insert(v)
+                assert.Equal(t, !unique || !slices.Contains(model, v), inserted)
+                if inserted {
+                    model = append(model, v)
+                    slices.Sort(model)
+                }
+            }
+        }
+
+        var values []int
+        for _, v := range sorted.Snapshot().All() {
+            values = append(values, v)
+        }
+        assert.Equal(t, model, values)
+    }
+}
+
+func TestSortedSnapshot(t *testing.T) {
+    config := SortedConfig{MetricsEnabled: false}
+    sorted := NewSorted[int](8, config)
+    sorted.Insert(2)
+    sorted.Insert(1)
+
+    snap := sorted.Snapshot()
+    sorted.Insert(0)
+    sorted.RemoveValue(2)
+
+    value, _ := snap.Get(0)
+    assert.Equal(t, 1, value)
+    assert.Equal(t, 2, snap.Length())
+}
+
+func TestSortedMetrics(t *testing.T) {
+    config := SortedConfig{MetricsEnabled: true}
+    sorted := NewSorted[int](8, config)
+
+    sorted.Insert(1)
+    sorted.Insert(2)
+    sorted.Search(1)
+    sorted.LowerBound(1)
+    sorted.RemoveValue(1)
+    assert.Equal(t, int64(2), sorted.insertCounter.Count())
+    assert.Equal(t, int64(2), sorted.searchCounter.Count())
+    assert.Equal(t, int64(1), sorted.removeCounter.Count())
+
+    // Merging counts only the elements that were kept
+    set := NewSorted[int](4, SortedConfig{Unique: true, MetricsEnabled: true})
+    other := NewSorted[int](4, SortedConfig{Unique: true})
+    set.Insert(1)
+    set.Insert(2)
+    for _, v := range []int{2, 3, 4} {
+        other.Insert(v)
+    }
+    set.Merge(other)
+    assert.Equal(t, int64(4), set.insertCounter.Count())
+
+    // other is read without a snapshot, so writing to it later does not copy its data
+    assert.False(t, other.arr.shared)
+}
+
+// BenchmarkSortedSearch benchmarks the binary search of a sorted array.
+func BenchmarkSortedSearch(b *testing.B) {
+    config := SortedConfig{MetricsEnabled: false}
+    sorted := NewSorted[int](1000, config)
+
+    // Pre-fill the array
+    for i := 0; i < 1000; i++ {
+        sorted.Insert(i * 2)
+    }
+
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        sorted.Search(i % 2000)
+    }
+}
+
+// BenchmarkSortedInsert benchmarks inserting into a sorted array of a stable size.
+func BenchmarkSortedInsert(b *testing.B) {
+    config := SortedConfig{MetricsEnabled: false}
+    sorted := NewSorted[int](1000, config)
+
+    // Pre-fill the array
+    for i := 0; i < 1000; i++ {
+        sorted.Insert(i * 2)
+    }
+
+    b.ResetTimer()
+    for i := 0; i < b.N; i++ {
+        value := (i * 7919) % 2000
+        sorted.Insert(value)
+        sorted.RemoveValue(value)
+    }
+}
@@ -0,0 +1,256 @@
+package bitset
+
+import (
+	"iter"
+	"math/bits"
+)
+
+const wordSize = 64
+
+// Bitset is a dense set of non-negative integers stored one bit per element
+// The set grows automatically on Add. A Bitset is not safe for concurrent use;
+// callers that share one between goroutines must provide their own locking.
+type Bitset struct {
+	words  []uint64
+	length uint
+}
+
+// New creates a bitset able to hold the integers in [0, length) without growing
+func New(length uint) *Bitset {
+	return &Bitset{
+		words:  make([]uint64, wordsNeeded(length)),
+		length: length,
+	}
+}
+
+// From creates a bitset containing the given integers
+func From(values ...uint) *Bitset {
+	b := New(0)
+	for _, v := range values {
+		b.Add(v)
+	}
+	return b
+}
+
+// Len returns the number of bits addressable without growing the set
+func (b *Bitset) Len() uint {
+	return b.length
+}
+
+// Add inserts i into the set, growing it if needed
+func (b *Bitset) Add(i uint) {
+	if i >= b.length {
+		b.grow(i + 1)
+	}
+	b.words[i/wordSize] |= 1 << (i % wordSize)
+}
+
+// Remove deletes i from the set
+func (b *Bitset) Remove(i uint) {
+	if i >= b.length {
+		return
+	}
+	b.words[i/wordSize] &^= 1 << (i % wordSize)
+}
+
+// Contains reports whether i is in the set
+func (b *Bitset) Contains(i uint) bool {
+	if i >= b.length {
+		return false
+	}
+	return b.words[i/wordSize]&(1<<(i%wordSize)) != 0
+}
+
+// Flip toggles the membership of i
+func (b *Bitset) Flip(i uint) {
+	if i >= b.length {
+		b.grow(i + 1)
+	}
+	b.words[i/wordSize] ^= 1 << (i % wordSize)
+}
+
+// Cardinality returns the number of integers in the set
+func (b *Bitset) Cardinality() uint {
+	count := 0
+	for _, w := range b.words {
+		count += bits.OnesCount64(w)
+	}
+	return uint(count)
+}
+
+// IsEmpty reports whether the set has no elements
+func (b *Bitset) IsEmpty() bool {
+	for _, w := range b.words {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Clear removes all elements while keeping the current length
+func (b *Bitset) Clear() {
+	clear(b.words)
+}
+
+// Clone returns an independent copy of the set
+func (b *Bitset) Clone() *Bitset {
+	c := &Bitset{
+		words:  make([]uint64, len(b.words)),
+		length: b.length,
+	}
+	copy(c.words, b.words)
+	return c
+}
+
+// Equal reports whether both sets contain the same elements, regardless of their lengths
+func (b *Bitset) Equal(other *Bitset) bool {
+	short, long := b.words, other.words
+	if len(short) > len(long) {
+		short, long = long, short
+	}
+	for i, w := range short {
+		if w != long[i] {
+			return false
+		}
+	}
+	for _, w := range long[len(short):] {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// And returns the intersection of both sets
+func (b *Bitset) And(other *Bitset) *Bitset {
+	result := New(min(b.length, other.length))
+	for i := range result.words {
+		result.words[i] = b.words[i] & other.words[i]
+	}
+	return result
+}
+
+// Or returns the union of both sets
+func (b *Bitset) Or(other *Bitset) *Bitset {
+	result, shorter := b.Clone(), other
+	if other.length > b.length {
+		result, shorter = other.Clone(), b
+	}
+	for i, w := range shorter.words {
+		result.words[i] |= w
+	}
+	return result
+}
+
+// Xor returns the symmetric difference of both sets
+func (b *Bitset) Xor(other *Bitset) *Bitset {
+	result, shorter := b.Clone(), other
+	if other.length > b.length {
+		result, shorter = other.Clone(), b
+	}
+	for i, w := range shorter.words {
+		result.words[i] ^= w
+	}
+	return result
+}
+
+// AndNot returns the elements of b that are not in other
+func (b *Bitset) AndNot(other *Bitset) *Bitset {
+	result := b.Clone()
+	n := min(len(result.words), len(other.words))
+	for i := 0; i < n; i++ {
+		result.words[i] &^= other.words[i]
+	}
+	return result
+}
+
+// Rank returns the number of elements less than or equal to i
+func (b *Bitset) Rank(i uint) uint {
+	if i >= b.length {
+		return b.Cardinality()
+	}
+	count := 0
+	last := i / wordSize
+	for _, w := range b.words[:last] {
+		count += bits.OnesCount64(w)
+	}
+	// Keep the bits at positions 0..i%64 of the last word
+	mask := uint64(1)<<(i%wordSize+1) - 1
+	if i%wordSize == wordSize-1 {
+		mask = ^uint64(0)
+	}
+	count += bits.OnesCount64(b.words[last] & mask)
+	return uint(count)
+}
+
+// Select returns the j-th smallest element (counting from zero)
+// The second result is false when the set has j or fewer elements
+func (b *Bitset) Select(j uint) (uint, bool) {
+	for i, w := range b.words {
+		count := uint(bits.OnesCount64(w))
+		if j < count {
+			return uint(i)*wordSize + selectInWord(w, j), true
+		}
+		j -= count
+	}
+	return 0, false
+}
+
+// NextSet returns the smallest element greater than or equal to i
+// The second result is false when there is no such element
+func (b *Bitset) NextSet(i uint) (uint, bool) {
+	if i >= b.length {
+		return 0, false
+	}
+	index := i / wordSize
+	w := b.words[index] >> (i % wordSize)
+	if w != 0 {
+		return i + uint(bits.TrailingZeros64(w)), true
+	}
+	for index++; index < uint(len(b.words)); index++ {
+		if b.words[index] != 0 {
+			return index*wordSize + uint(bits.TrailingZeros64(b.words[index])), true
+		}
+	}
+	return 0, false
+}
+
+// All returns an iterator over the elements in increasing order
+func (b *Bitset) All() iter.Seq[uint] {
+	return func(yield func(uint) bool) {
+		for i, w := range b.words {
+			for w != 0 {
+				t := bits.TrailingZeros64(w)
+				if !yield(uint(i)*wordSize + uint(t)) {
+					return
+				}
+				w &= w - 1
+			}
+		}
+	}
+}
+
+// grow extends the set so that it can address length bits
+func (b *Bitset) grow(length uint) {
+	needed := wordsNeeded(length)
+	if needed > len(b.words) {
+		// Double the storage to amortize the cost of repeated growth
+		newWords := make([]uint64, max(needed, 2*len(b.words)))
+		copy(newWords, b.words)
+		b.words = newWords
+	}
+	b.length = max(length, uint(len(b.words))*wordSize)
+}
+
+func wordsNeeded(length uint) int {
+	return int((length + wordSize - 1) / wordSize)
+}
+
+// selectInWord returns the position of the j-th set bit of w
+func selectInWord(w uint64, j uint) uint {
+	for ; j > 0; j-- {
+		w &= w - 1
+	}
+	return uint(bits.TrailingZeros64(w))
+}
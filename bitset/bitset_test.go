@@ -0,0 +1,142 @@
+package bitset
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddContainsRemove(t *testing.T) {
+	b := New(10)
+
+	b.Add(1)
+	b.Add(9)
+	b.Add(130) // Grows the set
+	assert.True(t, b.Contains(1))
+	assert.True(t, b.Contains(9))
+	assert.True(t, b.Contains(130))
+	assert.False(t, b.Contains(2))
+	assert.False(t, b.Contains(10000))
+	assert.GreaterOrEqual(t, b.Len(), uint(131))
+	assert.Equal(t, uint(3), b.Cardinality())
+
+	b.Remove(9)
+	b.Remove(10000) // No-op
+	assert.False(t, b.Contains(9))
+	assert.Equal(t, uint(2), b.Cardinality())
+
+	b.Flip(1)
+	b.Flip(2)
+	assert.False(t, b.Contains(1))
+	assert.True(t, b.Contains(2))
+}
+
+func TestSetOperations(t *testing.T) {
+	a := From(1, 2, 3, 64, 200)
+	b := From(2, 3, 4, 65)
+
+	assert.Equal(t, []uint{2, 3}, slices.Collect(a.And(b).All()))
+	assert.Equal(t, []uint{1, 2, 3, 4, 64, 65, 200}, slices.Collect(a.Or(b).All()))
+	assert.Equal(t, []uint{1, 4, 64, 65, 200}, slices.Collect(a.Xor(b).All()))
+	assert.Equal(t, []uint{1, 64, 200}, slices.Collect(a.AndNot(b).All()))
+	assert.Equal(t, []uint{4, 65}, slices.Collect(b.AndNot(a).All()))
+
+	// Operands are left untouched
+	assert.Equal(t, []uint{1, 2, 3, 64, 200}, slices.Collect(a.All()))
+}
+
+func TestEqual(t *testing.T) {
+	a := From(1, 5)
+	b := New(1000)
+	b.Add(1)
+	b.Add(5)
+	assert.True(t, a.Equal(b))
+	assert.True(t, b.Equal(a))
+
+	b.Add(999)
+	assert.False(t, a.Equal(b))
+}
+
+func TestRankSelect(t *testing.T) {
+	b := From(0, 3, 63, 64, 100, 127)
+
+	assert.Equal(t, uint(1), b.Rank(0))
+	assert.Equal(t, uint(1), b.Rank(2))
+	assert.Equal(t, uint(2), b.Rank(3))
+	assert.Equal(t, uint(3), b.Rank(63))
+	assert.Equal(t, uint(4), b.Rank(64))
+	assert.Equal(t, uint(6), b.Rank(127))
+	assert.Equal(t, uint(6), b.Rank(5000))
+
+	for j, want := range []uint{0, 3, 63, 64, 100, 127} {
+		got, ok := b.Select(uint(j))
+		assert.True(t, ok)
+		assert.Equal(t, want, got)
+	}
+	_, ok := b.Select(6)
+	assert.False(t, ok)
+}
+
+func TestNextSet(t *testing.T) {
+	b := From(5, 70, 300)
+
+	next, ok := b.NextSet(0)
+	assert.True(t, ok)
+	assert.Equal(t, uint(5), next)
+
+	next, ok = b.NextSet(6)
+	assert.True(t, ok)
+	assert.Equal(t, uint(70), next)
+
+	next, ok = b.NextSet(300)
+	assert.True(t, ok)
+	assert.Equal(t, uint(300), next)
+
+	_, ok = b.NextSet(301)
+	assert.False(t, ok)
+}
+
+func TestIteratorStops(t *testing.T) {
+	b := From(1, 2, 3, 4)
+
+	var seen []uint
+	for v := range b.All() {
+		if v == 3 {
+			break
+		}
+		seen = append(seen, v)
+	}
+	assert.Equal(t, []uint{1, 2}, seen)
+}
+
+func TestClearClone(t *testing.T) {
+	b := From(1, 2)
+	c := b.Clone()
+	b.Clear()
+
+	assert.True(t, b.IsEmpty())
+	assert.Equal(t, uint(2), c.Cardinality())
+}
+
+func BenchmarkAdd(b *testing.B) {
+	set := New(1 << 20)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		set.Add(uint(i) & (1<<20 - 1))
+	}
+}
+
+func BenchmarkAnd(b *testing.B) {
+	x, y := New(1<<20), New(1<<20)
+	for i := uint(0); i < 1<<20; i += 3 {
+		x.Add(i)
+		y.Add(i + 1)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.And(y)
+	}
+}
@@ -0,0 +1,94 @@
+package cdc
+
+import (
+	"context"
+	"sync"
+
+	"github.com/vzahanych/data-structures/array"
+)
+
+// Array publishes an event for every mutation made to an array.Array through it
+// The array must only be modified through the wrapper.
+type Array[T any] struct {
+	mu     sync.Mutex // Keeps events in the order of the mutations
+	array  *array.Array[T]
+	stream *Stream[T]
+}
+
+// WrapArray returns a wrapper publishing the mutations made to a
+func WrapArray[T any](a *array.Array[T], config Config) *Array[T] {
+	return &Array[T]{array: a, stream: newStream[T](config)}
+}
+
+// Subscribe returns a channel receiving the events of the mutations made from now on
+// The channel is closed when ctx is done or Close is called.
+func (a *Array[T]) Subscribe(ctx context.Context) <-chan Event[T] {
+	return a.stream.Subscribe(ctx)
+}
+
+// Dropped returns the number of events discarded because a subscriber was too slow
+func (a *Array[T]) Dropped() int64 {
+	return a.stream.Dropped()
+}
+
+// Close ends every subscription
+func (a *Array[T]) Close() {
+	a.stream.Close()
+}
+
+// Append adds a new element to the array
+func (a *Array[T]) Append(value T) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	index := a.array.Length()
+	if err := a.array.Append(value); err != nil {
+		return err
+	}
+	a.stream.publish(Event[T]{Op: OpAppend, Index: index, New: value})
+	return nil
+}
+
+// Delete removes the element at the specified index and shifts subsequent elements
+func (a *Array[T]) Delete(index int) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	old, err := a.array.Get(index)
+	if err != nil {
+		return err
+	}
+	if err := a.array.Delete(index); err != nil {
+		return err
+	}
+	a.stream.publish(Event[T]{Op: OpDelete, Index: index, Old: old})
+	return nil
+}
+
+// Resize resizes the array to a new capacity
+func (a *Array[T]) Resize(newCapacity int) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	oldCapacity := a.array.Capacity()
+	if err := a.array.Resize(newCapacity); err != nil {
+		return err
+	}
+	a.stream.publish(Event[T]{Op: OpResize, OldCapacity: oldCapacity, Capacity: newCapacity})
+	return nil
+}
+
+// Get retrieves an element at the specified index
+func (a *Array[T]) Get(index int) (T, error) {
+	return a.array.Get(index)
+}
+
+// Length returns the current number of elements in the array
+func (a *Array[T]) Length() int {
+	return a.array.Length()
+}
+
+// Capacity returns the number of elements the array can hold without resizing
+func (a *Array[T]) Capacity() int {
+	return a.array.Capacity()
+}
@@ -0,0 +1,67 @@
+package cdc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vzahanych/data-structures/array"
+)
+
+func TestArrayEvents(t *testing.T) {
+	a := WrapArray(array.NewArray[string](2, array.ArrayConfig{}), Config{})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := a.Subscribe(ctx)
+
+	assert.NoError(t, a.Append("a"))
+	assert.NoError(t, a.Append("b"))
+	assert.Error(t, a.Append("c")) // Failed mutations publish nothing
+	assert.NoError(t, a.Resize(4))
+	assert.NoError(t, a.Delete(0))
+	assert.Error(t, a.Delete(5))
+
+	assert.Equal(t, []Event[string]{
+		{Seq: 1, Op: OpAppend, Index: 0, New: "a"},
+		{Seq: 2, Op: OpAppend, Index: 1, New: "b"},
+		{Seq: 3, Op: OpResize, OldCapacity: 2, Capacity: 4},
+		{Seq: 4, Op: OpDelete, Index: 0, Old: "a"},
+	}, drain(events))
+}
+
+func TestArrayReplica(t *testing.T) {
+	master := WrapArray(array.NewArray[int](100, array.ArrayConfig{}), Config{Backpressure: Block})
+	events := master.Subscribe(context.Background())
+
+	// Keep a plain slice in sync by applying the events
+	replica := make(chan []int)
+	go func() {
+		var values []int
+		for e := range events {
+			switch e.Op {
+			case OpAppend:
+				values = append(values, e.New)
+			case OpDelete:
+				values = append(values[:e.Index], values[e.Index+1:]...)
+			}
+		}
+		replica <- values
+	}()
+
+	for i := 0; i < 100; i++ {
+		master.Append(i)
+		if i%3 == 0 {
+			master.Delete(master.Length() / 2)
+		}
+	}
+	master.Close()
+
+	expected := make([]int, 0, master.Length())
+	for i := 0; i < master.Length(); i++ {
+		v, _ := master.Get(i)
+		expected = append(expected, v)
+	}
+	assert.Equal(t, expected, <-replica)
+	assert.Equal(t, 100, master.Capacity())
+	assert.Equal(t, int64(0), master.Dropped())
+}
@@ -0,0 +1,76 @@
+package cdc
+
+import (
+	"context"
+	"sync"
+
+	"github.com/vzahanych/data-structures/linkedlist"
+)
+
+// LinkedList publishes an event for every mutation made to a linkedlist.LinkedList through it
+// The list must only be modified through the wrapper.
+type LinkedList struct {
+	mu     sync.Mutex // Keeps events in the order of the mutations
+	list   *linkedlist.LinkedList
+	stream *Stream[interface{}]
+}
+
+// WrapLinkedList returns a wrapper publishing the mutations made to l
+func WrapLinkedList(l *linkedlist.LinkedList, config Config) *LinkedList {
+	return &LinkedList{list: l, stream: newStream[interface{}](config)}
+}
+
+// Subscribe returns a channel receiving the events of the mutations made from now on
+// The channel is closed when ctx is done or Close is called.
+func (l *LinkedList) Subscribe(ctx context.Context) <-chan Event[interface{}] {
+	return l.stream.Subscribe(ctx)
+}
+
+// Dropped returns the number of events discarded because a subscriber was too slow
+func (l *LinkedList) Dropped() int64 {
+	return l.stream.Dropped()
+}
+
+// Close ends every subscription
+func (l *LinkedList) Close() {
+	l.stream.Close()
+}
+
+// Add an element at the end of the list
+func (l *LinkedList) Add(data interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	index := l.list.Length()
+	l.list.Add(data)
+	l.stream.publish(Event[interface{}]{Op: OpAdd, Index: index, New: data})
+}
+
+// Remove the first occurrence of an element from the list
+// No event is published when the element is absent.
+func (l *LinkedList) Remove(data interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	index := l.list.IndexOf(data)
+	if index < 0 {
+		return
+	}
+	l.list.Remove(data)
+	l.stream.publish(Event[interface{}]{Op: OpRemove, Index: index, Old: data})
+}
+
+// Find an element in the list
+func (l *LinkedList) Find(data interface{}) *linkedlist.Node {
+	return l.list.Find(data)
+}
+
+// Length returns the number of elements in the list
+func (l *LinkedList) Length() int {
+	return l.list.Length()
+}
+
+// Values returns the elements of the list in order
+func (l *LinkedList) Values() []interface{} {
+	return l.list.Values()
+}
@@ -0,0 +1,34 @@
+package cdc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vzahanych/data-structures/linkedlist"
+)
+
+func TestLinkedListEvents(t *testing.T) {
+	l := WrapLinkedList(linkedlist.NewLinkedList(), Config{Buffer: 2, Backpressure: DropNewest})
+	events := l.Subscribe(context.Background())
+	defer l.Close()
+
+	l.Add("a")
+	l.Add(2)
+	l.Remove("z") // Absent, nothing published
+	l.Remove("a") // Dropped, the buffer is full
+
+	assert.Equal(t, []Event[interface{}]{
+		{Seq: 1, Op: OpAdd, Index: 0, New: "a"},
+		{Seq: 2, Op: OpAdd, Index: 1, New: 2},
+	}, drain(events))
+	assert.Equal(t, int64(1), l.Dropped())
+
+	l.Remove(2)
+	assert.Equal(t, []Event[interface{}]{
+		{Seq: 4, Op: OpRemove, Index: 0, Old: 2},
+	}, drain(events))
+	assert.Equal(t, 0, l.Length())
+	assert.Empty(t, l.Values())
+	assert.Nil(t, l.Find(2))
+}
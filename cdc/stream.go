@@ -0,0 +1,258 @@
+package cdc
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+// Op identifies the mutation an event describes
+type Op int
+
+const (
+	OpAppend Op = iota
+	OpDelete
+	OpResize
+	OpAdd
+	OpRemove
+)
+
+func (op Op) String() string {
+	switch op {
+	case OpAppend:
+		return "append"
+	case OpDelete:
+		return "delete"
+	case OpResize:
+		return "resize"
+	case OpAdd:
+		return "add"
+	case OpRemove:
+		return "remove"
+	}
+	return "unknown"
+}
+
+// Event describes a single mutation of a container
+// Old is the zero value for insertions and New for removals. Capacity and
+// OldCapacity are only set for OpResize.
+type Event[T any] struct {
+	// Seq numbers the events of a stream from 1, a gap tells a subscriber that events were dropped
+	Seq         uint64
+	Op          Op
+	Index       int
+	Old         T
+	New         T
+	OldCapacity int
+	Capacity    int
+}
+
+// Backpressure decides what happens when a subscriber's buffer is full
+type Backpressure int
+
+const (
+	// Block makes the mutation wait until the subscriber has room or unsubscribes
+	Block Backpressure = iota
+	// DropOldest discards the oldest buffered event to make room
+	DropOldest
+	// DropNewest discards the new event
+	DropNewest
+)
+
+const defaultBuffer = 64
+
+type Config struct {
+	// Buffer is the number of events buffered per subscriber (default 64)
+	Buffer         int
+	Backpressure   Backpressure
+	MetricsEnabled bool
+}
+
+// subscriber is a subscription, its lock is held while sending so that the channel is never closed during a send
+type subscriber[T any] struct {
+	mu     sync.Mutex
+	events chan Event[T]
+	ctx    context.Context
+	closed bool
+}
+
+// close closes the channel of the subscription, waiting for a send in progress
+func (sub *subscriber[T]) close() {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+
+	if !sub.closed {
+		sub.closed = true
+		close(sub.events)
+	}
+}
+
+// Stream delivers the events of a container to its subscribers
+type Stream[T any] struct {
+	mu               sync.Mutex
+	publishMu        sync.Mutex // Serializes publishers so that events are delivered in sequence order
+	subscribers      map[*subscriber[T]]struct{}
+	seq              uint64
+	dropped          atomic.Int64
+	closed           bool
+	done             chan struct{} // Closed by Close, releases the goroutines waiting on the stream
+	config           Config
+	publishedCounter metrics.Counter
+	droppedCounter   metrics.Counter
+}
+
+func newStream[T any](config Config) *Stream[T] {
+	if config.Buffer <= 0 {
+		config.Buffer = defaultBuffer
+	}
+	s := &Stream[T]{subscribers: make(map[*subscriber[T]]struct{}), done: make(chan struct{}), config: config}
+
+	// Initialize the metrics only if enabled in the config
+	if s.config.MetricsEnabled {
+		s.publishedCounter = metrics.NewCounter()
+		s.droppedCounter = metrics.NewCounter()
+		metrics.DefaultRegistry.Register("cdc.published", s.publishedCounter)
+		metrics.DefaultRegistry.Register("cdc.dropped", s.droppedCounter)
+	}
+
+	return s
+}
+
+// Subscribe returns a channel receiving the events published from now on
+// The channel is closed when ctx is done or the stream is closed.
+func (s *Stream[T]) Subscribe(ctx context.Context) <-chan Event[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sub := &subscriber[T]{events: make(chan Event[T], s.config.Buffer), ctx: ctx}
+	if s.closed {
+		sub.close()
+		return sub.events
+	}
+	s.subscribers[sub] = struct{}{}
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-s.done:
+			// Close ends the subscription itself
+			return
+		}
+
+		s.mu.Lock()
+		_, found := s.subscribers[sub]
+		delete(s.subscribers, sub)
+		s.mu.Unlock()
+
+		// The stream may have been closed in the meantime
+		if found {
+			sub.close()
+		}
+	}()
+	return sub.events
+}
+
+// Dropped returns the number of events discarded because a subscriber was too slow
+func (s *Stream[T]) Dropped() int64 {
+	return s.dropped.Load()
+}
+
+// Close ends every subscription and releases the publishers blocked on a subscriber
+func (s *Stream[T]) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	close(s.done)
+	subscribers := s.subscribers
+	s.subscribers = make(map[*subscriber[T]]struct{})
+	s.mu.Unlock()
+
+	for sub := range subscribers {
+		sub.close()
+	}
+}
+
+// publish numbers e and hands it to every subscriber according to the backpressure policy
+// The events are sent without holding the lock of the stream, so a slow
+// subscriber only holds up the publishers.
+func (s *Stream[T]) publish(e Event[T]) {
+	s.publishMu.Lock()
+	defer s.publishMu.Unlock()
+
+	s.mu.Lock()
+	if len(s.subscribers) == 0 {
+		s.mu.Unlock()
+		return
+	}
+	s.seq++
+	e.Seq = s.seq
+	subscribers := make([]*subscriber[T], 0, len(s.subscribers))
+	for sub := range s.subscribers {
+		subscribers = append(subscribers, sub)
+	}
+	s.mu.Unlock()
+
+	for _, sub := range subscribers {
+		s.deliver(sub, e)
+	}
+
+	// Track metrics if enabled
+	if s.config.MetricsEnabled {
+		s.publishedCounter.Inc(1)
+	}
+}
+
+// deliver sends e to sub unless the subscription ended
+func (s *Stream[T]) deliver(sub *subscriber[T], e Event[T]) {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+
+	if sub.closed {
+		return
+	}
+	switch s.config.Backpressure {
+	case Block:
+		select {
+		case sub.events <- e:
+		case <-sub.ctx.Done():
+			// Unsubscribing, the event is not needed anymore
+		case <-s.done:
+			// Closing, the event is not needed anymore
+		}
+	case DropNewest:
+		select {
+		case sub.events <- e:
+		default:
+			s.drop()
+		}
+	case DropOldest:
+		for sent := false; !sent; {
+			select {
+			case sub.events <- e:
+				sent = true
+			default:
+				select {
+				case <-sub.events:
+					s.drop()
+				default:
+					// The subscriber just made room
+				}
+			}
+		}
+	}
+}
+
+// drop counts a discarded event
+func (s *Stream[T]) drop() {
+	s.dropped.Add(1)
+
+	// Track metrics if enabled
+	if s.config.MetricsEnabled {
+		s.droppedCounter.Inc(1)
+	}
+}
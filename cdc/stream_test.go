@@ -0,0 +1,178 @@
+package cdc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func drain[T any](events <-chan Event[T]) []Event[T] {
+	var result []Event[T]
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				return result
+			}
+			result = append(result, e)
+		default:
+			return result
+		}
+	}
+}
+
+func publishValues(s *Stream[int], values ...int) {
+	for _, v := range values {
+		s.publish(Event[int]{Op: OpAppend, New: v})
+	}
+}
+
+func newValues(events []Event[int]) []int {
+	result := make([]int, 0, len(events))
+	for _, e := range events {
+		result = append(result, e.New)
+	}
+	return result
+}
+
+func TestDropNewest(t *testing.T) {
+	s := newStream[int](Config{Buffer: 2, Backpressure: DropNewest})
+	events := s.Subscribe(context.Background())
+
+	publishValues(s, 1, 2, 3, 4)
+	received := drain(events)
+	assert.Equal(t, []int{1, 2}, newValues(received))
+	assert.Equal(t, []uint64{1, 2}, []uint64{received[0].Seq, received[1].Seq})
+	assert.Equal(t, int64(2), s.Dropped())
+}
+
+func TestDropOldest(t *testing.T) {
+	s := newStream[int](Config{Buffer: 2, Backpressure: DropOldest})
+	events := s.Subscribe(context.Background())
+
+	publishValues(s, 1, 2, 3, 4)
+	received := drain(events)
+	assert.Equal(t, []int{3, 4}, newValues(received))
+	assert.Equal(t, uint64(3), received[0].Seq) // The gap reveals the drops
+	assert.Equal(t, int64(2), s.Dropped())
+}
+
+func TestBlock(t *testing.T) {
+	s := newStream[int](Config{Buffer: 1, Backpressure: Block})
+	events := s.Subscribe(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		publishValues(s, 1, 2, 3)
+		close(done)
+	}()
+
+	// The publisher waits for the subscriber instead of dropping
+	var received []int
+	for len(received) < 3 {
+		received = append(received, (<-events).New)
+	}
+	<-done
+	assert.Equal(t, []int{1, 2, 3}, received)
+	assert.Equal(t, int64(0), s.Dropped())
+}
+
+func TestBlockedPublisherReleasedOnCancel(t *testing.T) {
+	s := newStream[int](Config{Buffer: 1, Backpressure: Block})
+	ctx, cancel := context.WithCancel(context.Background())
+	s.Subscribe(ctx)
+
+	done := make(chan struct{})
+	go func() {
+		publishValues(s, 1, 2)
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("publisher did not block")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("publisher still blocked after unsubscribe")
+	}
+}
+
+func TestBlockedPublisherReleasedOnClose(t *testing.T) {
+	s := newStream[int](Config{Buffer: 1, Backpressure: Block})
+	events := s.Subscribe(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		publishValues(s, 1, 2)
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("publisher did not block")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	// The stream stays usable while the publisher waits
+	assert.Equal(t, int64(0), s.Dropped())
+
+	closed := make(chan struct{})
+	go func() {
+		s.Close()
+		close(closed)
+	}()
+	for _, ch := range []chan struct{}{closed, done} {
+		select {
+		case <-ch:
+		case <-time.After(time.Second):
+			t.Fatal("publisher still blocked after close")
+		}
+	}
+	assert.Equal(t, []int{1}, newValues(drain(events)))
+}
+
+func TestUnsubscribe(t *testing.T) {
+	s := newStream[int](Config{})
+	ctx, cancel := context.WithCancel(context.Background())
+	events := s.Subscribe(ctx)
+	other := s.Subscribe(context.Background())
+
+	cancel()
+	for range events {
+		// Wait for the channel to be closed
+	}
+	publishValues(s, 1)
+	assert.Equal(t, []int{1}, newValues(drain(other)))
+
+	s.Close()
+	_, ok := <-other
+	assert.False(t, ok)
+
+	// Subscribing to a closed stream returns a closed channel
+	_, ok = <-s.Subscribe(context.Background())
+	assert.False(t, ok)
+}
+
+func TestMetrics(t *testing.T) {
+	s := newStream[int](Config{Buffer: 1, Backpressure: DropNewest, MetricsEnabled: true})
+	s.Subscribe(context.Background())
+
+	publishValues(s, 1, 2, 3)
+	assert.Equal(t, int64(3), s.publishedCounter.Count())
+	assert.Equal(t, int64(2), s.droppedCounter.Count())
+}
+
+func BenchmarkPublish(b *testing.B) {
+	s := newStream[int](Config{Buffer: 1024, Backpressure: DropOldest})
+	s.Subscribe(context.Background())
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.publish(Event[int]{Op: OpAppend, New: i})
+	}
+}
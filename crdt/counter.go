@@ -0,0 +1,182 @@
+package crdt
+
+import (
+	"maps"
+	"sync"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+// counts maps every replica to the total it added
+type counts map[string]uint64
+
+// join keeps the highest total of every replica and returns the totals that increased
+func (c counts) join(other counts) counts {
+	increased := counts{}
+	for replica, n := range other {
+		if n > c[replica] {
+			c[replica] = n
+			increased[replica] = n
+		}
+	}
+	return increased
+}
+
+func (c counts) sum() uint64 {
+	var total uint64
+	for _, n := range c {
+		total += n
+	}
+	return total
+}
+
+// GCounter is a grow-only counter
+// Every replica only increments its own entry and merging keeps the highest
+// entry of every replica, so replicas converge to the sum of all increments.
+type GCounter struct {
+	replica      string
+	counts       counts
+	delta        counts
+	mu           sync.RWMutex
+	config       Config
+	mergeCounter metrics.Counter
+}
+
+// NewGCounter creates a zero counter for replica
+// The config parameter is used to enable or disable metrics collection
+func NewGCounter(replica string, config Config) *GCounter {
+	g := &GCounter{replica: replica, counts: counts{}, delta: counts{}, config: config}
+
+	// Initialize the metrics only if enabled in the config
+	if g.config.MetricsEnabled {
+		g.mergeCounter = metrics.NewCounter()
+		metrics.DefaultRegistry.Register("crdt.gcounter.merge", g.mergeCounter)
+	}
+
+	return g
+}
+
+// Inc adds n to the counter
+func (g *GCounter) Inc(n uint64) {
+	g.mu.Lock() // Lock for writing
+	defer g.mu.Unlock()
+
+	g.counts[g.replica] += n
+	g.delta[g.replica] = g.counts[g.replica]
+}
+
+// Value returns the sum of the increments of all replicas
+func (g *GCounter) Value() uint64 {
+	g.mu.RLock() // Lock for reading
+	defer g.mu.RUnlock()
+
+	return g.counts.sum()
+}
+
+// Delta returns the changes since the previous call to Delta, to be merged into other replicas
+func (g *GCounter) Delta() *GCounter {
+	g.mu.Lock() // Lock for writing
+	defer g.mu.Unlock()
+
+	delta := &GCounter{replica: g.replica, counts: g.delta, delta: counts{}}
+	g.delta = counts{}
+	return delta
+}
+
+// Merge incorporates the state or a delta of another replica
+func (g *GCounter) Merge(other *GCounter) {
+	other.mu.RLock() // Lock for reading
+	theirs := maps.Clone(other.counts)
+	other.mu.RUnlock()
+
+	g.mu.Lock() // Lock for writing
+	defer g.mu.Unlock()
+
+	// What was new is relayed with the next delta
+	g.delta.join(g.counts.join(theirs))
+
+	// Track metrics if enabled
+	if g.config.MetricsEnabled {
+		g.mergeCounter.Inc(1)
+	}
+}
+
+// PNCounter is a counter supporting decrements, made of one grow-only counter for increments and one for decrements
+type PNCounter struct {
+	replica      string
+	p, n         counts
+	deltaP       counts
+	deltaN       counts
+	mu           sync.RWMutex
+	config       Config
+	mergeCounter metrics.Counter
+}
+
+// NewPNCounter creates a zero counter for replica
+// The config parameter is used to enable or disable metrics collection
+func NewPNCounter(replica string, config Config) *PNCounter {
+	c := &PNCounter{replica: replica, p: counts{}, n: counts{}, deltaP: counts{}, deltaN: counts{}, config: config}
+
+	// Initialize the metrics only if enabled in the config
+	if c.config.MetricsEnabled {
+		c.mergeCounter = metrics.NewCounter()
+		metrics.DefaultRegistry.Register("crdt.pncounter.merge", c.mergeCounter)
+	}
+
+	return c
+}
+
+// Inc adds n to the counter
+func (c *PNCounter) Inc(n uint64) {
+	c.mu.Lock() // Lock for writing
+	defer c.mu.Unlock()
+
+	c.p[c.replica] += n
+	c.deltaP[c.replica] = c.p[c.replica]
+}
+
+// Dec subtracts n from the counter
+func (c *PNCounter) Dec(n uint64) {
+	c.mu.Lock() // Lock for writing
+	defer c.mu.Unlock()
+
+	c.n[c.replica] += n
+	c.deltaN[c.replica] = c.n[c.replica]
+}
+
+// Value returns the increments minus the decrements of all replicas
+func (c *PNCounter) Value() int64 {
+	c.mu.RLock() // Lock for reading
+	defer c.mu.RUnlock()
+
+	return int64(c.p.sum() - c.n.sum())
+}
+
+// Delta returns the changes since the previous call to Delta, to be merged into other replicas
+func (c *PNCounter) Delta() *PNCounter {
+	c.mu.Lock() // Lock for writing
+	defer c.mu.Unlock()
+
+	delta := &PNCounter{replica: c.replica, p: c.deltaP, n: c.deltaN, deltaP: counts{}, deltaN: counts{}}
+	c.deltaP, c.deltaN = counts{}, counts{}
+	return delta
+}
+
+// Merge incorporates the state or a delta of another replica
+func (c *PNCounter) Merge(other *PNCounter) {
+	other.mu.RLock() // Lock for reading
+	p, n := maps.Clone(other.p), maps.Clone(other.n)
+	other.mu.RUnlock()
+
+	c.mu.Lock() // Lock for writing
+	defer c.mu.Unlock()
+
+	// What was new is relayed with the next delta
+	c.deltaP.join(c.p.join(p))
+	c.deltaN.join(c.n.join(n))
+
+	// Track metrics if enabled
+	if c.config.MetricsEnabled {
+		c.mergeCounter.Inc(1)
+	}
+}
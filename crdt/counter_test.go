@@ -0,0 +1,119 @@
+package crdt
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGCounter(t *testing.T) {
+	a := NewGCounter("a", Config{})
+	b := NewGCounter("b", Config{})
+	a.Inc(3)
+	b.Inc(2)
+	b.Inc(1)
+	assert.Equal(t, uint64(3), a.Value())
+
+	a.Merge(b)
+	b.Merge(a)
+	assert.Equal(t, uint64(6), a.Value())
+	assert.Equal(t, uint64(6), b.Value())
+
+	// Merging again is idempotent
+	a.Merge(b)
+	assert.Equal(t, uint64(6), a.Value())
+}
+
+func TestGCounterDelta(t *testing.T) {
+	a := NewGCounter("a", Config{})
+	b := NewGCounter("b", Config{})
+	a.Inc(1)
+	a.Inc(4)
+	delta := a.Delta()
+	assert.Equal(t, counts{"a": 5}, delta.counts)
+	assert.Empty(t, a.Delta().counts)
+
+	b.Inc(2)
+	b.Merge(delta)
+	assert.Equal(t, uint64(7), b.Value())
+
+	// A stale delta delivered late does not undo newer increments
+	a.Inc(1)
+	b.Merge(a.Delta())
+	b.Merge(delta)
+	assert.Equal(t, uint64(8), b.Value())
+}
+
+func TestPNCounter(t *testing.T) {
+	a := NewPNCounter("a", Config{})
+	b := NewPNCounter("b", Config{})
+	a.Inc(5)
+	a.Dec(2)
+	b.Dec(4)
+	assert.Equal(t, int64(3), a.Value())
+	assert.Equal(t, int64(-4), b.Value())
+
+	a.Merge(b.Delta())
+	b.Merge(a.Delta())
+	assert.Equal(t, int64(-1), a.Value())
+	assert.Equal(t, int64(-1), b.Value())
+	assert.Empty(t, a.Delta().p)
+}
+
+func TestCounterConvergence(t *testing.T) {
+	t.Run("GCounter", func(t *testing.T) {
+		testConvergence(t, replicated[*GCounter]{
+			create: func(replica string) *GCounter { return NewGCounter(replica, Config{}) },
+			update: func(rng *rand.Rand, g *GCounter) { g.Inc(rng.Uint64N(10)) },
+			delta:  (*GCounter).Delta,
+			merge:  (*GCounter).Merge,
+			view:   func(g *GCounter) any { return g.Value() },
+		})
+	})
+
+	t.Run("PNCounter", func(t *testing.T) {
+		testConvergence(t, replicated[*PNCounter]{
+			create: func(replica string) *PNCounter { return NewPNCounter(replica, Config{}) },
+			update: func(rng *rand.Rand, c *PNCounter) {
+				if rng.IntN(2) == 0 {
+					c.Inc(rng.Uint64N(10))
+				} else {
+					c.Dec(rng.Uint64N(10))
+				}
+			},
+			delta: (*PNCounter).Delta,
+			merge: (*PNCounter).Merge,
+			view:  func(c *PNCounter) any { return c.Value() },
+		})
+	})
+}
+
+func TestCounterConvergenceValue(t *testing.T) {
+	// Every replica ends up with the sum of all the increments
+	var total uint64
+	states := simulate(7, replicated[*GCounter]{
+		create: func(replica string) *GCounter { return NewGCounter(replica, Config{}) },
+		update: func(rng *rand.Rand, g *GCounter) {
+			n := rng.Uint64N(10)
+			total += n
+			g.Inc(n)
+		},
+		delta: (*GCounter).Delta,
+		merge: (*GCounter).Merge,
+	}, 5, 500, line(5))
+	for _, g := range states {
+		assert.Equal(t, total, g.Value())
+	}
+}
+
+func TestCounterMetrics(t *testing.T) {
+	g := NewGCounter("a", Config{MetricsEnabled: true})
+	g.Merge(NewGCounter("b", Config{}))
+	g.Merge(NewGCounter("c", Config{}))
+	assert.Equal(t, int64(2), g.mergeCounter.Count())
+
+	c := NewPNCounter("a", Config{MetricsEnabled: true})
+	c.Merge(NewPNCounter("b", Config{}))
+	assert.Equal(t, int64(1), c.mergeCounter.Count())
+}
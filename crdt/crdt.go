@@ -0,0 +1,99 @@
+package crdt
+
+import "errors"
+
+// ErrIndexOutOfBounds is returned by the RGA operations taking an index
+var ErrIndexOutOfBounds = errors.New("index out of bounds")
+
+// Config is shared by all the CRDT types
+type Config struct {
+	MetricsEnabled bool
+}
+
+// dot identifies a single event: the n-th event of a replica
+type dot struct {
+	replica string
+	counter uint64
+}
+
+// causalContext is the set of events a replica has seen
+// It is a version vector plus the dots received out of order, which deltas
+// produce.
+type causalContext struct {
+	vv    map[string]uint64
+	cloud map[dot]struct{}
+}
+
+func newCausalContext() causalContext {
+	return causalContext{vv: make(map[string]uint64), cloud: make(map[dot]struct{})}
+}
+
+func (c causalContext) contains(d dot) bool {
+	if d.counter <= c.vv[d.replica] {
+		return true
+	}
+	_, ok := c.cloud[d]
+	return ok
+}
+
+// next returns a new dot of replica
+func (c causalContext) next(replica string) dot {
+	return dot{replica: replica, counter: c.vv[replica] + 1}
+}
+
+func (c causalContext) add(d dot) {
+	c.cloud[d] = struct{}{}
+	c.compact()
+}
+
+// join adds the events of other and reports whether any of them was new
+func (c causalContext) join(other causalContext) bool {
+	changed := false
+	for replica, counter := range other.vv {
+		if counter > c.vv[replica] {
+			c.vv[replica] = counter
+			changed = true
+		}
+	}
+	for d := range other.cloud {
+		if !c.contains(d) {
+			c.cloud[d] = struct{}{}
+			changed = true
+		}
+	}
+	c.compact()
+	return changed
+}
+
+// compact moves the dots extending the version vector out of the cloud
+func (c causalContext) compact() {
+	for progress := true; progress; {
+		progress = false
+		for d := range c.cloud {
+			switch {
+			case d.counter == c.vv[d.replica]+1:
+				c.vv[d.replica] = d.counter
+				delete(c.cloud, d)
+				progress = true
+			case d.counter <= c.vv[d.replica]:
+				delete(c.cloud, d)
+			}
+		}
+	}
+}
+
+func (c causalContext) clone() causalContext {
+	clone := newCausalContext()
+	clone.join(c)
+	return clone
+}
+
+// stamp is a Lamport timestamp, the replica breaks ties so that all replicas order stamps the same way
+type stamp struct {
+	time    uint64
+	replica string
+}
+
+func (s stamp) after(other stamp) bool {
+	return s.time > other.time || s.time == other.time && s.replica > other.replica
+}
@@ -0,0 +1,159 @@
+package crdt
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// replicated describes how the simulation drives a CRDT type
+type replicated[S any] struct {
+	create func(replica string) S
+	update func(rng *rand.Rand, s S)
+	delta  func(s S) S
+	merge  func(s, other S)
+	view   func(s S) any // Observable value, equal on replicas that converged
+}
+
+// message is a delta in flight to a replica
+type message[S any] struct {
+	to    int
+	delta S
+}
+
+// mesh connects every replica to all the others
+func mesh(replicas int) func(i int) []int {
+	return func(i int) []int {
+		var peers []int
+		for to := 0; to < replicas; to++ {
+			if to != i {
+				peers = append(peers, to)
+			}
+		}
+		return peers
+	}
+}
+
+// line connects every replica to its neighbours only, so deltas have to be relayed
+func line(replicas int) func(i int) []int {
+	return func(i int) []int {
+		var peers []int
+		if i > 0 {
+			peers = append(peers, i-1)
+		}
+		if i < replicas-1 {
+			peers = append(peers, i+1)
+		}
+		return peers
+	}
+}
+
+// simulate runs replicas updating concurrently and exchanging deltas with
+// their peers over a network that delays, reorders and duplicates them, then
+// lets the deltas propagate until every replica received everything and
+// returns the replicas
+func simulate[S any](seed uint64, c replicated[S], replicas, steps int, peers func(i int) []int) []S {
+	rng := rand.New(rand.NewPCG(seed, 2))
+	states := make([]S, replicas)
+	for i := range states {
+		states[i] = c.create(fmt.Sprintf("r%d", i))
+	}
+
+	var inFlight []message[S]
+	for step := 0; step < steps; step++ {
+		i := rng.IntN(replicas)
+		switch rng.IntN(4) {
+		case 0, 1:
+			c.update(rng, states[i])
+		case 2:
+			delta := c.delta(states[i])
+			for _, to := range peers(i) {
+				inFlight = append(inFlight, message[S]{to: to, delta: delta})
+			}
+		case 3:
+			if len(inFlight) == 0 {
+				continue
+			}
+			k := rng.IntN(len(inFlight))
+			m := inFlight[k]
+			c.merge(states[m.to], m.delta)
+			// Keep some messages around to be delivered again
+			if rng.IntN(4) != 0 {
+				inFlight[k] = inFlight[len(inFlight)-1]
+				inFlight = inFlight[:len(inFlight)-1]
+			}
+		}
+	}
+
+	// Every round carries the changes at least one hop further
+	for round := 0; round < replicas; round++ {
+		for i := range states {
+			delta := c.delta(states[i])
+			for _, to := range peers(i) {
+				inFlight = append(inFlight, message[S]{to: to, delta: delta})
+			}
+		}
+		rng.Shuffle(len(inFlight), func(i, j int) { inFlight[i], inFlight[j] = inFlight[j], inFlight[i] })
+		for _, m := range inFlight {
+			c.merge(states[m.to], m.delta)
+		}
+		inFlight = nil
+	}
+	return states
+}
+
+// testConvergence checks that replicas end up equal whether they exchange
+// deltas or full states, in any order, and whether they are all connected or
+// relay the deltas they receive
+func testConvergence[S any](t *testing.T, c replicated[S]) {
+	topologies := map[string]func(i int) []int{"mesh": mesh(4), "line": line(4)}
+	for seed := uint64(1); seed <= 20; seed++ {
+		for name, peers := range topologies {
+			states := simulate(seed, c, 4, 400, peers)
+			for _, s := range states[1:] {
+				assert.Equal(t, c.view(states[0]), c.view(s), "seed %d, %s", seed, name)
+			}
+
+			// Full states merged in a different order on a fresh replica give the same result
+			rng := rand.New(rand.NewPCG(seed, 3))
+			fresh := c.create("observer")
+			for _, i := range rng.Perm(len(states)) {
+				c.merge(fresh, states[i])
+				c.merge(fresh, states[i])
+			}
+			assert.Equal(t, c.view(states[0]), c.view(fresh), "seed %d, %s", seed, name)
+		}
+	}
+}
+
+func TestCausalContext(t *testing.T) {
+	c := newCausalContext()
+	c.add(dot{replica: "a", counter: 1})
+	c.add(dot{replica: "a", counter: 3})
+	assert.True(t, c.contains(dot{replica: "a", counter: 1}))
+	assert.False(t, c.contains(dot{replica: "a", counter: 2}))
+	assert.True(t, c.contains(dot{replica: "a", counter: 3}))
+	assert.Equal(t, dot{replica: "a", counter: 2}, c.next("a"))
+	assert.Len(t, c.cloud, 1)
+
+	// Filling the gap compacts the cloud into the version vector
+	other := newCausalContext()
+	other.add(dot{replica: "a", counter: 1})
+	other.add(dot{replica: "a", counter: 2})
+	other.add(dot{replica: "b", counter: 1})
+	c.join(other)
+	assert.Equal(t, map[string]uint64{"a": 3, "b": 1}, c.vv)
+	assert.Empty(t, c.cloud)
+
+	clone := c.clone()
+	clone.add(dot{replica: "a", counter: 4})
+	assert.False(t, c.contains(dot{replica: "a", counter: 4}))
+}
+
+func TestStampOrder(t *testing.T) {
+	assert.True(t, stamp{time: 2, replica: "a"}.after(stamp{time: 1, replica: "b"}))
+	assert.True(t, stamp{time: 1, replica: "b"}.after(stamp{time: 1, replica: "a"}))
+	assert.False(t, stamp{time: 1, replica: "a"}.after(stamp{time: 1, replica: "a"}))
+}
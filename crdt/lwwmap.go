@@ -0,0 +1,146 @@
+package crdt
+
+import (
+	"maps"
+	"sync"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+// lwwEntry is the last write of a key, a delete leaves a tombstone so that it can win over older writes
+type lwwEntry[V any] struct {
+	value   V
+	stamp   stamp
+	deleted bool
+}
+
+// LWWMap is a last-writer-wins map
+// Every write is stamped with a Lamport clock, which merges advance past every
+// stamp they receive, so a write always wins over the writes its replica has
+// seen. Concurrent writes of a key are ordered by stamp and then by replica.
+type LWWMap[K comparable, V any] struct {
+	replica      string
+	clock        uint64
+	entries      map[K]lwwEntry[V]
+	delta        map[K]lwwEntry[V]
+	mu           sync.RWMutex
+	config       Config
+	mergeCounter metrics.Counter
+}
+
+// NewLWWMap creates an empty map for replica
+// The config parameter is used to enable or disable metrics collection
+func NewLWWMap[K comparable, V any](replica string, config Config) *LWWMap[K, V] {
+	m := &LWWMap[K, V]{replica: replica, entries: make(map[K]lwwEntry[V]), delta: make(map[K]lwwEntry[V]), config: config}
+
+	// Initialize the metrics only if enabled in the config
+	if m.config.MetricsEnabled {
+		m.mergeCounter = metrics.NewCounter()
+		metrics.DefaultRegistry.Register("crdt.lwwmap.merge", m.mergeCounter)
+	}
+
+	return m
+}
+
+// Set associates value with key
+func (m *LWWMap[K, V]) Set(key K, value V) {
+	m.mu.Lock() // Lock for writing
+	defer m.mu.Unlock()
+
+	m.write(key, lwwEntry[V]{value: value})
+}
+
+// Delete removes key, it returns false when key is not in the map
+func (m *LWWMap[K, V]) Delete(key K) bool {
+	m.mu.Lock() // Lock for writing
+	defer m.mu.Unlock()
+
+	if e, ok := m.entries[key]; !ok || e.deleted {
+		return false
+	}
+	m.write(key, lwwEntry[V]{deleted: true})
+	return true
+}
+
+// write stamps e and records it in the entries and the pending delta
+func (m *LWWMap[K, V]) write(key K, e lwwEntry[V]) {
+	m.clock++
+	e.stamp = stamp{time: m.clock, replica: m.replica}
+	m.entries[key] = e
+	m.delta[key] = e
+}
+
+// Get returns the value associated with key
+func (m *LWWMap[K, V]) Get(key K) (V, bool) {
+	m.mu.RLock() // Lock for reading
+	defer m.mu.RUnlock()
+
+	e, ok := m.entries[key]
+	if !ok || e.deleted {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Len returns the number of keys
+func (m *LWWMap[K, V]) Len() int {
+	m.mu.RLock() // Lock for reading
+	defer m.mu.RUnlock()
+
+	n := 0
+	for _, e := range m.entries {
+		if !e.deleted {
+			n++
+		}
+	}
+	return n
+}
+
+// Keys returns the keys in no particular order
+func (m *LWWMap[K, V]) Keys() []K {
+	m.mu.RLock() // Lock for reading
+	defer m.mu.RUnlock()
+
+	keys := make([]K, 0, len(m.entries))
+	for k, e := range m.entries {
+		if !e.deleted {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// Delta returns the changes since the previous call to Delta, to be merged into other replicas
+func (m *LWWMap[K, V]) Delta() *LWWMap[K, V] {
+	m.mu.Lock() // Lock for writing
+	defer m.mu.Unlock()
+
+	delta := &LWWMap[K, V]{replica: m.replica, clock: m.clock, entries: m.delta, delta: make(map[K]lwwEntry[V])}
+	m.delta = make(map[K]lwwEntry[V])
+	return delta
+}
+
+// Merge incorporates the state or a delta of another replica
+func (m *LWWMap[K, V]) Merge(other *LWWMap[K, V]) {
+	other.mu.RLock() // Lock for reading
+	theirs := maps.Clone(other.entries)
+	other.mu.RUnlock()
+
+	m.mu.Lock() // Lock for writing
+	defer m.mu.Unlock()
+
+	for k, e := range theirs {
+		if mine, ok := m.entries[k]; !ok || e.stamp.after(mine.stamp) {
+			// The winning writes are relayed with the next delta
+			m.entries[k] = e
+			m.delta[k] = e
+		}
+		m.clock = max(m.clock, e.stamp.time)
+	}
+
+	// Track metrics if enabled
+	if m.config.MetricsEnabled {
+		m.mergeCounter.Inc(1)
+	}
+}
@@ -0,0 +1,105 @@
+package crdt
+
+import (
+	"maps"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLWWMap(t *testing.T) {
+	m := NewLWWMap[string, int]("a", Config{})
+	m.Set("x", 1)
+	m.Set("y", 2)
+	m.Set("x", 3)
+	v, ok := m.Get("x")
+	assert.True(t, ok)
+	assert.Equal(t, 3, v)
+	assert.Equal(t, 2, m.Len())
+	assert.ElementsMatch(t, []string{"x", "y"}, m.Keys())
+
+	assert.True(t, m.Delete("x"))
+	assert.False(t, m.Delete("x"))
+	assert.False(t, m.Delete("z"))
+	_, ok = m.Get("x")
+	assert.False(t, ok)
+	assert.Equal(t, 1, m.Len())
+	assert.Equal(t, []string{"y"}, m.Keys())
+}
+
+func TestLWWMapLastWriterWins(t *testing.T) {
+	a := NewLWWMap[string, string]("a", Config{})
+	b := NewLWWMap[string, string]("b", Config{})
+
+	// Concurrent writes with the same stamp are ordered by replica
+	a.Set("k", "from a")
+	b.Set("k", "from b")
+	a.Merge(b)
+	b.Merge(a)
+	v, _ := a.Get("k")
+	assert.Equal(t, "from b", v)
+	v, _ = b.Get("k")
+	assert.Equal(t, "from b", v)
+
+	// A write after a merge wins over everything the replica has seen
+	a.Set("k", "later")
+	b.Merge(a)
+	v, _ = b.Get("k")
+	assert.Equal(t, "later", v)
+
+	// The same holds for deletes
+	b.Delete("k")
+	a.Merge(b)
+	_, ok := a.Get("k")
+	assert.False(t, ok)
+}
+
+func TestLWWMapDelta(t *testing.T) {
+	a := NewLWWMap[string, int]("a", Config{})
+	b := NewLWWMap[string, int]("b", Config{})
+	a.Set("x", 1)
+	a.Set("y", 2)
+	first := a.Delta()
+	assert.Equal(t, 2, first.Len())
+	assert.Equal(t, 0, a.Delta().Len())
+
+	a.Set("x", 3)
+	a.Delete("y")
+	second := a.Delta()
+	assert.Equal(t, 1, second.Len())
+
+	// The older delta arriving late does not override the newer writes
+	b.Merge(second)
+	b.Merge(first)
+	v, _ := b.Get("x")
+	assert.Equal(t, 3, v)
+	_, ok := b.Get("y")
+	assert.False(t, ok)
+}
+
+func TestLWWMapConvergence(t *testing.T) {
+	testConvergence(t, replicated[*LWWMap[int, int]]{
+		create: func(replica string) *LWWMap[int, int] { return NewLWWMap[int, int](replica, Config{}) },
+		update: func(rng *rand.Rand, m *LWWMap[int, int]) {
+			if k := rng.IntN(8); rng.IntN(4) == 0 {
+				m.Delete(k)
+			} else {
+				m.Set(k, rng.IntN(100))
+			}
+		},
+		delta: (*LWWMap[int, int]).Delta,
+		merge: (*LWWMap[int, int]).Merge,
+		view: func(m *LWWMap[int, int]) any {
+			m.mu.RLock()
+			defer m.mu.RUnlock()
+			return maps.Clone(m.entries)
+		},
+	})
+}
+
+func TestLWWMapMetrics(t *testing.T) {
+	m := NewLWWMap[string, int]("a", Config{MetricsEnabled: true})
+	m.Merge(NewLWWMap[string, int]("b", Config{}))
+	assert.Equal(t, int64(1), m.mergeCounter.Count())
+}
@@ -0,0 +1,186 @@
+package crdt
+
+import (
+	"maps"
+	"sync"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+// orState is the state of an observed-remove set: the dots supporting every
+// element and the context of all the dots seen, including the removed ones
+type orState[T comparable] struct {
+	entries map[T]map[dot]struct{}
+	context causalContext
+}
+
+func newORState[T comparable]() orState[T] {
+	return orState[T]{entries: make(map[T]map[dot]struct{}), context: newCausalContext()}
+}
+
+// join keeps the dots both sides have and the dots one side has that the other has not seen yet
+// A dot one side has seen but no longer has was removed there. It reports
+// whether s changed.
+func (s orState[T]) join(other orState[T]) bool {
+	changed := false
+	for v, theirs := range other.entries {
+		mine := s.entries[v]
+		if mine == nil {
+			mine = make(map[dot]struct{})
+			s.entries[v] = mine
+		}
+		for d := range theirs {
+			if _, ok := mine[d]; !ok && !s.context.contains(d) {
+				mine[d] = struct{}{}
+				changed = true
+			}
+		}
+	}
+	for v, mine := range s.entries {
+		theirs := other.entries[v]
+		for d := range mine {
+			if _, ok := theirs[d]; !ok && other.context.contains(d) {
+				delete(mine, d)
+				changed = true
+			}
+		}
+		if len(mine) == 0 {
+			delete(s.entries, v)
+		}
+	}
+	return s.context.join(other.context) || changed
+}
+
+func (s orState[T]) clone() orState[T] {
+	clone := orState[T]{entries: make(map[T]map[dot]struct{}, len(s.entries)), context: s.context.clone()}
+	for v, dots := range s.entries {
+		clone.entries[v] = maps.Clone(dots)
+	}
+	return clone
+}
+
+// ORSet is an add-wins observed-remove set
+// Every Add tags the element with a new dot and Remove only drops the dots
+// the replica has observed, so an Add concurrent with a Remove of the same
+// element survives the merge.
+type ORSet[T comparable] struct {
+	replica      string
+	state        orState[T]
+	delta        orState[T]
+	mu           sync.RWMutex
+	config       Config
+	mergeCounter metrics.Counter
+}
+
+// NewORSet creates an empty set for replica
+// The config parameter is used to enable or disable metrics collection
+func NewORSet[T comparable](replica string, config Config) *ORSet[T] {
+	s := &ORSet[T]{replica: replica, state: newORState[T](), delta: newORState[T](), config: config}
+
+	// Initialize the metrics only if enabled in the config
+	if s.config.MetricsEnabled {
+		s.mergeCounter = metrics.NewCounter()
+		metrics.DefaultRegistry.Register("crdt.orset.merge", s.mergeCounter)
+	}
+
+	return s
+}
+
+// Add inserts v into the set
+// The dots v had are replaced with a new one, which is enough to outlive the
+// removes that observed them.
+func (s *ORSet[T]) Add(v T) {
+	s.mu.Lock() // Lock for writing
+	defer s.mu.Unlock()
+
+	d := s.state.context.next(s.replica)
+	op := newORState[T]()
+	op.entries[v] = map[dot]struct{}{d: {}}
+	for old := range s.state.entries[v] {
+		op.context.add(old)
+	}
+	op.context.add(d)
+	s.apply(op)
+}
+
+// Remove deletes v from the set, it returns false when v is not in the set
+func (s *ORSet[T]) Remove(v T) bool {
+	s.mu.Lock() // Lock for writing
+	defer s.mu.Unlock()
+
+	dots, ok := s.state.entries[v]
+	if !ok {
+		return false
+	}
+	op := newORState[T]()
+	for d := range dots {
+		op.context.add(d)
+	}
+	s.apply(op)
+	return true
+}
+
+// apply joins a local operation into the state and the pending delta
+func (s *ORSet[T]) apply(op orState[T]) {
+	s.state.join(op)
+	s.delta.join(op)
+}
+
+// Contains reports whether v is in the set
+func (s *ORSet[T]) Contains(v T) bool {
+	s.mu.RLock() // Lock for reading
+	defer s.mu.RUnlock()
+
+	_, ok := s.state.entries[v]
+	return ok
+}
+
+// Len returns the number of elements
+func (s *ORSet[T]) Len() int {
+	s.mu.RLock() // Lock for reading
+	defer s.mu.RUnlock()
+
+	return len(s.state.entries)
+}
+
+// Elements returns the elements in no particular order
+func (s *ORSet[T]) Elements() []T {
+	s.mu.RLock() // Lock for reading
+	defer s.mu.RUnlock()
+
+	elements := make([]T, 0, len(s.state.entries))
+	for v := range s.state.entries {
+		elements = append(elements, v)
+	}
+	return elements
+}
+
+// Delta returns the changes since the previous call to Delta, to be merged into other replicas
+func (s *ORSet[T]) Delta() *ORSet[T] {
+	s.mu.Lock() // Lock for writing
+	defer s.mu.Unlock()
+
+	delta := &ORSet[T]{replica: s.replica, state: s.delta, delta: newORState[T]()}
+	s.delta = newORState[T]()
+	return delta
+}
+
+// Merge incorporates the state or a delta of another replica
+func (s *ORSet[T]) Merge(other *ORSet[T]) {
+	other.mu.RLock() // Lock for reading
+	theirs := other.state.clone()
+	other.mu.RUnlock()
+
+	s.mu.Lock() // Lock for writing
+	defer s.mu.Unlock()
+
+	// A merge bringing something new is relayed with the next delta
+	if s.state.join(theirs) {
+		s.delta.join(theirs)
+	}
+
+	// Track metrics if enabled
+	if s.config.MetricsEnabled {
+		s.mergeCounter.Inc(1)
+	}
+}
@@ -0,0 +1,106 @@
+package crdt
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestORSet(t *testing.T) {
+	s := NewORSet[string]("a", Config{})
+	s.Add("x")
+	s.Add("y")
+	s.Add("x")
+	assert.True(t, s.Contains("x"))
+	assert.Equal(t, 2, s.Len())
+	assert.ElementsMatch(t, []string{"x", "y"}, s.Elements())
+
+	assert.True(t, s.Remove("x"))
+	assert.False(t, s.Remove("x"))
+	assert.False(t, s.Contains("x"))
+	assert.Equal(t, []string{"y"}, s.Elements())
+}
+
+func TestORSetAddWins(t *testing.T) {
+	a := NewORSet[string]("a", Config{})
+	b := NewORSet[string]("b", Config{})
+	a.Add("x")
+	b.Merge(a)
+
+	// A remove only drops the adds it observed
+	a.Remove("x")
+	b.Add("x")
+	a.Merge(b)
+	b.Merge(a)
+	assert.True(t, a.Contains("x"))
+	assert.True(t, b.Contains("x"))
+
+	// Once both sides observed the add, removing it removes it everywhere
+	b.Remove("x")
+	a.Merge(b)
+	assert.False(t, a.Contains("x"))
+}
+
+func TestORSetDelta(t *testing.T) {
+	a := NewORSet[int]("a", Config{})
+	b := NewORSet[int]("b", Config{})
+	a.Add(1)
+	a.Add(2)
+	b.Merge(a.Delta())
+	assert.ElementsMatch(t, []int{1, 2}, b.Elements())
+
+	// The remove delta carries no element, only the dots it drops
+	a.Remove(1)
+	delta := a.Delta()
+	assert.Empty(t, delta.state.entries)
+	assert.Equal(t, 0, delta.Len())
+	b.Merge(delta)
+	assert.Equal(t, []int{2}, b.Elements())
+
+	// Re-adding replaces the dots of the element in the delta as well
+	a.Add(2)
+	a.Add(2)
+	delta = a.Delta()
+	assert.Len(t, delta.state.entries[2], 1)
+	b.Merge(delta)
+	assert.Len(t, b.state.entries[2], 1)
+	assert.Equal(t, []int{2}, b.Elements())
+
+	// Deltas of the same replica can arrive out of order
+	c := NewORSet[int]("c", Config{})
+	a.Add(3)
+	first := a.Delta()
+	a.Remove(3)
+	second := a.Delta()
+	c.Merge(second)
+	c.Merge(first)
+	assert.False(t, c.Contains(3))
+}
+
+func TestORSetConvergence(t *testing.T) {
+	testConvergence(t, replicated[*ORSet[int]]{
+		create: func(replica string) *ORSet[int] { return NewORSet[int](replica, Config{}) },
+		update: func(rng *rand.Rand, s *ORSet[int]) {
+			if v := rng.IntN(8); rng.IntN(3) == 0 {
+				s.Remove(v)
+			} else {
+				s.Add(v)
+			}
+		},
+		delta: (*ORSet[int]).Delta,
+		merge: (*ORSet[int]).Merge,
+		view: func(s *ORSet[int]) any {
+			elements := s.Elements()
+			slices.Sort(elements)
+			return elements
+		},
+	})
+}
+
+func TestORSetMetrics(t *testing.T) {
+	s := NewORSet[int]("a", Config{MetricsEnabled: true})
+	s.Merge(NewORSet[int]("b", Config{}))
+	assert.Equal(t, int64(1), s.mergeCounter.Count())
+}
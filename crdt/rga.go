@@ -0,0 +1,257 @@
+package crdt
+
+import (
+	"sync"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+// rgaNode is an element of the sequence, removed elements stay in the list as tombstones
+type rgaNode[T any] struct {
+	id      stamp
+	origin  stamp // Element the node was inserted after, the zero stamp is the head
+	value   T
+	deleted bool
+	next    *rgaNode[T]
+}
+
+// RGA is a replicated growable array, a sequence supporting concurrent inserts and removes
+// Every element remembers the element it was inserted after. Elements inserted
+// after the same one are ordered by descending Lamport stamp, which every
+// replica computes the same way, and removed elements are kept as tombstones
+// so that later inserts can still refer to them. Inserts whose origin has not
+// been received yet are held back until it is, so deltas can be merged in any
+// order.
+type RGA[T any] struct {
+	replica      string
+	clock        uint64
+	head         rgaNode[T]
+	nodes        map[stamp]*rgaNode[T]
+	length       int
+	pending      map[stamp][]rgaNode[T] // Inserts waiting for their origin
+	removed      map[stamp]struct{}     // Removes waiting for their element
+	delta        *RGA[T]
+	mu           sync.RWMutex
+	config       Config
+	mergeCounter metrics.Counter
+}
+
+// NewRGA creates an empty sequence for replica
+// The config parameter is used to enable or disable metrics collection
+func NewRGA[T any](replica string, config Config) *RGA[T] {
+	r := newRGA[T](replica)
+	r.delta = newRGA[T](replica)
+	r.config = config
+
+	// Initialize the metrics only if enabled in the config
+	if r.config.MetricsEnabled {
+		r.mergeCounter = metrics.NewCounter()
+		metrics.DefaultRegistry.Register("crdt.rga.merge", r.mergeCounter)
+	}
+
+	return r
+}
+
+func newRGA[T any](replica string) *RGA[T] {
+	return &RGA[T]{
+		replica: replica,
+		nodes:   make(map[stamp]*rgaNode[T]),
+		pending: make(map[stamp][]rgaNode[T]),
+		removed: make(map[stamp]struct{}),
+	}
+}
+
+// Add appends an element to the end of the sequence
+func (r *RGA[T]) Add(value T) {
+	r.mu.Lock() // Lock for writing
+	defer r.mu.Unlock()
+
+	r.insertAfter(r.nodeBefore(r.length), value)
+}
+
+// InsertAt inserts an element at the specified position, shifting the following ones
+func (r *RGA[T]) InsertAt(index int, value T) error {
+	r.mu.Lock() // Lock for writing
+	defer r.mu.Unlock()
+
+	if index < 0 || index > r.length {
+		return ErrIndexOutOfBounds
+	}
+	r.insertAfter(r.nodeBefore(index), value)
+	return nil
+}
+
+// insertAfter creates an element following origin and records it in the pending delta
+func (r *RGA[T]) insertAfter(origin *rgaNode[T], value T) {
+	r.clock++
+	n := rgaNode[T]{id: stamp{time: r.clock, replica: r.replica}, origin: origin.id, value: value}
+	r.integrate(n)
+	r.delta.integrate(n)
+}
+
+// RemoveAt removes the element at the specified position and returns it
+func (r *RGA[T]) RemoveAt(index int) (T, error) {
+	r.mu.Lock() // Lock for writing
+	defer r.mu.Unlock()
+
+	if index < 0 || index >= r.length {
+		var zero T
+		return zero, ErrIndexOutOfBounds
+	}
+	n := r.nodeBefore(index + 1)
+	r.remove(n.id)
+	r.delta.remove(n.id)
+	return n.value, nil
+}
+
+// Get returns the element at the specified position
+func (r *RGA[T]) Get(index int) (T, error) {
+	r.mu.RLock() // Lock for reading
+	defer r.mu.RUnlock()
+
+	if index < 0 || index >= r.length {
+		var zero T
+		return zero, ErrIndexOutOfBounds
+	}
+	return r.nodeBefore(index + 1).value, nil
+}
+
+// Length returns the number of elements, not counting the removed ones
+func (r *RGA[T]) Length() int {
+	r.mu.RLock() // Lock for reading
+	defer r.mu.RUnlock()
+
+	return r.length
+}
+
+// Values returns all the elements in order
+func (r *RGA[T]) Values() []T {
+	r.mu.RLock() // Lock for reading
+	defer r.mu.RUnlock()
+
+	values := make([]T, 0, r.length)
+	for n := r.head.next; n != nil; n = n.next {
+		if !n.deleted {
+			values = append(values, n.value)
+		}
+	}
+	return values
+}
+
+// nodeBefore returns the node holding the visible element index-1, or the head when index is 0
+// Tombstones following it are skipped so that inserting after the returned
+// node places the new element at index.
+func (r *RGA[T]) nodeBefore(index int) *rgaNode[T] {
+	n := &r.head
+	for ; index > 0; n = n.next {
+		if !n.next.deleted {
+			index--
+		}
+	}
+	return n
+}
+
+// integrate places an insert after its origin, or holds it back until the origin is known
+func (r *RGA[T]) integrate(n rgaNode[T]) {
+	if known, ok := r.nodes[n.id]; ok {
+		if n.deleted {
+			r.tombstone(known)
+		}
+		return
+	}
+	prev := &r.head
+	if n.origin != (stamp{}) {
+		var ok bool
+		if prev, ok = r.nodes[n.origin]; !ok {
+			r.pending[n.origin] = append(r.pending[n.origin], n)
+			return
+		}
+	}
+	// Skip the elements inserted after the same origin with a higher stamp, and their successors
+	for prev.next != nil && prev.next.id.after(n.id) {
+		prev = prev.next
+	}
+
+	node := &rgaNode[T]{id: n.id, origin: n.origin, value: n.value, next: prev.next}
+	prev.next = node
+	r.nodes[n.id] = node
+	r.length++
+	r.clock = max(r.clock, n.id.time)
+	if _, ok := r.removed[n.id]; n.deleted || ok {
+		delete(r.removed, n.id)
+		r.tombstone(node)
+	}
+
+	waiting := r.pending[n.id]
+	delete(r.pending, n.id)
+	for _, w := range waiting {
+		r.integrate(w)
+	}
+}
+
+// remove turns the element id into a tombstone, or remembers the remove until the element is known
+func (r *RGA[T]) remove(id stamp) {
+	if n, ok := r.nodes[id]; ok {
+		r.tombstone(n)
+		return
+	}
+	r.removed[id] = struct{}{}
+}
+
+func (r *RGA[T]) tombstone(n *rgaNode[T]) {
+	if !n.deleted {
+		n.deleted = true
+		r.length--
+	}
+}
+
+// Delta returns the changes since the previous call to Delta, to be merged into other replicas
+func (r *RGA[T]) Delta() *RGA[T] {
+	r.mu.Lock() // Lock for writing
+	defer r.mu.Unlock()
+
+	delta := r.delta
+	r.delta = newRGA[T](r.replica)
+	return delta
+}
+
+// Merge incorporates the state or a delta of another replica
+func (r *RGA[T]) Merge(other *RGA[T]) {
+	other.mu.RLock() // Lock for reading
+	var inserts []rgaNode[T]
+	// The list order puts every element after its origin
+	for n := other.head.next; n != nil; n = n.next {
+		inserts = append(inserts, *n)
+	}
+	for _, waiting := range other.pending {
+		inserts = append(inserts, waiting...)
+	}
+	removes := make([]stamp, 0, len(other.removed))
+	for id := range other.removed {
+		removes = append(removes, id)
+	}
+	other.mu.RUnlock()
+
+	r.mu.Lock() // Lock for writing
+	defer r.mu.Unlock()
+
+	// What is new is relayed with the next delta
+	for _, n := range inserts {
+		n.next = nil
+		if known, ok := r.nodes[n.id]; !ok || n.deleted && !known.deleted {
+			r.delta.integrate(n)
+		}
+		r.integrate(n)
+	}
+	for _, id := range removes {
+		if known, ok := r.nodes[id]; !ok || !known.deleted {
+			r.delta.remove(id)
+		}
+		r.remove(id)
+	}
+
+	// Track metrics if enabled
+	if r.config.MetricsEnabled {
+		r.mergeCounter.Inc(1)
+	}
+}
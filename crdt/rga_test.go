@@ -0,0 +1,144 @@
+package crdt
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRGA(t *testing.T) {
+	r := NewRGA[string]("a", Config{})
+	r.Add("b")
+	r.Add("d")
+	assert.NoError(t, r.InsertAt(0, "a"))
+	assert.NoError(t, r.InsertAt(2, "c"))
+	assert.Equal(t, ErrIndexOutOfBounds, r.InsertAt(5, "x"))
+	assert.Equal(t, ErrIndexOutOfBounds, r.InsertAt(-1, "x"))
+	assert.Equal(t, []string{"a", "b", "c", "d"}, r.Values())
+	assert.Equal(t, 4, r.Length())
+
+	v, err := r.Get(2)
+	assert.NoError(t, err)
+	assert.Equal(t, "c", v)
+	_, err = r.Get(4)
+	assert.Equal(t, ErrIndexOutOfBounds, err)
+
+	v, err = r.RemoveAt(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "b", v)
+	_, err = r.RemoveAt(3)
+	assert.Equal(t, ErrIndexOutOfBounds, err)
+	assert.Equal(t, []string{"a", "c", "d"}, r.Values())
+
+	// Inserting next to a tombstone places the element at the requested index
+	assert.NoError(t, r.InsertAt(1, "b"))
+	assert.Equal(t, []string{"a", "b", "c", "d"}, r.Values())
+}
+
+func TestRGAConcurrentInserts(t *testing.T) {
+	a := NewRGA[string]("a", Config{})
+	b := NewRGA[string]("b", Config{})
+	a.Add("x")
+	b.Merge(a)
+
+	// Both replicas insert after x, the later stamp goes first on every replica
+	a.Add("from a")
+	b.Add("from b")
+	b.Add("again b")
+	a.Merge(b)
+	b.Merge(a)
+	assert.Equal(t, []string{"x", "from b", "again b", "from a"}, a.Values())
+	assert.Equal(t, a.Values(), b.Values())
+
+	// Concurrent removes of the same element remove it once
+	_, err := a.RemoveAt(0)
+	assert.NoError(t, err)
+	_, err = b.RemoveAt(0)
+	assert.NoError(t, err)
+	a.Merge(b)
+	b.Merge(a)
+	assert.Equal(t, 3, a.Length())
+	assert.Equal(t, a.Values(), b.Values())
+}
+
+func TestRGADelta(t *testing.T) {
+	a := NewRGA[int]("a", Config{})
+	b := NewRGA[int]("b", Config{})
+	a.Add(1)
+	first := a.Delta()
+	a.Add(2)
+	a.Add(3)
+	second := a.Delta()
+	_, err := a.RemoveAt(1)
+	assert.NoError(t, err)
+	third := a.Delta()
+	assert.Equal(t, 0, a.Delta().Length())
+
+	// Deltas arriving before the elements they depend on are held back
+	b.Merge(third)
+	assert.Empty(t, b.Values())
+	b.Merge(second)
+	assert.Empty(t, b.Values())
+	b.Merge(first)
+	assert.Equal(t, []int{1, 3}, b.Values())
+	assert.Empty(t, b.pending)
+	assert.Empty(t, b.removed)
+
+	// Merging a delta again changes nothing
+	b.Merge(second)
+	assert.Equal(t, []int{1, 3}, b.Values())
+}
+
+func TestRGAConvergence(t *testing.T) {
+	testConvergence(t, replicated[*RGA[int]]{
+		create: func(replica string) *RGA[int] { return NewRGA[int](replica, Config{}) },
+		update: func(rng *rand.Rand, r *RGA[int]) {
+			if n := r.Length(); n > 0 && rng.IntN(3) == 0 {
+				_, err := r.RemoveAt(rng.IntN(n))
+				assert.NoError(t, err)
+			} else {
+				assert.NoError(t, r.InsertAt(rng.IntN(n+1), rng.IntN(100)))
+			}
+		},
+		delta: (*RGA[int]).Delta,
+		merge: (*RGA[int]).Merge,
+		view:  func(r *RGA[int]) any { return r.Values() },
+	})
+}
+
+func TestRGAModel(t *testing.T) {
+	// A single replica behaves like a plain list
+	rng := rand.New(rand.NewPCG(1, 2))
+	r := NewRGA[int]("a", Config{})
+	var model []int
+	for i := 0; i < 1000; i++ {
+		if len(model) > 0 && rng.IntN(3) == 0 {
+			index := rng.IntN(len(model))
+			v, err := r.RemoveAt(index)
+			assert.NoError(t, err)
+			assert.Equal(t, model[index], v)
+			model = slices.Delete(model, index, index+1)
+		} else {
+			index := rng.IntN(len(model) + 1)
+			assert.NoError(t, r.InsertAt(index, i))
+			model = slices.Insert(model, index, i)
+		}
+	}
+	assert.Equal(t, model, r.Values())
+	assert.Equal(t, len(model), r.Length())
+}
+
+func TestRGAMetrics(t *testing.T) {
+	r := NewRGA[int]("a", Config{MetricsEnabled: true})
+	r.Merge(NewRGA[int]("b", Config{}))
+	assert.Equal(t, int64(1), r.mergeCounter.Count())
+}
+
+func BenchmarkRGAInsert(b *testing.B) {
+	r := NewRGA[int]("a", Config{})
+	for i := 0; i < b.N; i++ {
+		_ = r.InsertAt(r.Length()%64, i)
+	}
+}
@@ -0,0 +1,375 @@
+package cuckoo
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/fnv"
+	"math/rand/v2"
+	"sync"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+// Errors returned by the filter
+var (
+	ErrFull          = errors.New("filter is full")
+	ErrInvalidConfig = errors.New("invalid filter configuration")
+	ErrInvalidData   = errors.New("invalid filter data")
+)
+
+const (
+	defaultFingerprintBits = 8
+	defaultBucketSize      = 4
+	defaultMaxKicks        = 500
+
+	// Header layout: magic(4) version(1) fingerprintBits(1) bucketSize(2) maxKicks(4) numBuckets(8) count(8)
+	magic        = "CKOO"
+	version      = 1
+	headerLength = 28
+)
+
+type FilterConfig struct {
+	// FingerprintBits is the size of a stored fingerprint, between 1 and 16 bits (default 8)
+	FingerprintBits uint
+	// BucketSize is the number of fingerprints stored per bucket (default 4)
+	BucketSize int
+	// MaxKicks bounds the number of relocations attempted by Insert (default 500)
+	MaxKicks       int
+	MetricsEnabled bool
+}
+
+// Filter is a cuckoo filter: a probabilistic set that supports deletion
+type Filter struct {
+	buckets         []uint16 // numBuckets*bucketSize fingerprints, 0 marks an empty slot
+	numBuckets      uint64
+	bucketSize      int
+	fingerprintBits uint
+	maxKicks        int
+	count           uint64
+	mu              sync.RWMutex
+	config          FilterConfig
+	insertCounter   metrics.Counter
+	lookupCounter   metrics.Counter
+	deleteCounter   metrics.Counter
+	kickCounter     metrics.Counter
+}
+
+// NewFilter creates a filter able to hold at least capacity items
+// The config parameter selects the fingerprint and bucket sizes and enables metrics collection
+func NewFilter(capacity uint, config FilterConfig) (*Filter, error) {
+	if config.FingerprintBits == 0 {
+		config.FingerprintBits = defaultFingerprintBits
+	}
+	if config.BucketSize == 0 {
+		config.BucketSize = defaultBucketSize
+	}
+	if config.MaxKicks == 0 {
+		config.MaxKicks = defaultMaxKicks
+	}
+	if config.FingerprintBits > 16 || config.BucketSize < 0 || config.BucketSize > 0xffff || config.MaxKicks < 0 {
+		return nil, ErrInvalidConfig
+	}
+
+	// The number of buckets is rounded up to a power of two so that the
+	// alternate index can be computed with a XOR
+	numBuckets := uint64(1)
+	for numBuckets*uint64(config.BucketSize) < uint64(capacity) {
+		numBuckets <<= 1
+	}
+
+	f := &Filter{
+		buckets:         make([]uint16, numBuckets*uint64(config.BucketSize)),
+		numBuckets:      numBuckets,
+		bucketSize:      config.BucketSize,
+		fingerprintBits: config.FingerprintBits,
+		maxKicks:        config.MaxKicks,
+		config:          config,
+	}
+	f.initMetrics()
+
+	return f, nil
+}
+
+// Initialize the metrics only if enabled in the config
+func (f *Filter) initMetrics() {
+	if !f.config.MetricsEnabled {
+		return
+	}
+	f.insertCounter = metrics.NewCounter()
+	f.lookupCounter = metrics.NewCounter()
+	f.deleteCounter = metrics.NewCounter()
+	f.kickCounter = metrics.NewCounter()
+	metrics.DefaultRegistry.Register("cuckoo.insert", f.insertCounter)
+	metrics.DefaultRegistry.Register("cuckoo.lookup", f.lookupCounter)
+	metrics.DefaultRegistry.Register("cuckoo.delete", f.deleteCounter)
+	metrics.DefaultRegistry.Register("cuckoo.kick", f.kickCounter)
+}
+
+// Insert adds an item to the filter
+// It returns ErrFull when no slot could be freed within MaxKicks relocations,
+// in which case the filter is left unchanged
+func (f *Filter) Insert(item []byte) error {
+	f.mu.Lock() // Lock for writing
+	defer f.mu.Unlock()
+
+	fp, i1, i2 := f.locate(item)
+	if f.insertInto(i1, fp) || f.insertInto(i2, fp) {
+		f.count++
+		if f.config.MetricsEnabled {
+			f.insertCounter.Inc(1)
+		}
+		return nil
+	}
+
+	// Both buckets are full: relocate existing fingerprints, remembering each
+	// swap so that the filter can be restored if we run out of kicks
+	type swap struct {
+		slot uint64
+		fp   uint16
+	}
+	path := make([]swap, 0, 16)
+	index := i1
+	if rand.IntN(2) == 1 {
+		index = i2
+	}
+	for kick := 0; kick < f.maxKicks; kick++ {
+		slot := index*uint64(f.bucketSize) + uint64(rand.IntN(f.bucketSize))
+		path = append(path, swap{slot: slot, fp: f.buckets[slot]})
+		fp, f.buckets[slot] = f.buckets[slot], fp
+
+		index = f.altIndex(index, fp)
+		if f.insertInto(index, fp) {
+			f.count++
+			if f.config.MetricsEnabled {
+				f.insertCounter.Inc(1)
+				f.kickCounter.Inc(int64(len(path)))
+			}
+			return nil
+		}
+	}
+
+	// Undo the relocations in reverse order
+	for i := len(path) - 1; i >= 0; i-- {
+		f.buckets[path[i].slot] = path[i].fp
+	}
+	return ErrFull
+}
+
+// Lookup reports whether the item may be in the filter
+// False positives are possible, false negatives are not
+func (f *Filter) Lookup(item []byte) bool {
+	f.mu.RLock() // Lock for reading
+	defer f.mu.RUnlock()
+
+	// Track metrics if enabled
+	if f.config.MetricsEnabled {
+		f.lookupCounter.Inc(1)
+	}
+
+	fp, i1, i2 := f.locate(item)
+	return f.find(i1, fp) >= 0 || f.find(i2, fp) >= 0
+}
+
+// Delete removes one copy of the item from the filter and reports whether it was found
+// Only items that were previously inserted may be deleted
+func (f *Filter) Delete(item []byte) bool {
+	f.mu.Lock() // Lock for writing
+	defer f.mu.Unlock()
+
+	fp, i1, i2 := f.locate(item)
+	slot := f.find(i1, fp)
+	if slot < 0 {
+		slot = f.find(i2, fp)
+	}
+	if slot < 0 {
+		return false
+	}
+	f.buckets[slot] = 0
+	f.count--
+
+	// Track metrics if enabled
+	if f.config.MetricsEnabled {
+		f.deleteCounter.Inc(1)
+	}
+	return true
+}
+
+// Count returns the number of items stored in the filter
+func (f *Filter) Count() uint {
+	f.mu.RLock() // Lock for reading
+	defer f.mu.RUnlock()
+
+	return uint(f.count)
+}
+
+// LoadFactor returns the fraction of occupied slots
+func (f *Filter) LoadFactor() float64 {
+	f.mu.RLock() // Lock for reading
+	defer f.mu.RUnlock()
+
+	return float64(f.count) / float64(len(f.buckets))
+}
+
+// Reset removes all items from the filter
+func (f *Filter) Reset() {
+	f.mu.Lock() // Lock for writing
+	defer f.mu.Unlock()
+
+	clear(f.buckets)
+	f.count = 0
+}
+
+// MarshalBinary encodes the filter into a portable binary form
+func (f *Filter) MarshalBinary() ([]byte, error) {
+	f.mu.RLock() // Lock for reading
+	defer f.mu.RUnlock()
+
+	width := f.fingerprintWidth()
+	buf := make([]byte, headerLength, headerLength+len(f.buckets)*width)
+	copy(buf, magic)
+	buf[4] = version
+	buf[5] = byte(f.fingerprintBits)
+	binary.LittleEndian.PutUint16(buf[6:], uint16(f.bucketSize))
+	binary.LittleEndian.PutUint32(buf[8:], uint32(f.maxKicks))
+	binary.LittleEndian.PutUint64(buf[12:], f.numBuckets)
+	binary.LittleEndian.PutUint64(buf[20:], f.count)
+
+	for _, fp := range f.buckets {
+		if width == 1 {
+			buf = append(buf, byte(fp))
+		} else {
+			buf = binary.LittleEndian.AppendUint16(buf, fp)
+		}
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary replaces the filter contents with data produced by MarshalBinary
+// Metrics collection is kept as configured on the receiver
+func (f *Filter) UnmarshalBinary(data []byte) error {
+	if len(data) < headerLength || string(data[:4]) != magic || data[4] != version {
+		return ErrInvalidData
+	}
+	fingerprintBits := uint(data[5])
+	bucketSize := int(binary.LittleEndian.Uint16(data[6:]))
+	maxKicks := int(binary.LittleEndian.Uint32(data[8:]))
+	numBuckets := binary.LittleEndian.Uint64(data[12:])
+	count := binary.LittleEndian.Uint64(data[20:])
+	if fingerprintBits == 0 || fingerprintBits > 16 || bucketSize == 0 ||
+		numBuckets == 0 || numBuckets&(numBuckets-1) != 0 {
+		return ErrInvalidData
+	}
+
+	width := 1
+	if fingerprintBits > 8 {
+		width = 2
+	}
+	// Bound numBuckets by the payload first so that the slot count cannot overflow
+	payloadLength := uint64(len(data) - headerLength)
+	if numBuckets > payloadLength/uint64(bucketSize*width) {
+		return ErrInvalidData
+	}
+	slots := numBuckets * uint64(bucketSize)
+	if payloadLength != slots*uint64(width) {
+		return ErrInvalidData
+	}
+	buckets := make([]uint16, slots)
+	payload := data[headerLength:]
+	var occupied uint64
+	for i := range buckets {
+		if width == 1 {
+			buckets[i] = uint16(payload[i])
+		} else {
+			buckets[i] = binary.LittleEndian.Uint16(payload[2*i:])
+		}
+		if uint(buckets[i]) >= 1<<fingerprintBits {
+			return ErrInvalidData
+		}
+		if buckets[i] != 0 {
+			occupied++
+		}
+	}
+	// Every item takes exactly one slot
+	if occupied != count {
+		return ErrInvalidData
+	}
+
+	f.mu.Lock() // Lock for writing
+	defer f.mu.Unlock()
+
+	f.buckets = buckets
+	f.numBuckets = numBuckets
+	f.bucketSize = bucketSize
+	f.fingerprintBits = fingerprintBits
+	f.maxKicks = maxKicks
+	f.count = count
+	f.config.FingerprintBits = fingerprintBits
+	f.config.BucketSize = bucketSize
+	f.config.MaxKicks = maxKicks
+	if f.config.MetricsEnabled && f.insertCounter == nil {
+		f.initMetrics()
+	}
+	return nil
+}
+
+// locate computes the fingerprint and both candidate buckets of an item
+func (f *Filter) locate(item []byte) (uint16, uint64, uint64) {
+	h := fnv.New64a()
+	h.Write(item)
+	sum := mix(h.Sum64())
+
+	fp := uint16((sum >> 32) & (1<<f.fingerprintBits - 1))
+	if fp == 0 {
+		fp = 1 // Zero is reserved for empty slots
+	}
+	i1 := sum & (f.numBuckets - 1)
+	return fp, i1, f.altIndex(i1, fp)
+}
+
+// altIndex returns the other candidate bucket of a fingerprint stored at index
+func (f *Filter) altIndex(index uint64, fp uint16) uint64 {
+	// Mix the fingerprint so that small fingerprints still spread across the table
+	return (index ^ mix(uint64(fp))) & (f.numBuckets - 1)
+}
+
+// mix is the MurmurHash3 finalizer; FNV alone leaves the low bits poorly
+// distributed for keys that differ only in their last bytes
+func mix(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+// insertInto stores the fingerprint in a free slot of the bucket, if any
+func (f *Filter) insertInto(index uint64, fp uint16) bool {
+	base := index * uint64(f.bucketSize)
+	for i := uint64(0); i < uint64(f.bucketSize); i++ {
+		if f.buckets[base+i] == 0 {
+			f.buckets[base+i] = fp
+			return true
+		}
+	}
+	return false
+}
+
+// find returns the slot holding the fingerprint in the bucket, or -1
+func (f *Filter) find(index uint64, fp uint16) int64 {
+	base := index * uint64(f.bucketSize)
+	for i := uint64(0); i < uint64(f.bucketSize); i++ {
+		if f.buckets[base+i] == fp {
+			return int64(base + i)
+		}
+	}
+	return -1
+}
+
+// fingerprintWidth returns the number of bytes used to serialize a fingerprint
+func (f *Filter) fingerprintWidth() int {
+	if f.fingerprintBits > 8 {
+		return 2
+	}
+	return 1
+}
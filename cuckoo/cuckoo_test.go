@@ -0,0 +1,186 @@
+package cuckoo
+
+import (
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsertLookup(t *testing.T) {
+	filter, err := NewFilter(1000, FilterConfig{MetricsEnabled: false})
+	assert.NoError(t, err)
+
+	for i := 0; i < 500; i++ {
+		assert.NoError(t, filter.Insert([]byte(fmt.Sprintf("session-%d", i))))
+	}
+	assert.Equal(t, uint(500), filter.Count())
+
+	// Inserted items must always be found
+	for i := 0; i < 500; i++ {
+		assert.True(t, filter.Lookup([]byte(fmt.Sprintf("session-%d", i))))
+	}
+}
+
+func TestFalsePositiveRate(t *testing.T) {
+	filter, err := NewFilter(10000, FilterConfig{FingerprintBits: 16})
+	assert.NoError(t, err)
+
+	for i := 0; i < 5000; i++ {
+		assert.NoError(t, filter.Insert([]byte(fmt.Sprintf("in-%d", i))))
+	}
+
+	falsePositives := 0
+	for i := 0; i < 10000; i++ {
+		if filter.Lookup([]byte(fmt.Sprintf("out-%d", i))) {
+			falsePositives++
+		}
+	}
+	// With 16-bit fingerprints and buckets of 4 the expected rate is about 0.012%
+	assert.Less(t, falsePositives, 20)
+}
+
+func TestDelete(t *testing.T) {
+	filter, err := NewFilter(100, FilterConfig{})
+	assert.NoError(t, err)
+
+	assert.NoError(t, filter.Insert([]byte("a")))
+	assert.NoError(t, filter.Insert([]byte("b")))
+	assert.NoError(t, filter.Insert([]byte("a")))
+	assert.Equal(t, uint(3), filter.Count())
+
+	// Duplicates are counted and deleted one at a time
+	assert.True(t, filter.Delete([]byte("a")))
+	assert.True(t, filter.Lookup([]byte("a")))
+	assert.True(t, filter.Delete([]byte("a")))
+	assert.False(t, filter.Lookup([]byte("a")))
+	assert.False(t, filter.Delete([]byte("a")))
+
+	assert.True(t, filter.Lookup([]byte("b")))
+	assert.Equal(t, uint(1), filter.Count())
+}
+
+func TestInsertFull(t *testing.T) {
+	filter, err := NewFilter(8, FilterConfig{BucketSize: 2, MaxKicks: 10})
+	assert.NoError(t, err)
+
+	var inserted []string
+	var fullErr error
+	for i := 0; i < 100 && fullErr == nil; i++ {
+		item := fmt.Sprintf("item-%d", i)
+		if fullErr = filter.Insert([]byte(item)); fullErr == nil {
+			inserted = append(inserted, item)
+		}
+	}
+	assert.Equal(t, ErrFull, fullErr)
+	assert.Equal(t, "filter is full", fullErr.Error())
+	assert.Equal(t, uint(len(inserted)), filter.Count())
+
+	// A failed insertion must not evict previously inserted items
+	for _, item := range inserted {
+		assert.True(t, filter.Lookup([]byte(item)))
+	}
+}
+
+func TestInvalidConfig(t *testing.T) {
+	_, err := NewFilter(100, FilterConfig{FingerprintBits: 17})
+	assert.Equal(t, ErrInvalidConfig, err)
+}
+
+func TestMarshalBinary(t *testing.T) {
+	for _, bits := range []uint{4, 8, 12, 16} {
+		filter, err := NewFilter(256, FilterConfig{FingerprintBits: bits, BucketSize: 2})
+		assert.NoError(t, err)
+		for i := 0; i < 100; i++ {
+			assert.NoError(t, filter.Insert([]byte(fmt.Sprintf("key-%d", i))))
+		}
+
+		data, err := filter.MarshalBinary()
+		assert.NoError(t, err)
+
+		restored := &Filter{}
+		assert.NoError(t, restored.UnmarshalBinary(data))
+		assert.Equal(t, filter.Count(), restored.Count())
+		for i := 0; i < 100; i++ {
+			assert.True(t, restored.Lookup([]byte(fmt.Sprintf("key-%d", i))))
+		}
+
+		// The restored filter keeps supporting deletion
+		assert.True(t, restored.Delete([]byte("key-0")))
+		assert.Equal(t, uint(99), restored.Count())
+	}
+
+	assert.Equal(t, ErrInvalidData, (&Filter{}).UnmarshalBinary([]byte("garbage")))
+}
+
+func TestUnmarshalOverflowingHeader(t *testing.T) {
+	// numBuckets * bucketSize wraps around to zero slots with an empty payload
+	header := make([]byte, headerLength)
+	copy(header, magic)
+	header[4] = version
+	header[5] = 8
+	binary.LittleEndian.PutUint16(header[6:], 2)
+	binary.LittleEndian.PutUint64(header[12:], 1<<63)
+	assert.Equal(t, ErrInvalidData, (&Filter{}).UnmarshalBinary(header))
+
+	// More buckets than the payload holds
+	binary.LittleEndian.PutUint64(header[12:], 4)
+	assert.Equal(t, ErrInvalidData, (&Filter{}).UnmarshalBinary(append(header, 0, 0, 0, 0)))
+	assert.NoError(t, (&Filter{}).UnmarshalBinary(append(header, make([]byte, 8)...)))
+}
+
+func TestUnmarshalInconsistentPayload(t *testing.T) {
+	header := make([]byte, headerLength)
+	copy(header, magic)
+	header[4] = version
+	header[5] = 4
+	binary.LittleEndian.PutUint16(header[6:], 2)
+	binary.LittleEndian.PutUint64(header[12:], 2)
+	binary.LittleEndian.PutUint64(header[20:], 2)
+
+	// The count must match the occupied slots
+	assert.NoError(t, (&Filter{}).UnmarshalBinary(append(header, 1, 0, 0, 15)))
+	assert.Equal(t, ErrInvalidData, (&Filter{}).UnmarshalBinary(append(header, 1, 0, 0, 0)))
+	assert.Equal(t, ErrInvalidData, (&Filter{}).UnmarshalBinary(append(header, 1, 2, 3, 0)))
+
+	// Fingerprints must fit in fingerprintBits
+	assert.Equal(t, ErrInvalidData, (&Filter{}).UnmarshalBinary(append(header, 1, 0, 0, 16)))
+}
+
+func TestReset(t *testing.T) {
+	filter, err := NewFilter(100, FilterConfig{})
+	assert.NoError(t, err)
+
+	filter.Insert([]byte("a"))
+	filter.Reset()
+	assert.Equal(t, uint(0), filter.Count())
+	assert.False(t, filter.Lookup([]byte("a")))
+}
+
+func BenchmarkInsert(b *testing.B) {
+	filter, _ := NewFilter(uint(b.N)+1, FilterConfig{})
+	keys := make([][]byte, b.N)
+	for i := range keys {
+		keys[i] = []byte(fmt.Sprintf("key-%d", i))
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		filter.Insert(keys[i])
+	}
+}
+
+func BenchmarkLookup(b *testing.B) {
+	filter, _ := NewFilter(10000, FilterConfig{})
+	keys := make([][]byte, 10000)
+	for i := range keys {
+		keys[i] = []byte(fmt.Sprintf("key-%d", i))
+		filter.Insert(keys[i])
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		filter.Lookup(keys[i%10000])
+	}
+}
@@ -0,0 +1,189 @@
+package fenwick
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/rcrowley/go-metrics"
+	"github.com/vzahanych/data-structures/array"
+)
+
+// Errors returned by the tree
+var (
+	ErrIndexOutOfBounds = errors.New("index out of bounds")
+	ErrInvalidRange     = errors.New("invalid range")
+)
+
+// Number is the set of types that can be summed by the tree
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+type TreeConfig struct {
+	MetricsEnabled bool
+}
+
+// Tree is a Fenwick (binary indexed) tree maintaining prefix sums under point updates
+type Tree[T Number] struct {
+	sums          []T // 1-based: sums[i] covers the (i & -i) elements ending at i
+	mu            sync.RWMutex
+	config        TreeConfig
+	sumCounter    metrics.Counter
+	updateCounter metrics.Counter
+}
+
+// New creates a tree of size elements, all zero
+func New[T Number](size int, config TreeConfig) *Tree[T] {
+	t := &Tree[T]{
+		sums:   make([]T, size+1),
+		config: config,
+	}
+
+	// Initialize the metrics only if enabled in the config
+	if t.config.MetricsEnabled {
+		t.sumCounter = metrics.NewCounter()
+		t.updateCounter = metrics.NewCounter()
+		metrics.DefaultRegistry.Register("fenwick.sum", t.sumCounter)
+		metrics.DefaultRegistry.Register("fenwick.update", t.updateCounter)
+	}
+
+	return t
+}
+
+// FromSlice builds a tree over values in linear time
+func FromSlice[T Number](values []T, config TreeConfig) *Tree[T] {
+	t := New[T](len(values), config)
+	copy(t.sums[1:], values)
+	for i := 1; i < len(t.sums); i++ {
+		if parent := i + i&-i; parent < len(t.sums) {
+			t.sums[parent] += t.sums[i]
+		}
+	}
+	return t
+}
+
+// FromArray builds a tree over the current elements of arr
+func FromArray[T Number](arr *array.Array[T], config TreeConfig) *Tree[T] {
+	return FromSlice(arr.Values(), config)
+}
+
+// Len returns the number of elements in the tree
+func (t *Tree[T]) Len() int {
+	return len(t.sums) - 1
+}
+
+// Add adds delta to the element at the specified index
+func (t *Tree[T]) Add(index int, delta T) error {
+	t.mu.Lock() // Lock for writing
+	defer t.mu.Unlock()
+
+	if index < 0 || index >= len(t.sums)-1 {
+		return ErrIndexOutOfBounds
+	}
+	t.add(index, delta)
+
+	// Track metrics if enabled
+	if t.config.MetricsEnabled {
+		t.updateCounter.Inc(1)
+	}
+	return nil
+}
+
+// Set replaces the element at the specified index
+func (t *Tree[T]) Set(index int, value T) error {
+	t.mu.Lock() // Lock for writing
+	defer t.mu.Unlock()
+
+	if index < 0 || index >= len(t.sums)-1 {
+		return ErrIndexOutOfBounds
+	}
+	t.add(index, value-(t.prefix(index+1)-t.prefix(index)))
+
+	// Track metrics if enabled
+	if t.config.MetricsEnabled {
+		t.updateCounter.Inc(1)
+	}
+	return nil
+}
+
+// Get retrieves the element at the specified index
+func (t *Tree[T]) Get(index int) (T, error) {
+	return t.RangeSum(index, index+1)
+}
+
+// PrefixSum returns the sum of the elements in [0, index]
+func (t *Tree[T]) PrefixSum(index int) (T, error) {
+	t.mu.RLock() // Lock for reading
+	defer t.mu.RUnlock()
+
+	if index < 0 || index >= len(t.sums)-1 {
+		var zero T
+		return zero, ErrIndexOutOfBounds
+	}
+
+	// Track metrics if enabled
+	if t.config.MetricsEnabled {
+		t.sumCounter.Inc(1)
+	}
+	return t.prefix(index + 1), nil
+}
+
+// RangeSum returns the sum of the elements in [from, to)
+func (t *Tree[T]) RangeSum(from, to int) (T, error) {
+	t.mu.RLock() // Lock for reading
+	defer t.mu.RUnlock()
+
+	var zero T
+	if from < 0 || to > len(t.sums)-1 {
+		return zero, ErrIndexOutOfBounds
+	}
+	if from > to {
+		return zero, ErrInvalidRange
+	}
+
+	// Track metrics if enabled
+	if t.config.MetricsEnabled {
+		t.sumCounter.Inc(1)
+	}
+	return t.prefix(to) - t.prefix(from), nil
+}
+
+// LowerBound returns the smallest index whose prefix sum is at least target
+// It requires all elements to be non-negative and returns Len() when no prefix reaches target
+func (t *Tree[T]) LowerBound(target T) int {
+	t.mu.RLock() // Lock for reading
+	defer t.mu.RUnlock()
+
+	step := 1
+	for step*2 < len(t.sums) {
+		step *= 2
+	}
+	position := 0
+	var accumulated T
+	for ; step > 0; step /= 2 {
+		next := position + step
+		if next < len(t.sums) && accumulated+t.sums[next] < target {
+			position = next
+			accumulated += t.sums[next]
+		}
+	}
+	return position
+}
+
+// add updates every node covering index (0-based)
+func (t *Tree[T]) add(index int, delta T) {
+	for i := index + 1; i < len(t.sums); i += i & -i {
+		t.sums[i] += delta
+	}
+}
+
+// prefix returns the sum of the first n elements
+func (t *Tree[T]) prefix(n int) T {
+	var total T
+	for i := n; i > 0; i -= i & -i {
+		total += t.sums[i]
+	}
+	return total
+}
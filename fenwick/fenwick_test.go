@@ -0,0 +1,107 @@
+package fenwick
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vzahanych/data-structures/array"
+)
+
+func TestPrefixSum(t *testing.T) {
+	tree := FromSlice([]int{1, 2, 3, 4, 5}, TreeConfig{MetricsEnabled: false})
+
+	for i, expected := range []int{1, 3, 6, 10, 15} {
+		value, err := tree.PrefixSum(i)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value)
+	}
+
+	_, err := tree.PrefixSum(5)
+	assert.Error(t, err)
+	assert.Equal(t, "index out of bounds", err.Error())
+}
+
+func TestAddSetGet(t *testing.T) {
+	tree := New[int](5, TreeConfig{})
+
+	assert.NoError(t, tree.Add(2, 7))
+	assert.NoError(t, tree.Add(2, 3))
+	assert.NoError(t, tree.Set(4, 1))
+	assert.NoError(t, tree.Set(2, 4))
+
+	value, err := tree.Get(2)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, value)
+
+	value, _ = tree.RangeSum(0, 5)
+	assert.Equal(t, 5, value)
+
+	assert.Equal(t, ErrIndexOutOfBounds, tree.Add(5, 1))
+	assert.Equal(t, ErrIndexOutOfBounds, tree.Set(-1, 1))
+	_, err = tree.RangeSum(3, 2)
+	assert.Equal(t, ErrInvalidRange, err)
+}
+
+func TestRandomized(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	values := make([]int64, 200)
+	tree := New[int64](len(values), TreeConfig{})
+
+	for round := 0; round < 1000; round++ {
+		index := rng.IntN(len(values))
+		delta := int64(rng.IntN(100) - 50)
+		values[index] += delta
+		assert.NoError(t, tree.Add(index, delta))
+
+		from := rng.IntN(len(values))
+		to := from + rng.IntN(len(values)-from+1)
+		var expected int64
+		for _, v := range values[from:to] {
+			expected += v
+		}
+		got, err := tree.RangeSum(from, to)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, got)
+	}
+}
+
+func TestLowerBound(t *testing.T) {
+	tree := FromSlice([]uint{2, 0, 3, 1, 4}, TreeConfig{})
+
+	assert.Equal(t, 0, tree.LowerBound(1))
+	assert.Equal(t, 0, tree.LowerBound(2))
+	assert.Equal(t, 2, tree.LowerBound(3))
+	assert.Equal(t, 3, tree.LowerBound(6))
+	assert.Equal(t, 4, tree.LowerBound(10))
+	assert.Equal(t, 5, tree.LowerBound(11))
+}
+
+func TestFromArray(t *testing.T) {
+	arr := array.NewArray[float64](4, array.ArrayConfig{MetricsEnabled: false})
+	arr.Append(0.5)
+	arr.Append(1.5)
+
+	tree := FromArray(arr, TreeConfig{})
+	assert.Equal(t, 2, tree.Len())
+	value, _ := tree.PrefixSum(1)
+	assert.Equal(t, 2.0, value)
+}
+
+func BenchmarkAdd(b *testing.B) {
+	tree := New[int](100000, TreeConfig{})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Add(i%100000, 1)
+	}
+}
+
+func BenchmarkPrefixSum(b *testing.B) {
+	tree := New[int](100000, TreeConfig{})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.PrefixSum(i % 100000)
+	}
+}
@@ -0,0 +1,134 @@
+package hamt
+
+import (
+	"iter"
+	"math/bits"
+)
+
+// ChangeKind tells how an entry differs between two versions of a map
+type ChangeKind int
+
+const (
+	Added ChangeKind = iota
+	Removed
+	Modified
+)
+
+// Change describes an entry that differs between two versions of a map
+// Old is the zero value for Added entries and New is the zero value for Removed ones.
+type Change[K comparable, V any] struct {
+	Kind ChangeKind
+	Key  K
+	Old  V
+	New  V
+}
+
+// Diff returns an iterator over the changes turning m into other
+// Subtrees shared by both versions are skipped without being visited, so
+// diffing a map against a version derived from it costs time proportional to
+// the number of changes rather than to the size of the maps. equal decides
+// whether the values of a key present in both maps differ.
+func (m *Map[K, V]) Diff(other *Map[K, V], equal func(a, b V) bool) iter.Seq[Change[K, V]] {
+	return func(yield func(Change[K, V]) bool) {
+		d := differ[K, V]{equal: equal, yield: yield}
+		d.nodes(m.root, other.root)
+	}
+}
+
+type differ[K comparable, V any] struct {
+	equal func(a, b V) bool
+	yield func(Change[K, V]) bool
+}
+
+// nodes compares two subtrees found at the same position, returning false once yield stops
+func (d *differ[K, V]) nodes(a, b *node[K, V]) bool {
+	if a == b {
+		return true
+	}
+	if a.collision || b.collision {
+		return d.entries(a, b)
+	}
+
+	for union := a.bitmap | b.bitmap; union != 0; union &= union - 1 {
+		bit := union & -union
+		inA, inB := a.bitmap&bit != 0, b.bitmap&bit != 0
+		var sa, sb slot[K, V]
+		if inA {
+			sa = a.slots[bits.OnesCount32(a.bitmap&(bit-1))]
+		}
+		if inB {
+			sb = b.slots[bits.OnesCount32(b.bitmap&(bit-1))]
+		}
+
+		var ok bool
+		switch {
+		case !inB:
+			ok = d.slot(sa, Removed)
+		case !inA:
+			ok = d.slot(sb, Added)
+		case sa.child != nil && sb.child != nil:
+			ok = d.nodes(sa.child, sb.child)
+		case sa.child == nil && sb.child == nil && sa.key == sb.key:
+			ok = d.values(sa.key, sa.value, sb.value)
+		default:
+			// Entries and subtrees are mixed: compare their contents
+			ok = d.entries(asNode(sa), asNode(sb))
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// entries compares two subtrees by their contents
+func (d *differ[K, V]) entries(a, b *node[K, V]) bool {
+	before := make(map[K]V)
+	walk(a, func(k K, v V) bool {
+		before[k] = v
+		return true
+	})
+
+	stopped := !walk(b, func(k K, v V) bool {
+		old, found := before[k]
+		if !found {
+			return d.yield(Change[K, V]{Kind: Added, Key: k, New: v})
+		}
+		delete(before, k)
+		return d.values(k, old, v)
+	})
+	if stopped {
+		return false
+	}
+	for k, v := range before {
+		if !d.yield(Change[K, V]{Kind: Removed, Key: k, Old: v}) {
+			return false
+		}
+	}
+	return true
+}
+
+// slot reports every entry below s with the given kind
+func (d *differ[K, V]) slot(s slot[K, V], kind ChangeKind) bool {
+	return walk(asNode(s), func(k K, v V) bool {
+		if kind == Added {
+			return d.yield(Change[K, V]{Kind: Added, Key: k, New: v})
+		}
+		return d.yield(Change[K, V]{Kind: Removed, Key: k, Old: v})
+	})
+}
+
+func (d *differ[K, V]) values(key K, old, new V) bool {
+	if d.equal(old, new) {
+		return true
+	}
+	return d.yield(Change[K, V]{Kind: Modified, Key: key, Old: old, New: new})
+}
+
+// asNode returns the subtree of a slot, wrapping a single entry in a node if needed
+func asNode[K comparable, V any](s slot[K, V]) *node[K, V] {
+	if s.child != nil {
+		return s.child
+	}
+	return &node[K, V]{slots: []slot[K, V]{s}}
+}
@@ -0,0 +1,331 @@
+package hamt
+
+import (
+	"errors"
+	"hash/maphash"
+	"iter"
+	"math/bits"
+	"slices"
+)
+
+// ErrTransientUsed is returned when a transient is modified after Persistent
+var ErrTransientUsed = errors.New("transient used after Persistent")
+
+const (
+	bitsPerLevel = 5
+	levelMask    = 1<<bitsPerLevel - 1
+	hashBits     = 64
+)
+
+// seed is shared by every map of the process so that any two versions can be diffed
+var seed = maphash.MakeSeed()
+
+func hashOf[K comparable](key K) uint64 {
+	return maphash.Comparable(seed, key)
+}
+
+// owner marks the nodes a transient may modify in place
+type owner struct{}
+
+// slot is either a key/value pair or, when child is set, a subtree
+type slot[K comparable, V any] struct {
+	hash  uint64
+	key   K
+	value V
+	child *node[K, V]
+}
+
+// node is a bitmap-indexed node: bit i of bitmap is set when the
+// 5-bit hash fragment i has a slot, stored at position popcount(bitmap & (1<<i - 1)).
+// Once all 64 hash bits are consumed, keys with equal hashes share a
+// collision node, which stores its slots unindexed.
+type node[K comparable, V any] struct {
+	owner     *owner
+	bitmap    uint32
+	slots     []slot[K, V]
+	collision bool
+}
+
+func (n *node[K, V]) clone(o *owner) *node[K, V] {
+	return &node[K, V]{owner: o, bitmap: n.bitmap, slots: slices.Clone(n.slots), collision: n.collision}
+}
+
+// editable returns n itself if it belongs to o, or a copy owned by o
+func editable[K comparable, V any](o *owner, n *node[K, V]) *node[K, V] {
+	if o != nil && n.owner == o {
+		return n
+	}
+	return n.clone(o)
+}
+
+// Map is an immutable hash map implemented as a hash array mapped trie
+// Assoc and Dissoc return new maps sharing every untouched node with the
+// original, so snapshots are cheap and can be read concurrently without locking.
+type Map[K comparable, V any] struct {
+	root *node[K, V]
+	size int
+}
+
+// New returns an empty map
+func New[K comparable, V any]() *Map[K, V] {
+	return &Map[K, V]{root: &node[K, V]{}}
+}
+
+// Len returns the number of entries
+func (m *Map[K, V]) Len() int {
+	return m.size
+}
+
+// Get returns the value stored for key
+func (m *Map[K, V]) Get(key K) (V, bool) {
+	hash := hashOf(key)
+	n := m.root
+	for shift := uint(0); ; shift += bitsPerLevel {
+		if n.collision {
+			for _, s := range n.slots {
+				if s.key == key {
+					return s.value, true
+				}
+			}
+			break
+		}
+		bit := uint32(1) << ((hash >> shift) & levelMask)
+		if n.bitmap&bit == 0 {
+			break
+		}
+		s := n.slots[bits.OnesCount32(n.bitmap&(bit-1))]
+		if s.child == nil {
+			if s.key == key {
+				return s.value, true
+			}
+			break
+		}
+		n = s.child
+	}
+	var zero V
+	return zero, false
+}
+
+// Assoc returns a new map with key set to value
+func (m *Map[K, V]) Assoc(key K, value V) *Map[K, V] {
+	added := false
+	root := assoc(nil, m.root, 0, hashOf(key), key, value, &added)
+	if added {
+		return &Map[K, V]{root: root, size: m.size + 1}
+	}
+	return &Map[K, V]{root: root, size: m.size}
+}
+
+// Dissoc returns a new map without key
+// The map itself is returned when key is absent.
+func (m *Map[K, V]) Dissoc(key K) *Map[K, V] {
+	removed := false
+	root := dissoc(nil, m.root, 0, hashOf(key), key, &removed)
+	if !removed {
+		return m
+	}
+	if root == nil {
+		root = &node[K, V]{}
+	}
+	return &Map[K, V]{root: root, size: m.size - 1}
+}
+
+// All returns an iterator over the entries in hash order
+func (m *Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		walk(m.root, yield)
+	}
+}
+
+// Transient returns a mutable builder initialized with the contents of the map
+// The map itself is left untouched.
+func (m *Map[K, V]) Transient() *TransientMap[K, V] {
+	return &TransientMap[K, V]{root: m.root, size: m.size, owner: &owner{}}
+}
+
+// TransientMap is a mutable map for efficient bulk building
+// It modifies in place the nodes it created and copies shared ones on first
+// write. A TransientMap is not safe for concurrent use and must not be used
+// after Persistent.
+type TransientMap[K comparable, V any] struct {
+	root  *node[K, V]
+	size  int
+	owner *owner
+}
+
+// Len returns the number of entries
+func (t *TransientMap[K, V]) Len() int {
+	return t.size
+}
+
+// Get returns the value stored for key
+func (t *TransientMap[K, V]) Get(key K) (V, bool) {
+	return (&Map[K, V]{root: t.root}).Get(key)
+}
+
+// Assoc sets key to value
+func (t *TransientMap[K, V]) Assoc(key K, value V) error {
+	if t.owner == nil {
+		return ErrTransientUsed
+	}
+	added := false
+	t.root = assoc(t.owner, t.root, 0, hashOf(key), key, value, &added)
+	if added {
+		t.size++
+	}
+	return nil
+}
+
+// Dissoc removes key
+func (t *TransientMap[K, V]) Dissoc(key K) error {
+	if t.owner == nil {
+		return ErrTransientUsed
+	}
+	removed := false
+	t.root = dissoc(t.owner, t.root, 0, hashOf(key), key, &removed)
+	if t.root == nil {
+		t.root = &node[K, V]{owner: t.owner}
+	}
+	if removed {
+		t.size--
+	}
+	return nil
+}
+
+// Persistent returns an immutable map holding the current contents and ends the transient
+func (t *TransientMap[K, V]) Persistent() *Map[K, V] {
+	t.owner = nil
+	return &Map[K, V]{root: t.root, size: t.size}
+}
+
+func assoc[K comparable, V any](o *owner, n *node[K, V], shift uint, hash uint64, key K, value V, added *bool) *node[K, V] {
+	if n.collision {
+		result := editable(o, n)
+		for i, s := range n.slots {
+			if s.key == key {
+				result.slots[i].value = value
+				return result
+			}
+		}
+		result.slots = append(result.slots, slot[K, V]{hash: hash, key: key, value: value})
+		*added = true
+		return result
+	}
+
+	bit := uint32(1) << ((hash >> shift) & levelMask)
+	index := bits.OnesCount32(n.bitmap & (bit - 1))
+	if n.bitmap&bit == 0 {
+		result := editable(o, n)
+		result.slots = slices.Insert(result.slots, index, slot[K, V]{hash: hash, key: key, value: value})
+		result.bitmap |= bit
+		*added = true
+		return result
+	}
+
+	s := n.slots[index]
+	result := editable(o, n)
+	switch {
+	case s.child != nil:
+		result.slots[index].child = assoc(o, s.child, shift+bitsPerLevel, hash, key, value, added)
+	case s.key == key:
+		result.slots[index].value = value
+	default:
+		// Two keys share this fragment: push both one level down
+		result.slots[index] = slot[K, V]{child: merge(o, shift+bitsPerLevel, s, slot[K, V]{hash: hash, key: key, value: value})}
+		*added = true
+	}
+	return result
+}
+
+// merge builds the smallest subtree holding two entries whose hashes agree up to shift
+func merge[K comparable, V any](o *owner, shift uint, a, b slot[K, V]) *node[K, V] {
+	if shift >= hashBits {
+		return &node[K, V]{owner: o, slots: []slot[K, V]{a, b}, collision: true}
+	}
+	fragmentA := (a.hash >> shift) & levelMask
+	fragmentB := (b.hash >> shift) & levelMask
+	if fragmentA == fragmentB {
+		return &node[K, V]{
+			owner:  o,
+			bitmap: 1 << fragmentA,
+			slots:  []slot[K, V]{{child: merge(o, shift+bitsPerLevel, a, b)}},
+		}
+	}
+	if fragmentA > fragmentB {
+		a, b = b, a
+		fragmentA, fragmentB = fragmentB, fragmentA
+	}
+	return &node[K, V]{owner: o, bitmap: 1<<fragmentA | 1<<fragmentB, slots: []slot[K, V]{a, b}}
+}
+
+// dissoc removes key below n and returns the new node, or nil when it became empty
+// Subtrees left with a single entry are collapsed into their parent.
+func dissoc[K comparable, V any](o *owner, n *node[K, V], shift uint, hash uint64, key K, removed *bool) *node[K, V] {
+	if n.collision {
+		for i, s := range n.slots {
+			if s.key == key {
+				*removed = true
+				if len(n.slots) == 1 {
+					return nil
+				}
+				result := editable(o, n)
+				result.slots = slices.Delete(result.slots, i, i+1)
+				return result
+			}
+		}
+		return n
+	}
+
+	bit := uint32(1) << ((hash >> shift) & levelMask)
+	if n.bitmap&bit == 0 {
+		return n
+	}
+	index := bits.OnesCount32(n.bitmap & (bit - 1))
+	s := n.slots[index]
+
+	if s.child == nil {
+		if s.key != key {
+			return n
+		}
+		*removed = true
+		if len(n.slots) == 1 {
+			return nil
+		}
+		result := editable(o, n)
+		result.slots = slices.Delete(result.slots, index, index+1)
+		result.bitmap &^= bit
+		return result
+	}
+
+	child := dissoc(o, s.child, shift+bitsPerLevel, hash, key, removed)
+	if !*removed {
+		return n
+	}
+	result := editable(o, n)
+	switch {
+	case child == nil:
+		if len(n.slots) == 1 {
+			return nil
+		}
+		result.slots = slices.Delete(result.slots, index, index+1)
+		result.bitmap &^= bit
+	case len(child.slots) == 1 && child.slots[0].child == nil:
+		result.slots[index] = child.slots[0]
+	default:
+		result.slots[index].child = child
+	}
+	return result
+}
+
+func walk[K comparable, V any](n *node[K, V], yield func(K, V) bool) bool {
+	for _, s := range n.slots {
+		if s.child != nil {
+			if !walk(s.child, yield) {
+				return false
+			}
+		} else if !yield(s.key, s.value) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,218 @@
+package hamt
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func equalInts(a, b int) bool { return a == b }
+
+func toMap[K comparable, V any](m *Map[K, V]) map[K]V {
+	result := make(map[K]V)
+	for k, v := range m.All() {
+		result[k] = v
+	}
+	return result
+}
+
+func TestAssocGet(t *testing.T) {
+	empty := New[string, int]()
+	m := empty.Assoc("a", 1).Assoc("b", 2).Assoc("a", 10)
+
+	value, found := m.Get("a")
+	assert.True(t, found)
+	assert.Equal(t, 10, value)
+	value, found = m.Get("b")
+	assert.True(t, found)
+	assert.Equal(t, 2, value)
+	_, found = m.Get("c")
+	assert.False(t, found)
+	assert.Equal(t, 2, m.Len())
+
+	// Earlier versions are untouched
+	assert.Equal(t, 0, empty.Len())
+	_, found = empty.Get("a")
+	assert.False(t, found)
+}
+
+func TestDissoc(t *testing.T) {
+	m := New[int, int]()
+	for i := 0; i < 1000; i++ {
+		m = m.Assoc(i, i*i)
+	}
+	full := m
+
+	for i := 0; i < 1000; i += 2 {
+		m = m.Dissoc(i)
+	}
+	assert.Equal(t, 500, m.Len())
+	assert.Same(t, m, m.Dissoc(2)) // Absent key
+
+	for i := 0; i < 1000; i++ {
+		value, found := m.Get(i)
+		assert.Equal(t, i%2 == 1, found)
+		if found {
+			assert.Equal(t, i*i, value)
+		}
+	}
+	assert.Equal(t, 1000, full.Len())
+
+	for i := 1; i < 1000; i += 2 {
+		m = m.Dissoc(i)
+	}
+	assert.Equal(t, 0, m.Len())
+	assert.Empty(t, toMap(m))
+}
+
+func TestRandomized(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	m := New[int, int]()
+	model := make(map[int]int)
+
+	for round := 0; round < 20000; round++ {
+		key := rng.IntN(2000)
+		if rng.IntN(3) == 0 {
+			m = m.Dissoc(key)
+			delete(model, key)
+		} else {
+			m = m.Assoc(key, round)
+			model[key] = round
+		}
+	}
+	assert.Equal(t, len(model), m.Len())
+	assert.Equal(t, model, toMap(m))
+}
+
+func TestCollisions(t *testing.T) {
+	// Force every key onto the same full hash to exercise collision nodes
+	var root *node[string, int] = &node[string, int]{}
+	const hash = 0xdeadbeef
+	for i := 0; i < 5; i++ {
+		added := false
+		root = assoc(nil, root, 0, hash, fmt.Sprintf("k%d", i), i, &added)
+		assert.True(t, added)
+	}
+	m := &Map[string, int]{root: root, size: 5}
+	assert.Len(t, toMap(m), 5)
+
+	// Descend to the collision node
+	n := root
+	for !n.collision {
+		assert.Len(t, n.slots, 1)
+		n = n.slots[0].child
+	}
+	assert.Len(t, n.slots, 5)
+
+	for i := 0; i < 4; i++ {
+		removed := false
+		root = dissoc(nil, root, 0, hash, fmt.Sprintf("k%d", i), &removed)
+		assert.True(t, removed)
+	}
+	// The last entry is collapsed all the way up to the root
+	assert.Len(t, root.slots, 1)
+	assert.Nil(t, root.slots[0].child)
+	assert.Equal(t, "k4", root.slots[0].key)
+}
+
+func TestTransient(t *testing.T) {
+	base := New[int, string]().Assoc(1, "one")
+	tr := base.Transient()
+	for i := 0; i < 1000; i++ {
+		assert.NoError(t, tr.Assoc(i, fmt.Sprint(i)))
+	}
+	assert.NoError(t, tr.Dissoc(500))
+	assert.Equal(t, 999, tr.Len())
+
+	built := tr.Persistent()
+	assert.Equal(t, 999, built.Len())
+	value, _ := built.Get(1)
+	assert.Equal(t, "1", value)
+	_, found := built.Get(500)
+	assert.False(t, found)
+
+	// The source map is unchanged and the transient is closed
+	value, _ = base.Get(1)
+	assert.Equal(t, "one", value)
+	assert.Equal(t, 1, base.Len())
+	assert.Equal(t, ErrTransientUsed, tr.Assoc(1, "x"))
+}
+
+func TestDiff(t *testing.T) {
+	before := New[int, int]()
+	for i := 0; i < 5000; i++ {
+		before = before.Assoc(i, i)
+	}
+	after := before.Assoc(10, -10).Dissoc(20).Assoc(6000, 6000).Assoc(30, 30)
+
+	var changes []string
+	for c := range before.Diff(after, equalInts) {
+		changes = append(changes, fmt.Sprintf("%d:%d:%d:%d", c.Kind, c.Key, c.Old, c.New))
+	}
+	sort.Strings(changes)
+	assert.Equal(t, []string{
+		fmt.Sprintf("%d:6000:0:6000", Added),
+		fmt.Sprintf("%d:20:20:0", Removed),
+		fmt.Sprintf("%d:10:10:-10", Modified),
+	}, changes)
+
+	// Identical versions have no changes
+	for range after.Diff(after, equalInts) {
+		t.Fatal("unexpected change")
+	}
+}
+
+func TestDiffRandomized(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+	a, b := New[int, int](), New[int, int]()
+	for i := 0; i < 500; i++ {
+		a = a.Assoc(rng.IntN(1000), rng.IntN(3))
+		b = b.Assoc(rng.IntN(1000), rng.IntN(3))
+	}
+
+	// Applying the diff to a model of a must yield b
+	model := toMap(a)
+	for c := range a.Diff(b, equalInts) {
+		switch c.Kind {
+		case Added, Modified:
+			model[c.Key] = c.New
+		case Removed:
+			delete(model, c.Key)
+		}
+	}
+	assert.Equal(t, toMap(b), model)
+}
+
+func BenchmarkAssoc(b *testing.B) {
+	m := New[int, int]()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m = m.Assoc(i, i)
+	}
+}
+
+func BenchmarkTransientAssoc(b *testing.B) {
+	tr := New[int, int]().Transient()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tr.Assoc(i, i)
+	}
+}
+
+func BenchmarkGet(b *testing.B) {
+	tr := New[int, int]().Transient()
+	for i := 0; i < 100000; i++ {
+		tr.Assoc(i, i)
+	}
+	m := tr.Persistent()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Get(i % 100000)
+	}
+}
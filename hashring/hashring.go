@@ -0,0 +1,118 @@
+package hashring
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+// Errors returned by the selectors
+var (
+	ErrNoNodes           = errors.New("no nodes")
+	ErrNodeExists        = errors.New("node already exists")
+	ErrUnknownNode       = errors.New("unknown node")
+	ErrInvalidWeight     = errors.New("weight must be positive")
+	ErrUnsupportedWeight = errors.New("weights are not supported")
+)
+
+// Selector maps keys to nodes so that changing the membership moves few keys
+// Ring, Jump and Rendezvous implement it.
+type Selector interface {
+	// Add adds a node, a node with weight 2 receives twice as many keys as one with weight 1
+	Add(node string, weight int) error
+	// Remove removes a node, its keys move to the remaining nodes
+	Remove(node string) error
+	// Get returns the node owning key
+	Get(key string) (string, error)
+	// GetN returns up to n distinct nodes for key, starting with the owner, to place replicas on
+	GetN(key string, n int) ([]string, error)
+	// Nodes returns the members
+	Nodes() []string
+}
+
+type Config struct {
+	// VirtualNodes is the number of points a Ring places per unit of weight (default 160)
+	VirtualNodes int
+	// LoadFactor bounds the load of every Ring node to LoadFactor times the average for Acquire, at least 1, 0 disables the bound
+	LoadFactor     float64
+	MetricsEnabled bool
+}
+
+// Metrics shared by all selectors, initialized by the first one created with metrics enabled
+var (
+	MembersGauge   metrics.Gauge
+	LookupDuration metrics.Timer
+)
+
+var metricsOnce sync.Once
+
+// Initialize the metrics
+func initMetrics() {
+	metricsOnce.Do(func() {
+		MembersGauge = metrics.NewGauge()
+		LookupDuration = metrics.NewTimer()
+		metrics.Register("hashring.members", MembersGauge)
+		metrics.Register("hashring.lookup", LookupDuration)
+	})
+}
+
+// metered holds the metrics bookkeeping common to the selectors
+type metered struct {
+	config Config
+}
+
+func newMetered(config Config) metered {
+	// Initialize the metrics only if enabled in the config
+	if config.MetricsEnabled {
+		initMetrics()
+	}
+	return metered{config: config}
+}
+
+// members records the membership size
+func (m metered) members(n int) {
+	// Track metrics if enabled
+	if m.config.MetricsEnabled {
+		MembersGauge.Update(int64(n))
+	}
+}
+
+// lookup records the duration of a lookup started at start
+func (m metered) lookup(start time.Time) {
+	// Track metrics if enabled
+	if m.config.MetricsEnabled {
+		LookupDuration.UpdateSince(start)
+	}
+}
+
+// hash maps a string to 64 bits: FNV-1a followed by the splitmix64 finalizer
+// FNV alone is stable across processes, which placement requires, but mixes
+// the last bytes poorly.
+func hash(s string) uint64 {
+	return mix(fnv(fnvOffset, s))
+}
+
+const (
+	fnvOffset = 14695981039346656037
+	fnvPrime  = 1099511628211
+)
+
+// fnv continues the FNV-1a hash h with the bytes of s
+func fnv(h uint64, s string) uint64 {
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= fnvPrime
+	}
+	return h
+}
+
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,145 @@
+package hashring
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// selectors creates every Selector
+func selectors() map[string]Selector {
+	return map[string]Selector{
+		"Ring":       NewRing(Config{}),
+		"Jump":       NewJump(Config{}),
+		"Rendezvous": NewRendezvous(Config{}),
+	}
+}
+
+// assignments returns the node of every key
+func assignments(t *testing.T, s Selector, keys int) map[string]string {
+	owners := make(map[string]string, keys)
+	for i := 0; i < keys; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		node, err := s.Get(key)
+		assert.NoError(t, err)
+		owners[key] = node
+	}
+	return owners
+}
+
+func TestSelectorMembership(t *testing.T) {
+	for name, s := range selectors() {
+		t.Run(name, func(t *testing.T) {
+			_, err := s.Get("key")
+			assert.Equal(t, ErrNoNodes, err)
+			_, err = s.GetN("key", 2)
+			assert.Equal(t, ErrNoNodes, err)
+
+			for _, node := range []string{"a", "b", "c"} {
+				assert.NoError(t, s.Add(node, 1))
+			}
+			assert.Equal(t, ErrNodeExists, s.Add("a", 1))
+			assert.ElementsMatch(t, []string{"a", "b", "c"}, s.Nodes())
+
+			owner, err := s.Get("key")
+			assert.NoError(t, err)
+			replicas, err := s.GetN("key", 2)
+			assert.NoError(t, err)
+			assert.Len(t, replicas, 2)
+			assert.Equal(t, owner, replicas[0])
+			assert.NotEqual(t, replicas[0], replicas[1])
+
+			replicas, err = s.GetN("key", 10)
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, []string{"a", "b", "c"}, replicas)
+			replicas, err = s.GetN("key", 0)
+			assert.NoError(t, err)
+			assert.Empty(t, replicas)
+
+			assert.NoError(t, s.Remove("b"))
+			assert.Equal(t, ErrUnknownNode, s.Remove("b"))
+			assert.ElementsMatch(t, []string{"a", "c"}, s.Nodes())
+		})
+	}
+}
+
+func TestSelectorMinimalMovement(t *testing.T) {
+	const keys = 20000
+	for name, s := range selectors() {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 10; i++ {
+				assert.NoError(t, s.Add(fmt.Sprintf("node-%d", i), 1))
+			}
+			before := assignments(t, s, keys)
+
+			// Every node gets roughly a tenth of the keys
+			counts := make(map[string]int)
+			for _, node := range before {
+				counts[node]++
+			}
+			for node, count := range counts {
+				assert.InDelta(t, keys/10, count, keys/10*0.3, node)
+			}
+
+			// Only keys moving to the new node change owner, about an eleventh of them
+			assert.NoError(t, s.Add("node-10", 1))
+			after := assignments(t, s, keys)
+			moved := 0
+			for key, node := range after {
+				if node != before[key] {
+					assert.Equal(t, "node-10", node)
+					moved++
+				}
+			}
+			assert.InDelta(t, keys/11, moved, keys/11*0.3)
+
+			// Removing the new node gives its keys back
+			assert.NoError(t, s.Remove("node-10"))
+			assert.Equal(t, before, assignments(t, s, keys))
+		})
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	r := NewRing(Config{MetricsEnabled: true})
+	r.Add("a", 1)
+	r.Add("b", 1)
+	assert.Equal(t, int64(2), MembersGauge.Value())
+
+	count := LookupDuration.Count()
+	r.Get("key")
+	r.GetN("key", 2)
+	assert.Equal(t, count+2, LookupDuration.Count())
+
+	j := NewJump(Config{MetricsEnabled: true})
+	j.Add("a", 1)
+	assert.Equal(t, int64(1), MembersGauge.Value())
+}
+
+func benchmarkGet(b *testing.B, s Selector) {
+	for i := 0; i < 100; i++ {
+		s.Add(fmt.Sprintf("node-%d", i), 1)
+	}
+	keys := make([]string, 1000)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Get(keys[i%len(keys)])
+	}
+}
+
+func BenchmarkRingGet(b *testing.B) {
+	benchmarkGet(b, NewRing(Config{}))
+}
+
+func BenchmarkJumpGet(b *testing.B) {
+	benchmarkGet(b, NewJump(Config{}))
+}
+
+func BenchmarkRendezvousGet(b *testing.B) {
+	benchmarkGet(b, NewRendezvous(Config{}))
+}
@@ -0,0 +1,114 @@
+package hashring
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Jump maps keys to nodes with jump consistent hashing
+// It needs no memory besides the member list and spreads keys evenly, but
+// nodes are numbered buckets: removing the last added node moves only its
+// keys, while removing another one also moves the keys of the last node,
+// which takes its bucket. Weights are not supported.
+type Jump struct {
+	nodes   []string
+	buckets map[string]int
+	mu      sync.RWMutex
+	metered
+}
+
+var _ Selector = (*Jump)(nil)
+
+// NewJump creates an empty jump hash selector
+// The config parameter is used to enable or disable metrics collection
+func NewJump(config Config) *Jump {
+	return &Jump{buckets: make(map[string]int), metered: newMetered(config)}
+}
+
+// Add appends a node as the last bucket, its weight must be 1
+func (j *Jump) Add(node string, weight int) error {
+	j.mu.Lock() // Lock for writing
+	defer j.mu.Unlock()
+
+	if weight != 1 {
+		return ErrUnsupportedWeight
+	}
+	if _, ok := j.buckets[node]; ok {
+		return ErrNodeExists
+	}
+	j.buckets[node] = len(j.nodes)
+	j.nodes = append(j.nodes, node)
+	j.members(len(j.nodes))
+	return nil
+}
+
+// Remove removes a node, moving the last node into its bucket
+func (j *Jump) Remove(node string) error {
+	j.mu.Lock() // Lock for writing
+	defer j.mu.Unlock()
+
+	bucket, ok := j.buckets[node]
+	if !ok {
+		return ErrUnknownNode
+	}
+	last := j.nodes[len(j.nodes)-1]
+	j.nodes[bucket] = last
+	j.buckets[last] = bucket
+	j.nodes = j.nodes[:len(j.nodes)-1]
+	delete(j.buckets, node)
+	j.members(len(j.nodes))
+	return nil
+}
+
+// Get returns the node of the bucket jump hashing assigns to key
+func (j *Jump) Get(key string) (string, error) {
+	start := time.Now()
+	defer j.lookup(start)
+
+	j.mu.RLock() // Lock for reading
+	defer j.mu.RUnlock()
+
+	if len(j.nodes) == 0 {
+		return "", ErrNoNodes
+	}
+	return j.nodes[jump(hash(key), len(j.nodes))], nil
+}
+
+// GetN returns the node of key followed by the nodes of the next buckets
+func (j *Jump) GetN(key string, n int) ([]string, error) {
+	start := time.Now()
+	defer j.lookup(start)
+
+	j.mu.RLock() // Lock for reading
+	defer j.mu.RUnlock()
+
+	if len(j.nodes) == 0 {
+		return nil, ErrNoNodes
+	}
+	n = max(min(n, len(j.nodes)), 0)
+	nodes := make([]string, 0, n)
+	for bucket := jump(hash(key), len(j.nodes)); len(nodes) < n; bucket = (bucket + 1) % len(j.nodes) {
+		nodes = append(nodes, j.nodes[bucket])
+	}
+	return nodes, nil
+}
+
+// Nodes returns the members in bucket order
+func (j *Jump) Nodes() []string {
+	j.mu.RLock() // Lock for reading
+	defer j.mu.RUnlock()
+
+	return slices.Clone(j.nodes)
+}
+
+// jump returns the bucket of key among buckets, as described by Lamping and Veach
+func jump(key uint64, buckets int) int {
+	b, next := int64(-1), int64(0)
+	for next < int64(buckets) {
+		b = next
+		key = key*2862933555777941757 + 1
+		next = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
@@ -0,0 +1,38 @@
+package hashring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJumpFunction(t *testing.T) {
+	// Growing from n to n+1 buckets only moves keys to the new bucket
+	for key := uint64(0); key < 2000; key++ {
+		previous := jump(mix(key), 1)
+		assert.Equal(t, 0, previous)
+		for buckets := 2; buckets <= 50; buckets++ {
+			b := jump(mix(key), buckets)
+			assert.True(t, b == previous || b == buckets-1)
+			previous = b
+		}
+	}
+}
+
+func TestJumpRemove(t *testing.T) {
+	j := NewJump(Config{})
+	assert.Equal(t, ErrUnsupportedWeight, j.Add("a", 2))
+	for _, node := range []string{"a", "b", "c", "d"} {
+		j.Add(node, 1)
+	}
+	before := assignments(t, j, 5000)
+
+	// The last node takes the bucket of the removed one
+	assert.NoError(t, j.Remove("b"))
+	assert.Equal(t, []string{"a", "d", "c"}, j.Nodes())
+	for key, node := range assignments(t, j, 5000) {
+		if before[key] == "a" || before[key] == "c" {
+			assert.Equal(t, before[key], node)
+		}
+	}
+}
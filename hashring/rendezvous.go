@@ -0,0 +1,129 @@
+package hashring
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Rendezvous maps keys to nodes with weighted rendezvous (highest random weight) hashing
+// Every node scores every key and the highest score wins, so only the keys
+// of an added or removed node move. Lookups cost time linear in the number
+// of nodes.
+type Rendezvous struct {
+	weights map[string]int
+	mu      sync.RWMutex
+	metered
+}
+
+var _ Selector = (*Rendezvous)(nil)
+
+// NewRendezvous creates an empty rendezvous hash selector
+// The config parameter is used to enable or disable metrics collection
+func NewRendezvous(config Config) *Rendezvous {
+	return &Rendezvous{weights: make(map[string]int), metered: newMetered(config)}
+}
+
+// Add adds a node with the given weight
+func (r *Rendezvous) Add(node string, weight int) error {
+	r.mu.Lock() // Lock for writing
+	defer r.mu.Unlock()
+
+	if weight <= 0 {
+		return ErrInvalidWeight
+	}
+	if _, ok := r.weights[node]; ok {
+		return ErrNodeExists
+	}
+	r.weights[node] = weight
+	r.members(len(r.weights))
+	return nil
+}
+
+// Remove removes a node
+func (r *Rendezvous) Remove(node string) error {
+	r.mu.Lock() // Lock for writing
+	defer r.mu.Unlock()
+
+	if _, ok := r.weights[node]; !ok {
+		return ErrUnknownNode
+	}
+	delete(r.weights, node)
+	r.members(len(r.weights))
+	return nil
+}
+
+// Get returns the node with the highest score for key
+func (r *Rendezvous) Get(key string) (string, error) {
+	start := time.Now()
+	defer r.lookup(start)
+
+	r.mu.RLock() // Lock for reading
+	defer r.mu.RUnlock()
+
+	if len(r.weights) == 0 {
+		return "", ErrNoNodes
+	}
+	best, bestScore := "", math.Inf(-1)
+	for node, weight := range r.weights {
+		// Ties go to the lowest node so that the result does not depend on map order
+		if s := score(node, key, weight); s > bestScore || s == bestScore && node < best {
+			best, bestScore = node, s
+		}
+	}
+	return best, nil
+}
+
+// GetN returns the n nodes with the highest scores for key, best first
+func (r *Rendezvous) GetN(key string, n int) ([]string, error) {
+	start := time.Now()
+	defer r.lookup(start)
+
+	r.mu.RLock() // Lock for reading
+	defer r.mu.RUnlock()
+
+	if len(r.weights) == 0 {
+		return nil, ErrNoNodes
+	}
+	type scored struct {
+		node  string
+		score float64
+	}
+	scores := make([]scored, 0, len(r.weights))
+	for node, weight := range r.weights {
+		scores = append(scores, scored{node, score(node, key, weight)})
+	}
+	slices.SortFunc(scores, func(a, b scored) int {
+		return cmp.Or(cmp.Compare(b.score, a.score), cmp.Compare(a.node, b.node))
+	})
+
+	n = max(min(n, len(scores)), 0)
+	nodes := make([]string, 0, n)
+	for _, s := range scores[:n] {
+		nodes = append(nodes, s.node)
+	}
+	return nodes, nil
+}
+
+// Nodes returns the members in lexicographic order
+func (r *Rendezvous) Nodes() []string {
+	r.mu.RLock() // Lock for reading
+	defer r.mu.RUnlock()
+
+	nodes := make([]string, 0, len(r.weights))
+	for node := range r.weights {
+		nodes = append(nodes, node)
+	}
+	slices.Sort(nodes)
+	return nodes
+}
+
+// score is the weighted rendezvous score -weight/ln(u), u uniform in (0, 1) derived from node and key
+// Each node wins a key with probability proportional to its weight.
+func score(node, key string, weight int) float64 {
+	h := mix(fnv(fnv(fnv(fnvOffset, node), "\x00"), key))
+	u := (float64(h>>11) + 0.5) / (1 << 53)
+	return -float64(weight) / math.Log(u)
+}
@@ -0,0 +1,37 @@
+package hashring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRendezvousWeights(t *testing.T) {
+	r := NewRendezvous(Config{})
+	assert.Equal(t, ErrInvalidWeight, r.Add("a", -1))
+	r.Add("small", 1)
+	r.Add("large", 3)
+
+	counts := make(map[string]int)
+	for _, node := range assignments(t, r, 20000) {
+		counts[node]++
+	}
+	assert.InDelta(t, 15000, counts["large"], 1000)
+	assert.InDelta(t, 5000, counts["small"], 1000)
+}
+
+func TestRendezvousRemove(t *testing.T) {
+	r := NewRendezvous(Config{})
+	for _, node := range []string{"a", "b", "c", "d"} {
+		r.Add(node, 1)
+	}
+	before := assignments(t, r, 5000)
+
+	// Only the keys of the removed node move
+	r.Remove("b")
+	for key, node := range assignments(t, r, 5000) {
+		if before[key] != "b" {
+			assert.Equal(t, before[key], node)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package hashring
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const defaultVirtualNodes = 160
+
+// point is a virtual node on the ring
+type point struct {
+	hash uint64
+	node string
+}
+
+// Ring is a consistent hashing ring with weighted virtual nodes
+// Every node is placed at VirtualNodes*weight points and a key belongs to the
+// first point clockwise from its hash, so adding or removing a node only moves
+// the keys of the arcs it gains or loses.
+//
+// With a LoadFactor, Acquire implements consistent hashing with bounded loads:
+// a key skips the nodes already holding more than LoadFactor times their
+// share of the acquired keys.
+type Ring struct {
+	points  []point // Sorted by hash
+	weights map[string]int
+	total   int // Sum of the weights
+	loads   map[string]int
+	load    int // Sum of the loads
+	mu      sync.RWMutex
+	metered
+}
+
+var _ Selector = (*Ring)(nil)
+
+// NewRing creates an empty ring
+// The config parameter sets the number of virtual nodes and the load bound and enables metrics collection
+func NewRing(config Config) *Ring {
+	if config.VirtualNodes <= 0 {
+		config.VirtualNodes = defaultVirtualNodes
+	}
+	// Below 1 the bounds cannot hold every key
+	if config.LoadFactor > 0 && config.LoadFactor < 1 {
+		config.LoadFactor = 1
+	}
+	return &Ring{weights: make(map[string]int), loads: make(map[string]int), metered: newMetered(config)}
+}
+
+// Add places a node on the ring at VirtualNodes*weight points
+func (r *Ring) Add(node string, weight int) error {
+	r.mu.Lock() // Lock for writing
+	defer r.mu.Unlock()
+
+	if weight <= 0 {
+		return ErrInvalidWeight
+	}
+	if _, ok := r.weights[node]; ok {
+		return ErrNodeExists
+	}
+	for i := 0; i < r.config.VirtualNodes*weight; i++ {
+		r.points = append(r.points, point{hash: hash(node + "#" + strconv.Itoa(i)), node: node})
+	}
+	slices.SortFunc(r.points, comparePoints)
+	r.weights[node] = weight
+	r.total += weight
+	r.members(len(r.weights))
+	return nil
+}
+
+// comparePoints orders points by hash, colliding hashes by node so that the order does not depend on insertion
+func comparePoints(a, b point) int {
+	if a.hash != b.hash {
+		if a.hash < b.hash {
+			return -1
+		}
+		return 1
+	}
+	if a.node < b.node {
+		return -1
+	}
+	if a.node > b.node {
+		return 1
+	}
+	return 0
+}
+
+// Remove takes a node and its points off the ring, its load is forgotten
+func (r *Ring) Remove(node string) error {
+	r.mu.Lock() // Lock for writing
+	defer r.mu.Unlock()
+
+	weight, ok := r.weights[node]
+	if !ok {
+		return ErrUnknownNode
+	}
+	r.points = slices.DeleteFunc(r.points, func(p point) bool { return p.node == node })
+	delete(r.weights, node)
+	r.total -= weight
+	r.load -= r.loads[node]
+	delete(r.loads, node)
+	r.members(len(r.weights))
+	return nil
+}
+
+// Get returns the node of the first point clockwise from the hash of key
+func (r *Ring) Get(key string) (string, error) {
+	start := time.Now()
+	defer r.lookup(start)
+
+	r.mu.RLock() // Lock for reading
+	defer r.mu.RUnlock()
+
+	if len(r.points) == 0 {
+		return "", ErrNoNodes
+	}
+	return r.points[r.search(key)].node, nil
+}
+
+// GetN returns the first n distinct nodes clockwise from the hash of key
+func (r *Ring) GetN(key string, n int) ([]string, error) {
+	start := time.Now()
+	defer r.lookup(start)
+
+	r.mu.RLock() // Lock for reading
+	defer r.mu.RUnlock()
+
+	if len(r.points) == 0 {
+		return nil, ErrNoNodes
+	}
+	n = max(min(n, len(r.weights)), 0)
+	nodes := make([]string, 0, n)
+	for i := r.search(key); len(nodes) < n; i = (i + 1) % len(r.points) {
+		if node := r.points[i].node; !slices.Contains(nodes, node) {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes, nil
+}
+
+// search returns the index of the first point at or after the hash of key, wrapping around
+func (r *Ring) search(key string) int {
+	h := hash(key)
+	i, _ := slices.BinarySearchFunc(r.points, h, func(p point, h uint64) int {
+		if p.hash < h {
+			return -1
+		}
+		if p.hash > h {
+			return 1
+		}
+		return 0
+	})
+	if i == len(r.points) {
+		return 0
+	}
+	return i
+}
+
+// Acquire assigns key to the first node clockwise that is below its load bound and counts it there
+// A node's bound is LoadFactor times its weighted share of the acquired
+// keys, including this one, rounded up. Release must be called once the key
+// is no longer held.
+func (r *Ring) Acquire(key string) (string, error) {
+	start := time.Now()
+	defer r.lookup(start)
+
+	r.mu.Lock() // Lock for writing
+	defer r.mu.Unlock()
+
+	if len(r.points) == 0 {
+		return "", ErrNoNodes
+	}
+	node := r.points[r.search(key)].node
+	if r.config.LoadFactor > 0 {
+		for i := r.search(key); ; i = (i + 1) % len(r.points) {
+			node = r.points[i].node
+			if float64(r.loads[node]+1) <= r.capacity(node) {
+				break
+			}
+		}
+	}
+	r.loads[node]++
+	r.load++
+	return node, nil
+}
+
+// capacity returns the maximum load of node once one more key is acquired
+// The bounds add up to at least LoadFactor times the new load, so some node always has room.
+func (r *Ring) capacity(node string) float64 {
+	return math.Ceil(r.config.LoadFactor * float64(r.load+1) * float64(r.weights[node]) / float64(r.total))
+}
+
+// Release decrements the load of node after a key acquired on it is dropped
+func (r *Ring) Release(node string) error {
+	r.mu.Lock() // Lock for writing
+	defer r.mu.Unlock()
+
+	if r.loads[node] == 0 {
+		return ErrUnknownNode
+	}
+	r.loads[node]--
+	r.load--
+	return nil
+}
+
+// Load returns the number of keys acquired on node and not yet released
+func (r *Ring) Load(node string) int {
+	r.mu.RLock() // Lock for reading
+	defer r.mu.RUnlock()
+
+	return r.loads[node]
+}
+
+// Nodes returns the members in lexicographic order
+func (r *Ring) Nodes() []string {
+	r.mu.RLock() // Lock for reading
+	defer r.mu.RUnlock()
+
+	nodes := make([]string, 0, len(r.weights))
+	for node := range r.weights {
+		nodes = append(nodes, node)
+	}
+	slices.Sort(nodes)
+	return nodes
+}
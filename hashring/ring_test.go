@@ -0,0 +1,87 @@
+package hashring
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRingWeights(t *testing.T) {
+	r := NewRing(Config{})
+	assert.Equal(t, ErrInvalidWeight, r.Add("a", 0))
+	assert.NoError(t, r.Add("small", 1))
+	assert.NoError(t, r.Add("large", 3))
+	assert.Len(t, r.points, 4*defaultVirtualNodes)
+
+	counts := make(map[string]int)
+	for _, node := range assignments(t, r, 20000) {
+		counts[node]++
+	}
+	assert.InDelta(t, 15000, counts["large"], 1500)
+	assert.InDelta(t, 5000, counts["small"], 1500)
+
+	assert.NoError(t, r.Remove("large"))
+	assert.Len(t, r.points, defaultVirtualNodes)
+}
+
+func TestRingBoundedLoad(t *testing.T) {
+	r := NewRing(Config{VirtualNodes: 10, LoadFactor: 1.25})
+	for i := 0; i < 4; i++ {
+		r.Add(fmt.Sprintf("node-%d", i), 1)
+	}
+
+	// The same key acquired over and over spills to other nodes once its owner is full
+	owner, _ := r.Get("hot")
+	acquired := make(map[string]int)
+	for i := 0; i < 400; i++ {
+		node, err := r.Acquire("hot")
+		assert.NoError(t, err)
+		acquired[node]++
+	}
+	assert.Len(t, acquired, 4)
+	for _, node := range r.Nodes() {
+		// ceil(1.25 * 400 / 4)
+		assert.LessOrEqual(t, r.Load(node), 125)
+	}
+	assert.Equal(t, 125, acquired[owner])
+
+	assert.NoError(t, r.Release(owner))
+	assert.Equal(t, 124, r.Load(owner))
+	assert.Equal(t, ErrUnknownNode, r.Release("missing"))
+
+	// The load of a removed node is forgotten
+	r.Remove(owner)
+	assert.Equal(t, 400-125, r.load)
+
+	// Without a load factor Acquire only counts
+	plain := NewRing(Config{})
+	plain.Add("a", 1)
+	plain.Add("b", 1)
+	for i := 0; i < 10; i++ {
+		node, _ := plain.Acquire("hot")
+		assert.Equal(t, ownerOf(plain, "hot"), node)
+	}
+}
+
+func ownerOf(r *Ring, key string) string {
+	node, _ := r.Get(key)
+	return node
+}
+
+func TestRingWraparound(t *testing.T) {
+	r := NewRing(Config{VirtualNodes: 1})
+	r.Add("a", 1)
+	r.Add("b", 1)
+	last := r.points[len(r.points)-1]
+	first := r.points[0]
+
+	// A key hashing after the last point belongs to the first one
+	for i := 0; ; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		if hash(key) > last.hash {
+			assert.Equal(t, first.node, ownerOf(r, key))
+			break
+		}
+	}
+}
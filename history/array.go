@@ -0,0 +1,73 @@
+package history
+
+import (
+	"github.com/vzahanych/data-structures/array"
+)
+
+// Array records the edits made to an array.Array in a History
+type Array[T any] struct {
+	array   *array.Array[T]
+	history *History
+}
+
+// WrapArray returns a wrapper recording the edits made to a in h
+func WrapArray[T any](a *array.Array[T], h *History) *Array[T] {
+	return &Array[T]{array: a, history: h}
+}
+
+// Append adds a new element to the array
+func (a *Array[T]) Append(value T) error {
+	index := a.array.Length()
+	if err := a.array.Append(value); err != nil {
+		return err
+	}
+	a.history.record(operation{
+		undo: func() error { return a.array.Delete(index) },
+		redo: func() error { return a.array.Append(value) },
+	})
+	return nil
+}
+
+// Delete removes the element at the specified index and shifts subsequent elements
+func (a *Array[T]) Delete(index int) error {
+	value, err := a.array.Get(index)
+	if err != nil {
+		return err
+	}
+	if err := a.array.Delete(index); err != nil {
+		return err
+	}
+	a.history.record(operation{
+		undo: func() error { return a.array.Insert(index, value) },
+		redo: func() error { return a.array.Delete(index) },
+	})
+	return nil
+}
+
+// Resize resizes the array to a new capacity
+func (a *Array[T]) Resize(newCapacity int) error {
+	oldCapacity := a.array.Capacity()
+	if err := a.array.Resize(newCapacity); err != nil {
+		return err
+	}
+	a.history.record(operation{
+		undo: func() error { return a.array.Resize(oldCapacity) },
+		redo: func() error { return a.array.Resize(newCapacity) },
+	})
+	return nil
+}
+
+// Get retrieves an element at the specified index
+func (a *Array[T]) Get(index int) (T, error) {
+	return a.array.Get(index)
+}
+
+// Length returns the current number of elements in the array
+func (a *Array[T]) Length() int {
+	return a.array.Length()
+}
+
+// Capacity returns the number of elements the array can hold without resizing
+func (a *Array[T]) Capacity() int {
+	return a.array.Capacity()
+}
@@ -0,0 +1,221 @@
+package history
+
+import (
+	"errors"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+// Errors returned by History
+var (
+	ErrNothingToUndo     = errors.New("nothing to undo")
+	ErrNothingToRedo     = errors.New("nothing to redo")
+	ErrUnknownCheckpoint = errors.New("unknown checkpoint")
+)
+
+const defaultMaxDepth = 1000
+
+type Config struct {
+	// MaxDepth bounds the number of undoable edits, the oldest ones are forgotten first (default 1000)
+	MaxDepth       int
+	MetricsEnabled bool
+}
+
+// operation is a recorded edit along with its inverse
+type operation struct {
+	undo func() error
+	redo func() error
+}
+
+// edit is the unit of Undo and Redo: a single operation or a whole group
+type edit []operation
+
+// History records the edits made through the containers wrapped with it and can revert them
+// Several containers may share a History so that Undo and Redo follow the
+// order of edits across all of them. A History is not safe for concurrent use,
+// and the wrapped containers must only be modified through their wrappers.
+type History struct {
+	done        []edit
+	undone      []edit
+	base        int            // Number of edits forgotten because of MaxDepth
+	checkpoints map[string]int // Position, counted from the first edit ever made, of each checkpoint
+	group       edit
+	grouping    int // Depth of nested Group calls
+	config      Config
+	depthGauge  metrics.Gauge
+	undoCounter metrics.Counter
+	redoCounter metrics.Counter
+}
+
+// New creates an empty history
+// The config parameter is used to set the maximum depth and enable or disable metrics collection
+func New(config Config) *History {
+	if config.MaxDepth <= 0 {
+		config.MaxDepth = defaultMaxDepth
+	}
+	h := &History{checkpoints: make(map[string]int), config: config}
+
+	// Initialize the metrics only if enabled in the config
+	if h.config.MetricsEnabled {
+		h.depthGauge = metrics.NewGauge()
+		h.undoCounter = metrics.NewCounter()
+		h.redoCounter = metrics.NewCounter()
+		metrics.DefaultRegistry.Register("history.depth", h.depthGauge)
+		metrics.DefaultRegistry.Register("history.undo", h.undoCounter)
+		metrics.DefaultRegistry.Register("history.redo", h.redoCounter)
+	}
+
+	return h
+}
+
+// Depth returns the number of edits that can be undone
+func (h *History) Depth() int {
+	return len(h.done)
+}
+
+// CanRedo reports whether an undone edit can be redone
+func (h *History) CanRedo() bool {
+	return len(h.undone) > 0
+}
+
+// record adds an operation that was just applied, the redo stack is dropped
+func (h *History) record(op operation) {
+	if h.grouping > 0 {
+		h.group = append(h.group, op)
+		return
+	}
+	h.push(edit{op})
+}
+
+func (h *History) push(e edit) {
+	h.undone = nil
+	// Checkpoints in the dropped redo stack can never be reached again
+	for name, position := range h.checkpoints {
+		if position > h.position() {
+			delete(h.checkpoints, name)
+		}
+	}
+
+	h.done = append(h.done, e)
+	if overflow := len(h.done) - h.config.MaxDepth; overflow > 0 {
+		clear(h.done[:overflow])
+		h.done = h.done[overflow:]
+		h.base += overflow
+	}
+	h.updateDepth()
+}
+
+// position returns the number of edits applied since the history was created
+func (h *History) position() int {
+	return h.base + len(h.done)
+}
+
+// Undo reverts the last edit
+func (h *History) Undo() error {
+	if len(h.done) == 0 {
+		return ErrNothingToUndo
+	}
+	e := h.done[len(h.done)-1]
+	for i := len(e) - 1; i >= 0; i-- {
+		if err := e[i].undo(); err != nil {
+			return err
+		}
+	}
+	h.done = h.done[:len(h.done)-1]
+	h.undone = append(h.undone, e)
+
+	// Track metrics if enabled
+	if h.config.MetricsEnabled {
+		h.undoCounter.Inc(1)
+	}
+	h.updateDepth()
+	return nil
+}
+
+// Redo applies again the last undone edit
+func (h *History) Redo() error {
+	if len(h.undone) == 0 {
+		return ErrNothingToRedo
+	}
+	e := h.undone[len(h.undone)-1]
+	for _, op := range e {
+		if err := op.redo(); err != nil {
+			return err
+		}
+	}
+	h.undone = h.undone[:len(h.undone)-1]
+	h.done = append(h.done, e)
+
+	// Track metrics if enabled
+	if h.config.MetricsEnabled {
+		h.redoCounter.Inc(1)
+	}
+	h.updateDepth()
+	return nil
+}
+
+// Checkpoint names the current state so that RestoreCheckpoint can return to it
+// Reusing a name moves the checkpoint.
+func (h *History) Checkpoint(name string) {
+	h.checkpoints[name] = h.position()
+}
+
+// RestoreCheckpoint undoes or redoes edits until the state named by Checkpoint is reached
+// It fails with ErrUnknownCheckpoint if the checkpoint was never set, fell
+// beyond MaxDepth, or was in a redo stack dropped by a new edit.
+func (h *History) RestoreCheckpoint(name string) error {
+	target, found := h.checkpoints[name]
+	if !found || target < h.base {
+		return ErrUnknownCheckpoint
+	}
+	for h.position() > target {
+		if err := h.Undo(); err != nil {
+			return err
+		}
+	}
+	for h.position() < target {
+		if err := h.Redo(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Group runs fn and records the edits it makes as a single one
+// If fn returns an error, the edits it made are reverted and the error is
+// returned, joined with the errors of the edits that could not be reverted.
+// Groups may be nested, the outermost one defines the edit.
+func (h *History) Group(fn func() error) error {
+	h.grouping++
+	start := len(h.group)
+	err := fn()
+	h.grouping--
+
+	if err != nil {
+		// Revert this group's operations, in reverse order
+		var undoErrs []error
+		for i := len(h.group) - 1; i >= start; i-- {
+			if undoErr := h.group[i].undo(); undoErr != nil {
+				undoErrs = append(undoErrs, undoErr)
+			}
+		}
+		if len(undoErrs) > 0 {
+			err = errors.Join(append([]error{err}, undoErrs...)...)
+		}
+		h.group = h.group[:start]
+	}
+	if h.grouping == 0 {
+		if len(h.group) > 0 {
+			h.push(h.group)
+		}
+		h.group = nil
+	}
+	return err
+}
+
+func (h *History) updateDepth() {
+	// Track metrics if enabled
+	if h.config.MetricsEnabled {
+		h.depthGauge.Update(int64(len(h.done)))
+	}
+}
@@ -0,0 +1,209 @@
+package history
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vzahanych/data-structures/array"
+	"github.com/vzahanych/data-structures/linkedlist"
+)
+
+func values(a *Array[int]) []int {
+	result := make([]int, 0, a.Length())
+	for i := 0; i < a.Length(); i++ {
+		v, _ := a.Get(i)
+		result = append(result, v)
+	}
+	return result
+}
+
+func TestUndoRedo(t *testing.T) {
+	h := New(Config{})
+	a := WrapArray(array.NewArray[int](2, array.ArrayConfig{}), h)
+
+	assert.NoError(t, a.Append(1))
+	assert.NoError(t, a.Append(2))
+	assert.NoError(t, a.Resize(4))
+	assert.NoError(t, a.Append(3))
+	assert.NoError(t, a.Delete(0))
+	assert.Error(t, a.Delete(5)) // Failed edits are not recorded
+	assert.Equal(t, []int{2, 3}, values(a))
+	assert.Equal(t, 5, h.Depth())
+
+	assert.NoError(t, h.Undo())
+	assert.Equal(t, []int{1, 2, 3}, values(a))
+	assert.NoError(t, h.Undo())
+	assert.NoError(t, h.Undo())
+	assert.Equal(t, 2, a.Capacity())
+	assert.Equal(t, []int{1, 2}, values(a))
+
+	assert.NoError(t, h.Redo())
+	assert.NoError(t, h.Redo())
+	assert.NoError(t, h.Redo())
+	assert.Equal(t, []int{2, 3}, values(a))
+	assert.Equal(t, 4, a.Capacity())
+	assert.Equal(t, ErrNothingToRedo, h.Redo())
+
+	for h.Depth() > 0 {
+		assert.NoError(t, h.Undo())
+	}
+	assert.Empty(t, values(a))
+	assert.Equal(t, ErrNothingToUndo, h.Undo())
+}
+
+func TestNewEditDropsRedo(t *testing.T) {
+	h := New(Config{})
+	a := WrapArray(array.NewArray[int](5, array.ArrayConfig{}), h)
+
+	a.Append(1)
+	a.Append(2)
+	assert.NoError(t, h.Undo())
+	assert.True(t, h.CanRedo())
+	a.Append(3)
+	assert.False(t, h.CanRedo())
+	assert.Equal(t, []int{1, 3}, values(a))
+}
+
+func TestLinkedList(t *testing.T) {
+	h := New(Config{})
+	l := WrapLinkedList(linkedlist.NewLinkedList(), h)
+
+	l.Add("a")
+	l.Add("b")
+	l.Add("a")
+	l.Remove("a") // Removes the first one
+	l.Remove("z") // Absent, not recorded
+	assert.Equal(t, []interface{}{"b", "a"}, l.Values())
+	assert.Equal(t, 4, h.Depth())
+
+	assert.NoError(t, h.Undo())
+	assert.Equal(t, []interface{}{"a", "b", "a"}, l.Values())
+	assert.NoError(t, h.Undo())
+	assert.NoError(t, h.Undo())
+	assert.Equal(t, []interface{}{"a"}, l.Values())
+	assert.NoError(t, h.Redo())
+	assert.NoError(t, h.Redo())
+	assert.NoError(t, h.Redo())
+	assert.Equal(t, []interface{}{"b", "a"}, l.Values())
+	assert.NotNil(t, l.Find("b"))
+	assert.Equal(t, 2, l.Length())
+}
+
+func TestGroup(t *testing.T) {
+	h := New(Config{})
+	a := WrapArray(array.NewArray[int](10, array.ArrayConfig{}), h)
+	l := WrapLinkedList(linkedlist.NewLinkedList(), h)
+
+	// Move an element from the array to the list as a single edit
+	a.Append(7)
+	err := h.Group(func() error {
+		v, _ := a.Get(0)
+		if err := a.Delete(0); err != nil {
+			return err
+		}
+		l.Add(v)
+		return h.Group(func() error { // Nested groups join the outer one
+			return a.Append(8)
+		})
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, h.Depth())
+	assert.Equal(t, []int{8}, values(a))
+
+	assert.NoError(t, h.Undo())
+	assert.Equal(t, []int{7}, values(a))
+	assert.Equal(t, 0, l.Length())
+	assert.NoError(t, h.Redo())
+	assert.Equal(t, []interface{}{7}, l.Values())
+
+	// A failing group is rolled back and not recorded
+	failure := errors.New("failure")
+	err = h.Group(func() error {
+		a.Append(9)
+		l.Add(9)
+		return failure
+	})
+	assert.Equal(t, failure, err)
+	assert.Equal(t, []int{8}, values(a))
+	assert.Equal(t, []interface{}{7}, l.Values())
+	assert.Equal(t, 2, h.Depth())
+
+	// A rollback that fails is reported along with the error of the group
+	err = h.Group(func() error {
+		l.Add(10)
+		a.Append(10)
+		a.array.Delete(1) // Bypassing the wrapper breaks the undo of the append
+		return failure
+	})
+	assert.ErrorIs(t, err, failure)
+	assert.ErrorContains(t, err, "index out of bounds")
+	assert.Equal(t, []interface{}{7}, l.Values())
+	assert.Equal(t, 2, h.Depth())
+}
+
+func TestCheckpoints(t *testing.T) {
+	h := New(Config{})
+	a := WrapArray(array.NewArray[int](10, array.ArrayConfig{}), h)
+
+	h.Checkpoint("empty")
+	a.Append(1)
+	a.Append(2)
+	h.Checkpoint("two")
+	a.Append(3)
+
+	assert.NoError(t, h.RestoreCheckpoint("empty"))
+	assert.Empty(t, values(a))
+	assert.NoError(t, h.RestoreCheckpoint("two"))
+	assert.Equal(t, []int{1, 2}, values(a))
+	assert.Equal(t, ErrUnknownCheckpoint, h.RestoreCheckpoint("missing"))
+
+	// A new edit after going back drops the checkpoints ahead
+	assert.NoError(t, h.RestoreCheckpoint("empty"))
+	a.Append(4)
+	assert.Equal(t, ErrUnknownCheckpoint, h.RestoreCheckpoint("two"))
+	assert.NoError(t, h.RestoreCheckpoint("empty"))
+}
+
+func TestMaxDepth(t *testing.T) {
+	h := New(Config{MaxDepth: 3})
+	a := WrapArray(array.NewArray[int](10, array.ArrayConfig{}), h)
+
+	h.Checkpoint("start")
+	for i := 0; i < 5; i++ {
+		a.Append(i)
+	}
+	assert.Equal(t, 3, h.Depth())
+	assert.Equal(t, ErrUnknownCheckpoint, h.RestoreCheckpoint("start"))
+
+	for h.Depth() > 0 {
+		assert.NoError(t, h.Undo())
+	}
+	assert.Equal(t, []int{0, 1}, values(a))
+}
+
+func TestMetrics(t *testing.T) {
+	h := New(Config{MetricsEnabled: true})
+	a := WrapArray(array.NewArray[int](10, array.ArrayConfig{}), h)
+
+	a.Append(1)
+	a.Append(2)
+	assert.Equal(t, int64(2), h.depthGauge.Value())
+	h.Undo()
+	assert.Equal(t, int64(1), h.depthGauge.Value())
+	h.Redo()
+	assert.Equal(t, int64(2), h.depthGauge.Value())
+	assert.Equal(t, int64(1), h.undoCounter.Count())
+	assert.Equal(t, int64(1), h.redoCounter.Count())
+}
+
+func BenchmarkAppendUndo(b *testing.B) {
+	h := New(Config{})
+	a := WrapArray(array.NewArray[int](1, array.ArrayConfig{}), h)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.Append(i)
+		h.Undo()
+	}
+}
@@ -0,0 +1,61 @@
+package history
+
+import (
+	"github.com/vzahanych/data-structures/linkedlist"
+)
+
+// LinkedList records the edits made to a linkedlist.LinkedList in a History
+type LinkedList struct {
+	list    *linkedlist.LinkedList
+	history *History
+}
+
+// WrapLinkedList returns a wrapper recording the edits made to l in h
+func WrapLinkedList(l *linkedlist.LinkedList, h *History) *LinkedList {
+	return &LinkedList{list: l, history: h}
+}
+
+// Add an element at the end of the list
+func (l *LinkedList) Add(data interface{}) {
+	index := l.list.Length()
+	l.list.Add(data)
+	l.history.record(operation{
+		undo: func() error {
+			_, err := l.list.RemoveAt(index)
+			return err
+		},
+		redo: func() error { return l.list.InsertAt(index, data) },
+	})
+}
+
+// Remove the first occurrence of an element from the list
+// Nothing is recorded when the element is absent.
+func (l *LinkedList) Remove(data interface{}) {
+	index := l.list.IndexOf(data)
+	if index < 0 {
+		return
+	}
+	l.list.Remove(data)
+	l.history.record(operation{
+		undo: func() error { return l.list.InsertAt(index, data) },
+		redo: func() error {
+			_, err := l.list.RemoveAt(index)
+			return err
+		},
+	})
+}
+
+// Find an element in the list
+func (l *LinkedList) Find(data interface{}) *linkedlist.Node {
+	return l.list.Find(data)
+}
+
+// Length returns the number of elements in the list
+func (l *LinkedList) Length() int {
+	return l.list.Length()
+}
+
+// Values returns the elements of the list in order
+func (l *LinkedList) Values() []interface{} {
+	return l.list.Values()
+}
@@ -0,0 +1,356 @@
+package interval
+
+import (
+	"cmp"
+	"errors"
+	"iter"
+	"sync"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+// ErrInvalidInterval is returned for intervals whose start is not before their end
+var ErrInvalidInterval = errors.New("invalid interval")
+
+// Interval is the half-open range [Start, End)
+type Interval[K cmp.Ordered] struct {
+	Start K
+	End   K
+}
+
+// Overlaps reports whether both intervals share at least one point
+func (i Interval[K]) Overlaps(other Interval[K]) bool {
+	return i.Start < other.End && other.Start < i.End
+}
+
+// Contains reports whether point lies in the interval
+func (i Interval[K]) Contains(point K) bool {
+	return i.Start <= point && point < i.End
+}
+
+type TreeConfig struct {
+	MetricsEnabled bool
+}
+
+// node stores every value inserted with the same interval
+// maxEnd is the largest End in the subtree rooted at the node
+type node[K cmp.Ordered, V any] struct {
+	interval    Interval[K]
+	values      []V
+	maxEnd      K
+	height      int
+	left, right *node[K, V]
+}
+
+// Tree is an interval tree built on an AVL tree ordered by (Start, End)
+// and augmented with the maximum End of each subtree.
+type Tree[K cmp.Ordered, V any] struct {
+	root          *node[K, V]
+	size          int
+	mu            sync.RWMutex
+	config        TreeConfig
+	insertCounter metrics.Counter
+	deleteCounter metrics.Counter
+	queryCounter  metrics.Counter
+}
+
+// NewTree creates an empty interval tree
+// The config parameter is used to enable or disable metrics collection
+func NewTree[K cmp.Ordered, V any](config TreeConfig) *Tree[K, V] {
+	t := &Tree[K, V]{config: config}
+
+	// Initialize the metrics only if enabled in the config
+	if t.config.MetricsEnabled {
+		t.insertCounter = metrics.NewCounter()
+		t.deleteCounter = metrics.NewCounter()
+		t.queryCounter = metrics.NewCounter()
+		metrics.DefaultRegistry.Register("interval.insert", t.insertCounter)
+		metrics.DefaultRegistry.Register("interval.delete", t.deleteCounter)
+		metrics.DefaultRegistry.Register("interval.query", t.queryCounter)
+	}
+
+	return t
+}
+
+// Insert adds a value for the interval [start, end)
+// Inserting the same interval several times keeps every value
+func (t *Tree[K, V]) Insert(start, end K, value V) error {
+	if !(start < end) {
+		return ErrInvalidInterval
+	}
+
+	t.mu.Lock() // Lock for writing
+	defer t.mu.Unlock()
+
+	t.root = insert(t.root, Interval[K]{Start: start, End: end}, value)
+	t.size++
+
+	// Track metrics if enabled
+	if t.config.MetricsEnabled {
+		t.insertCounter.Inc(1)
+	}
+	return nil
+}
+
+// Delete removes the oldest value stored for the interval [start, end)
+// It reports whether such a value existed
+func (t *Tree[K, V]) Delete(start, end K) bool {
+	return t.DeleteFunc(start, end, func(V) bool { return true })
+}
+
+// DeleteFunc removes the oldest value stored for the interval [start, end) for which match returns true
+// It reports whether such a value existed
+func (t *Tree[K, V]) DeleteFunc(start, end K, match func(V) bool) bool {
+	t.mu.Lock() // Lock for writing
+	defer t.mu.Unlock()
+
+	var deleted bool
+	t.root = remove(t.root, Interval[K]{Start: start, End: end}, match, &deleted)
+	if !deleted {
+		return false
+	}
+	t.size--
+
+	// Track metrics if enabled
+	if t.config.MetricsEnabled {
+		t.deleteCounter.Inc(1)
+	}
+	return true
+}
+
+// Len returns the number of stored values
+func (t *Tree[K, V]) Len() int {
+	t.mu.RLock() // Lock for reading
+	defer t.mu.RUnlock()
+
+	return t.size
+}
+
+// Overlapping returns an iterator over the values whose interval overlaps [start, end)
+// Results are ordered by interval start. The tree is read-locked while the
+// iteration runs, so the loop body must not modify the tree.
+func (t *Tree[K, V]) Overlapping(start, end K) iter.Seq2[Interval[K], V] {
+	q := Interval[K]{Start: start, End: end}
+	return func(yield func(Interval[K], V) bool) {
+		t.mu.RLock() // Lock for reading
+		defer t.mu.RUnlock()
+
+		// Track metrics if enabled
+		if t.config.MetricsEnabled {
+			t.queryCounter.Inc(1)
+		}
+		if start < end {
+			overlapping(t.root, q, yield)
+		}
+	}
+}
+
+// Containing returns an iterator over the values whose interval contains point
+// Results are ordered by interval start. The tree is read-locked while the
+// iteration runs, so the loop body must not modify the tree.
+func (t *Tree[K, V]) Containing(point K) iter.Seq2[Interval[K], V] {
+	return func(yield func(Interval[K], V) bool) {
+		t.mu.RLock() // Lock for reading
+		defer t.mu.RUnlock()
+
+		// Track metrics if enabled
+		if t.config.MetricsEnabled {
+			t.queryCounter.Inc(1)
+		}
+		containing(t.root, point, yield)
+	}
+}
+
+// All returns an iterator over every stored value ordered by interval
+// The tree is read-locked while the iteration runs, so the loop body must not modify the tree.
+func (t *Tree[K, V]) All() iter.Seq2[Interval[K], V] {
+	return func(yield func(Interval[K], V) bool) {
+		t.mu.RLock() // Lock for reading
+		defer t.mu.RUnlock()
+
+		all(t.root, yield)
+	}
+}
+
+func compareIntervals[K cmp.Ordered](a, b Interval[K]) int {
+	if c := cmp.Compare(a.Start, b.Start); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.End, b.End)
+}
+
+func insert[K cmp.Ordered, V any](n *node[K, V], iv Interval[K], value V) *node[K, V] {
+	if n == nil {
+		return &node[K, V]{interval: iv, values: []V{value}, maxEnd: iv.End, height: 1}
+	}
+	switch c := compareIntervals(iv, n.interval); {
+	case c < 0:
+		n.left = insert(n.left, iv, value)
+	case c > 0:
+		n.right = insert(n.right, iv, value)
+	default:
+		n.values = append(n.values, value)
+		return n
+	}
+	return rebalance(n)
+}
+
+func remove[K cmp.Ordered, V any](n *node[K, V], iv Interval[K], match func(V) bool, deleted *bool) *node[K, V] {
+	if n == nil {
+		return nil
+	}
+	switch c := compareIntervals(iv, n.interval); {
+	case c < 0:
+		n.left = remove(n.left, iv, match, deleted)
+	case c > 0:
+		n.right = remove(n.right, iv, match, deleted)
+	default:
+		for i, v := range n.values {
+			if match(v) {
+				n.values = append(n.values[:i], n.values[i+1:]...)
+				*deleted = true
+				break
+			}
+		}
+		if len(n.values) > 0 {
+			return n
+		}
+
+		// The node is now empty: unlink it
+		if n.left == nil {
+			return n.right
+		}
+		if n.right == nil {
+			return n.left
+		}
+		successor := n.right
+		for successor.left != nil {
+			successor = successor.left
+		}
+		n.interval = successor.interval
+		n.values = successor.values
+		n.right = removeMin(n.right)
+	}
+	return rebalance(n)
+}
+
+func removeMin[K cmp.Ordered, V any](n *node[K, V]) *node[K, V] {
+	if n.left == nil {
+		return n.right
+	}
+	n.left = removeMin(n.left)
+	return rebalance(n)
+}
+
+func overlapping[K cmp.Ordered, V any](n *node[K, V], q Interval[K], yield func(Interval[K], V) bool) bool {
+	// No interval of this subtree ends after the query starts
+	if n == nil || !(q.Start < n.maxEnd) {
+		return true
+	}
+	if !overlapping(n.left, q, yield) {
+		return false
+	}
+	if n.interval.Overlaps(q) {
+		for _, v := range n.values {
+			if !yield(n.interval, v) {
+				return false
+			}
+		}
+	}
+	// Intervals on the right start at or after this one
+	if n.interval.Start < q.End {
+		return overlapping(n.right, q, yield)
+	}
+	return true
+}
+
+func containing[K cmp.Ordered, V any](n *node[K, V], point K, yield func(Interval[K], V) bool) bool {
+	if n == nil || !(point < n.maxEnd) {
+		return true
+	}
+	if !containing(n.left, point, yield) {
+		return false
+	}
+	if n.interval.Contains(point) {
+		for _, v := range n.values {
+			if !yield(n.interval, v) {
+				return false
+			}
+		}
+	}
+	if n.interval.Start <= point {
+		return containing(n.right, point, yield)
+	}
+	return true
+}
+
+func all[K cmp.Ordered, V any](n *node[K, V], yield func(Interval[K], V) bool) bool {
+	if n == nil {
+		return true
+	}
+	if !all(n.left, yield) {
+		return false
+	}
+	for _, v := range n.values {
+		if !yield(n.interval, v) {
+			return false
+		}
+	}
+	return all(n.right, yield)
+}
+
+func height[K cmp.Ordered, V any](n *node[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+// update recomputes the height and maxEnd of n from its children
+func update[K cmp.Ordered, V any](n *node[K, V]) {
+	n.height = 1 + max(height(n.left), height(n.right))
+	n.maxEnd = n.interval.End
+	if n.left != nil {
+		n.maxEnd = max(n.maxEnd, n.left.maxEnd)
+	}
+	if n.right != nil {
+		n.maxEnd = max(n.maxEnd, n.right.maxEnd)
+	}
+}
+
+func rotateLeft[K cmp.Ordered, V any](n *node[K, V]) *node[K, V] {
+	r := n.right
+	n.right = r.left
+	r.left = n
+	update(n)
+	update(r)
+	return r
+}
+
+func rotateRight[K cmp.Ordered, V any](n *node[K, V]) *node[K, V] {
+	l := n.left
+	n.left = l.right
+	l.right = n
+	update(n)
+	update(l)
+	return l
+}
+
+// rebalance restores the AVL invariant at n after one of its subtrees changed
+func rebalance[K cmp.Ordered, V any](n *node[K, V]) *node[K, V] {
+	update(n)
+	balance := height(n.left) - height(n.right)
+	if balance > 1 {
+		if height(n.left.left) < height(n.left.right) {
+			n.left = rotateLeft(n.left)
+		}
+		return rotateRight(n)
+	}
+	if balance < -1 {
+		if height(n.right.right) < height(n.right.left) {
+			n.right = rotateRight(n.right)
+		}
+		return rotateLeft(n)
+	}
+	return n
+}
@@ -0,0 +1,148 @@
+package interval
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type reservation struct {
+	start, end int
+	id         string
+}
+
+func collect(seq func(func(Interval[int], string) bool)) []string {
+	var ids []string
+	seq(func(_ Interval[int], id string) bool {
+		ids = append(ids, id)
+		return true
+	})
+	return ids
+}
+
+func TestOverlapping(t *testing.T) {
+	tree := NewTree[int, string](TreeConfig{MetricsEnabled: false})
+	assert.NoError(t, tree.Insert(10, 20, "a"))
+	assert.NoError(t, tree.Insert(15, 25, "b"))
+	assert.NoError(t, tree.Insert(30, 40, "c"))
+	assert.NoError(t, tree.Insert(0, 5, "d"))
+
+	assert.Equal(t, []string{"a", "b"}, collect(tree.Overlapping(12, 18)))
+	assert.Equal(t, []string{"b", "c"}, collect(tree.Overlapping(20, 31)))
+	assert.Empty(t, collect(tree.Overlapping(25, 30))) // Half-open bounds do not touch
+	assert.Empty(t, collect(tree.Overlapping(5, 5)))
+}
+
+func TestContaining(t *testing.T) {
+	tree := NewTree[int, string](TreeConfig{})
+	tree.Insert(10, 20, "a")
+	tree.Insert(15, 25, "b")
+	tree.Insert(20, 30, "c")
+
+	assert.Equal(t, []string{"a", "b"}, collect(tree.Containing(15)))
+	assert.Equal(t, []string{"b", "c"}, collect(tree.Containing(20)))
+	assert.Empty(t, collect(tree.Containing(30)))
+	assert.Empty(t, collect(tree.Containing(9)))
+}
+
+func TestDuplicates(t *testing.T) {
+	tree := NewTree[int, string](TreeConfig{})
+	tree.Insert(1, 5, "first")
+	tree.Insert(1, 5, "second")
+	tree.Insert(1, 5, "third")
+	assert.Equal(t, 3, tree.Len())
+	assert.Equal(t, []string{"first", "second", "third"}, collect(tree.Containing(2)))
+
+	assert.True(t, tree.DeleteFunc(1, 5, func(id string) bool { return id == "second" }))
+	assert.Equal(t, []string{"first", "third"}, collect(tree.Containing(2)))
+
+	assert.True(t, tree.Delete(1, 5))
+	assert.Equal(t, []string{"third"}, collect(tree.Containing(2)))
+
+	assert.True(t, tree.Delete(1, 5))
+	assert.False(t, tree.Delete(1, 5))
+	assert.Equal(t, 0, tree.Len())
+}
+
+func TestInvalidInterval(t *testing.T) {
+	tree := NewTree[int, string](TreeConfig{})
+	assert.Equal(t, ErrInvalidInterval, tree.Insert(5, 5, "empty"))
+	assert.Equal(t, ErrInvalidInterval, tree.Insert(6, 5, "reversed"))
+}
+
+func TestIteratorStops(t *testing.T) {
+	tree := NewTree[int, string](TreeConfig{})
+	tree.Insert(0, 10, "a")
+	tree.Insert(1, 10, "b")
+	tree.Insert(2, 10, "c")
+
+	var seen []string
+	for _, id := range tree.Containing(5) {
+		seen = append(seen, id)
+		if len(seen) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []string{"a", "b"}, seen)
+}
+
+func TestRandomized(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	tree := NewTree[int, string](TreeConfig{})
+	var reservations []reservation
+
+	for i := 0; i < 2000; i++ {
+		if len(reservations) > 0 && rng.IntN(3) == 0 {
+			// Delete a random reservation
+			k := rng.IntN(len(reservations))
+			r := reservations[k]
+			assert.True(t, tree.DeleteFunc(r.start, r.end, func(id string) bool { return id == r.id }))
+			reservations = slices.Delete(reservations, k, k+1)
+		} else {
+			start := rng.IntN(1000)
+			r := reservation{start: start, end: start + 1 + rng.IntN(50), id: fmt.Sprintf("r%d", i)}
+			assert.NoError(t, tree.Insert(r.start, r.end, r.id))
+			reservations = append(reservations, r)
+		}
+
+		// Compare a random query with a linear scan
+		qs := rng.IntN(1000)
+		qe := qs + 1 + rng.IntN(30)
+		var expected []string
+		for _, r := range reservations {
+			if r.start < qe && qs < r.end {
+				expected = append(expected, r.id)
+			}
+		}
+		got := collect(tree.Overlapping(qs, qe))
+		slices.Sort(expected)
+		slices.Sort(got)
+		assert.Equal(t, expected, got)
+	}
+	assert.Equal(t, len(reservations), tree.Len())
+}
+
+func BenchmarkInsert(b *testing.B) {
+	tree := NewTree[int, int](TreeConfig{})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Insert(i%100000, i%100000+10, i)
+	}
+}
+
+func BenchmarkOverlapping(b *testing.B) {
+	tree := NewTree[int, int](TreeConfig{})
+	for i := 0; i < 100000; i++ {
+		tree.Insert(i, i+10, i)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for range tree.Overlapping(i%100000, i%100000+20) {
+		}
+	}
+}
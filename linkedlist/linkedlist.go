@@ -1,6 +1,7 @@
 package linkedlist
 
 import (
+	"errors"
 	"github.com/rcrowley/go-metrics"
 	"sync"
 	"time"
@@ -129,3 +130,105 @@ func (list *LinkedList) Find(data interface{}) *Node {
 	return nil
 }
 
+
+// Length returns the number of elements in the list
+func (list *LinkedList) Length() int {
+	list.mu.Lock()
+	defer list.mu.Unlock()
+
+	return list.length
+}
+
+// Values returns the elements of the list in order
+func (list *LinkedList) Values() []interface{} {
+	list.mu.Lock()
+	defer list.mu.Unlock()
+
+	values := make([]interface{}, 0, list.length)
+	for current := list.head; current != nil; current = current.next {
+		values = append(values, current.data)
+	}
+	return values
+}
+
+// IndexOf returns the position of the first occurrence of an element, or -1 if it is absent
+func (list *LinkedList) IndexOf(data interface{}) int {
+	list.mu.Lock()
+	defer list.mu.Unlock()
+
+	index := 0
+	for current := list.head; current != nil; current = current.next {
+		if current.data == data {
+			return index
+		}
+		index++
+	}
+	return -1
+}
+
+// InsertAt inserts an element at the specified position, shifting the following ones
+func (list *LinkedList) InsertAt(index int, data interface{}) error {
+	list.mu.Lock()
+	defer list.mu.Unlock()
+
+	if index < 0 || index > list.length {
+		return errors.New("index out of bounds")
+	}
+
+	newNode := &Node{data: data}
+	if index == 0 {
+		newNode.next = list.head
+		list.head = newNode
+	} else {
+		current := list.head
+		for i := 1; i < index; i++ {
+			current = current.next
+		}
+		newNode.next = current.next
+		current.next = newNode
+	}
+
+	list.length++
+	return nil
+}
+
+// RemoveAt removes the element at the specified position and returns it
+func (list *LinkedList) RemoveAt(index int) (interface{}, error) {
+	list.mu.Lock()
+	defer list.mu.Unlock()
+
+	if index < 0 || index >= list.length {
+		return nil, errors.New("index out of bounds")
+	}
+
+	var removed *Node
+	if index == 0 {
+		removed = list.head
+		list.head = removed.next
+	} else {
+		current := list.head
+		for i := 1; i < index; i++ {
+			current = current.next
+		}
+		removed = current.next
+		current.next = removed.next
+	}
+
+	list.length--
+	return removed.data, nil
+}
+
+// Replace swaps the elements of the list for values in a single step
+// Readers see either the old or the new elements, never a mix of both.
+func (list *LinkedList) Replace(values []interface{}) {
+	var head *Node
+	for i := len(values) - 1; i >= 0; i-- {
+		head = &Node{data: values[i], next: head}
+	}
+
+	list.mu.Lock()
+	defer list.mu.Unlock()
+
+	list.head = head
+	list.length = len(values)
+}
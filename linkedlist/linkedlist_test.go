@@ -97,6 +97,83 @@ func TestFind(t *testing.T) {
 	}
 }
 
+// Test Length and Values reflect the elements of the list
+func TestValues(t *testing.T) {
+	list := NewLinkedList()
+
+	list.Add(10)
+	list.Add("twenty")
+	list.Add(30)
+	list.Remove(30)
+
+	if list.Length() != 2 {
+		t.Fatalf("Expected linked list length to be 2, got %d", list.Length())
+	}
+
+	values := list.Values()
+	if len(values) != 2 || values[0] != 10 || values[1] != "twenty" {
+		t.Fatalf("Expected values [10 twenty], got %v", values)
+	}
+}
+
+func TestInsertRemoveAt(t *testing.T) {
+	list := NewLinkedList()
+
+	list.Add("b")
+	list.Add("d")
+	if err := list.InsertAt(0, "a"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := list.InsertAt(2, "c"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := list.InsertAt(4, "e"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := list.InsertAt(6, "x"); err == nil {
+		t.Fatal("Expected an error when inserting out of bounds")
+	}
+
+	values := list.Values()
+	if len(values) != 5 || values[0] != "a" || values[2] != "c" || values[4] != "e" {
+		t.Fatalf("Expected values [a b c d e], got %v", values)
+	}
+	if list.IndexOf("c") != 2 || list.IndexOf("z") != -1 {
+		t.Fatalf("Unexpected IndexOf results %d and %d", list.IndexOf("c"), list.IndexOf("z"))
+	}
+
+	removed, err := list.RemoveAt(2)
+	if err != nil || removed != "c" {
+		t.Fatalf("Expected to remove c, got %v (%v)", removed, err)
+	}
+	removed, err = list.RemoveAt(0)
+	if err != nil || removed != "a" {
+		t.Fatalf("Expected to remove a, got %v (%v)", removed, err)
+	}
+	if _, err := list.RemoveAt(3); err == nil {
+		t.Fatal("Expected an error when removing out of bounds")
+	}
+	if list.Length() != 3 {
+		t.Fatalf("Expected linked list length to be 3, got %d", list.Length())
+	}
+}
+
+func TestReplace(t *testing.T) {
+	list := NewLinkedList()
+	list.Add("x")
+
+	list.Replace([]interface{}{"a", "b", "c"})
+	values := list.Values()
+	if len(values) != 3 || values[0] != "a" || values[2] != "c" || list.Length() != 3 {
+		t.Fatalf("Expected values [a b c], got %v", values)
+	}
+
+	list.Replace(nil)
+	if list.Length() != 0 || len(list.Values()) != 0 {
+		t.Fatalf("Expected an empty list, got %v", list.Values())
+	}
+}
+
 // Test metrics are updated correctly
 func TestMetrics(t *testing.T) {
 	// Create a new linked list and add some elements
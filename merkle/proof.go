@@ -0,0 +1,165 @@
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"hash"
+)
+
+// InclusionProof returns the audit path proving that the leaf at index is part of the tree of the given size
+// The proof lists the sibling subtree hashes from the leaf up to the root, as
+// PATH in RFC 6962.
+func (t *Tree) InclusionProof(index, size int) ([][]byte, error) {
+	t.mu.RLock() // Lock for reading
+	defer t.mu.RUnlock()
+
+	if size <= 0 || size > t.size() {
+		return nil, ErrInvalidSize
+	}
+	if index < 0 || index >= size {
+		return nil, ErrIndexOutOfBounds
+	}
+
+	// Track metrics if enabled
+	if t.config.MetricsEnabled {
+		t.proofCounter.Inc(1)
+	}
+	return t.path(index, 0, size), nil
+}
+
+// path computes PATH(index, D[from:to]) with index relative to from
+func (t *Tree) path(index, from, to int) [][]byte {
+	if to-from <= 1 {
+		return nil
+	}
+	k := split(to - from)
+	if index < k {
+		return append(t.path(index, from, from+k), t.subtree(from+k, to))
+	}
+	return append(t.path(index-k, from+k, to), t.subtree(from, from+k))
+}
+
+// ConsistencyProof returns the proof that the tree of size first is a prefix of the tree of size second
+// It is PROOF in RFC 6962.
+func (t *Tree) ConsistencyProof(first, second int) ([][]byte, error) {
+	t.mu.RLock() // Lock for reading
+	defer t.mu.RUnlock()
+
+	if first <= 0 || first > second || second > t.size() {
+		return nil, ErrInvalidSize
+	}
+
+	// Track metrics if enabled
+	if t.config.MetricsEnabled {
+		t.proofCounter.Inc(1)
+	}
+	return t.subproof(first, 0, second, true), nil
+}
+
+// subproof computes SUBPROOF(m, D[from:to], complete)
+// complete reports whether the subtree of the first m leaves is the whole
+// first tree, whose root the verifier already knows.
+func (t *Tree) subproof(m, from, to int, complete bool) [][]byte {
+	n := to - from
+	if m == n {
+		if complete {
+			return nil
+		}
+		return [][]byte{t.subtree(from, to)}
+	}
+	k := split(n)
+	if m <= k {
+		return append(t.subproof(m, from, from+k, complete), t.subtree(from+k, to))
+	}
+	return append(t.subproof(m-k, from+k, to, false), t.subtree(from, from+k))
+}
+
+// VerifyInclusion checks that data is the leaf at index of the tree of the given size with the given root
+// newHash must be the hash function of the tree, nil means SHA-256.
+func VerifyInclusion(newHash func() hash.Hash, data []byte, index, size int, proof [][]byte, root []byte) error {
+	if newHash == nil {
+		newHash = sha256.New
+	}
+	if index < 0 || index >= size {
+		return ErrIndexOutOfBounds
+	}
+
+	// Walk up from the leaf: fn is the index of the current node and sn the
+	// index of the last node at its level, as described in RFC 9162
+	fn, sn := index, size-1
+	r := leafHash(newHash, data)
+	for _, p := range proof {
+		if sn == 0 {
+			return ErrInvalidProof
+		}
+		if fn%2 == 1 || fn == sn {
+			r = nodeHash(newHash, p, r)
+			// The last node of a level without sibling is promoted unchanged
+			for fn%2 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = nodeHash(newHash, r, p)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	if sn != 0 || !bytes.Equal(r, root) {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// VerifyConsistency checks that the tree of size first with root firstRoot is a prefix of the tree of size second with root secondRoot
+// newHash must be the hash function of the tree, nil means SHA-256.
+func VerifyConsistency(newHash func() hash.Hash, first, second int, proof [][]byte, firstRoot, secondRoot []byte) error {
+	if newHash == nil {
+		newHash = sha256.New
+	}
+	if first <= 0 || first > second {
+		return ErrInvalidSize
+	}
+	if first == second {
+		if len(proof) != 0 || !bytes.Equal(firstRoot, secondRoot) {
+			return ErrInvalidProof
+		}
+		return nil
+	}
+	// When the first tree is a perfect subtree of the second its root starts the path
+	if first&(first-1) == 0 {
+		proof = append([][]byte{firstRoot}, proof...)
+	}
+	if len(proof) == 0 {
+		return ErrInvalidProof
+	}
+
+	// Rebuild both roots along the path of the last leaf of the first tree, as described in RFC 9162
+	fn, sn := first-1, second-1
+	for fn%2 == 1 {
+		fn >>= 1
+		sn >>= 1
+	}
+	fr, sr := proof[0], proof[0]
+	for _, c := range proof[1:] {
+		if sn == 0 {
+			return ErrInvalidProof
+		}
+		if fn%2 == 1 || fn == sn {
+			fr = nodeHash(newHash, c, fr)
+			sr = nodeHash(newHash, c, sr)
+			for fn%2 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			sr = nodeHash(newHash, sr, c)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	if sn != 0 || !bytes.Equal(fr, firstRoot) || !bytes.Equal(sr, secondRoot) {
+		return ErrInvalidProof
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package merkle
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInclusionProofVectors(t *testing.T) {
+	tree := rfcTree(t)
+	proof, err := tree.InclusionProof(0, 8)
+	assert.NoError(t, err)
+	expected := []string{
+		"96a296d224f285c67bee93c30f8a309157f0daa35dc5b87e410b78630a09cfc7",
+		"5f083f0a1a33ca076a95279832580db3e0ef4584bdff1f54c8a360f50de3031e",
+		"6b47aaf29ee3c2af9af889bc1fb9254dabd31177f16232dd6aab035ca39bf6e4",
+	}
+	var actual []string
+	for _, p := range proof {
+		actual = append(actual, hex.EncodeToString(p))
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func TestInclusionProofs(t *testing.T) {
+	tree := New(nil, Config{})
+	for i := 0; i < 40; i++ {
+		tree.Append([]byte(fmt.Sprintf("leaf-%d", i)))
+	}
+
+	for size := 1; size <= 40; size++ {
+		root, _ := tree.RootAt(size)
+		for index := 0; index < size; index++ {
+			proof, err := tree.InclusionProof(index, size)
+			assert.NoError(t, err)
+			data := []byte(fmt.Sprintf("leaf-%d", index))
+			assert.NoError(t, VerifyInclusion(nil, data, index, size, proof, root))
+
+			// Tampering with the data, the position or the proof is detected
+			assert.Equal(t, ErrInvalidProof, VerifyInclusion(nil, []byte("forged"), index, size, proof, root))
+			if size > 1 {
+				assert.Error(t, VerifyInclusion(nil, data, (index+1)%size, size, proof, root))
+				forged := append([][]byte(nil), proof...)
+				forged[0] = bytes.Repeat([]byte{0}, 32)
+				assert.Equal(t, ErrInvalidProof, VerifyInclusion(nil, data, index, size, forged, root))
+				assert.Equal(t, ErrInvalidProof, VerifyInclusion(nil, data, index, size, proof[:len(proof)-1], root))
+			}
+		}
+	}
+
+	_, err := tree.InclusionProof(5, 5)
+	assert.Equal(t, ErrIndexOutOfBounds, err)
+	_, err = tree.InclusionProof(0, 41)
+	assert.Equal(t, ErrInvalidSize, err)
+}
+
+func TestConsistencyProofs(t *testing.T) {
+	tree := New(nil, Config{})
+	for i := 0; i < 40; i++ {
+		tree.Append([]byte(fmt.Sprintf("leaf-%d", i)))
+	}
+
+	for second := 1; second <= 40; second++ {
+		secondRoot, _ := tree.RootAt(second)
+		for first := 1; first <= second; first++ {
+			firstRoot, _ := tree.RootAt(first)
+			proof, err := tree.ConsistencyProof(first, second)
+			assert.NoError(t, err)
+			assert.NoError(t, VerifyConsistency(nil, first, second, proof, firstRoot, secondRoot), "%d %d", first, second)
+
+			// A tree whose history was rewritten fails the check
+			forgedRoot := bytes.Clone(firstRoot)
+			forgedRoot[0] ^= 1
+			assert.Equal(t, ErrInvalidProof, VerifyConsistency(nil, first, second, proof, forgedRoot, secondRoot))
+			if len(proof) > 0 {
+				assert.Equal(t, ErrInvalidProof, VerifyConsistency(nil, first, second, proof[1:], firstRoot, secondRoot))
+			}
+		}
+	}
+
+	_, err := tree.ConsistencyProof(0, 10)
+	assert.Equal(t, ErrInvalidSize, err)
+	_, err = tree.ConsistencyProof(11, 10)
+	assert.Equal(t, ErrInvalidSize, err)
+}
+
+func TestConsistencyProofVectors(t *testing.T) {
+	tree := rfcTree(t)
+	// Size 4 is a perfect subtree of size 8, the proof holds the missing right half only
+	proof, err := tree.ConsistencyProof(4, 8)
+	assert.NoError(t, err)
+	assert.Len(t, proof, 1)
+	assert.Equal(t, "6b47aaf29ee3c2af9af889bc1fb9254dabd31177f16232dd6aab035ca39bf6e4", hex.EncodeToString(proof[0]))
+
+	proof, err = tree.ConsistencyProof(8, 8)
+	assert.NoError(t, err)
+	assert.Empty(t, proof)
+}
@@ -0,0 +1,203 @@
+package merkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"hash"
+	"math/bits"
+	"sync"
+
+	"github.com/rcrowley/go-metrics"
+	"github.com/vzahanych/data-structures/array"
+)
+
+// Errors returned by the tree and the proof verification
+var (
+	ErrIndexOutOfBounds = errors.New("index out of bounds")
+	ErrInvalidSize      = errors.New("invalid tree size")
+	ErrInvalidProof     = errors.New("invalid proof")
+)
+
+// Domain separation prefixes of RFC 6962, so that a leaf can never be passed off as a node
+const (
+	leafPrefix = 0x00
+	nodePrefix = 0x01
+)
+
+type Config struct {
+	// Hash creates the hash function of the tree (default SHA-256)
+	Hash           func() hash.Hash
+	MetricsEnabled bool
+}
+
+// Tree is a Merkle tree over an array of leaves, hashed as specified by RFC 6962
+// The tree keeps the hash of every perfect subtree: levels[k][i] covers the
+// leaves [i<<k, (i+1)<<k). Append adds the leaf hash and the subtrees it
+// completes in O(log n), and the root of any past size is derived from them,
+// which is what inclusion and consistency proofs need.
+type Tree struct {
+	leaves        *array.Array[[]byte]
+	levels        [][][]byte
+	mu            sync.RWMutex
+	config        Config
+	appendCounter metrics.Counter
+	proofCounter  metrics.Counter
+}
+
+// New creates a tree over the leaves already in the array
+// The array must only be appended to through the tree afterwards. A nil
+// array starts an empty tree. The leaves in the array are replaced by copies,
+// so later changes to the caller's slices do not alter the tree.
+// The config parameter sets the hash function and enables metrics collection
+func New(leaves *array.Array[[]byte], config Config) *Tree {
+	if config.Hash == nil {
+		config.Hash = sha256.New
+	}
+	if leaves == nil {
+		leaves = array.NewArray[[]byte](0, array.ArrayConfig{})
+	}
+	t := &Tree{leaves: leaves, config: config}
+	values := leaves.Values()
+	for i, data := range values {
+		values[i] = bytes.Clone(data)
+		t.add(values[i])
+	}
+	// The capacity is unchanged, so Replace cannot fail
+	_ = leaves.Replace(values, leaves.Capacity())
+
+	// Initialize the metrics only if enabled in the config
+	if t.config.MetricsEnabled {
+		t.appendCounter = metrics.NewCounter()
+		t.proofCounter = metrics.NewCounter()
+		metrics.DefaultRegistry.Register("merkle.append", t.appendCounter)
+		metrics.DefaultRegistry.Register("merkle.proof", t.proofCounter)
+	}
+
+	return t
+}
+
+// Append adds a leaf and returns its index, the array grows when it is full
+// The tree stores a copy of data.
+func (t *Tree) Append(data []byte) (int, error) {
+	t.mu.Lock() // Lock for writing
+	defer t.mu.Unlock()
+
+	data = bytes.Clone(data)
+	if t.leaves.Length() == t.leaves.Capacity() {
+		if err := t.leaves.Resize(max(2*t.leaves.Capacity(), 16)); err != nil {
+			return 0, err
+		}
+	}
+	if err := t.leaves.Append(data); err != nil {
+		return 0, err
+	}
+	t.add(data)
+
+	// Track metrics if enabled
+	if t.config.MetricsEnabled {
+		t.appendCounter.Inc(1)
+	}
+	return t.size() - 1, nil
+}
+
+// add hashes a new leaf and the perfect subtrees it completes
+func (t *Tree) add(data []byte) {
+	h := leafHash(t.config.Hash, data)
+	for k := 0; ; k++ {
+		if k == len(t.levels) {
+			t.levels = append(t.levels, nil)
+		}
+		t.levels[k] = append(t.levels[k], h)
+		n := len(t.levels[k])
+		if n%2 == 1 {
+			return
+		}
+		h = nodeHash(t.config.Hash, t.levels[k][n-2], h)
+	}
+}
+
+// Size returns the number of leaves
+func (t *Tree) Size() int {
+	t.mu.RLock() // Lock for reading
+	defer t.mu.RUnlock()
+
+	return t.size()
+}
+
+func (t *Tree) size() int {
+	if len(t.levels) == 0 {
+		return 0
+	}
+	return len(t.levels[0])
+}
+
+// Leaf returns a copy of the data of the leaf at index
+func (t *Tree) Leaf(index int) ([]byte, error) {
+	t.mu.RLock() // Lock for reading
+	defer t.mu.RUnlock()
+
+	data, err := t.leaves.Get(index)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.Clone(data), nil
+}
+
+// Root returns the tree head: the hash of the whole tree
+func (t *Tree) Root() []byte {
+	t.mu.RLock() // Lock for reading
+	defer t.mu.RUnlock()
+
+	return t.subtree(0, t.size())
+}
+
+// RootAt returns the root the tree had when it held size leaves
+func (t *Tree) RootAt(size int) ([]byte, error) {
+	t.mu.RLock() // Lock for reading
+	defer t.mu.RUnlock()
+
+	if size < 0 || size > t.size() {
+		return nil, ErrInvalidSize
+	}
+	return t.subtree(0, size), nil
+}
+
+// subtree returns the RFC 6962 hash of the leaves [from, to)
+// The left part is the largest power of two smaller than the range, so for
+// the ranges used by proofs it is always a stored perfect subtree.
+func (t *Tree) subtree(from, to int) []byte {
+	n := to - from
+	if n == 0 {
+		return emptyHash(t.config.Hash)
+	}
+	if k := bits.TrailingZeros(uint(n)); n == 1<<k && from%n == 0 {
+		return t.levels[k][from>>k]
+	}
+	k := split(n)
+	return nodeHash(t.config.Hash, t.subtree(from, from+k), t.subtree(from+k, to))
+}
+
+// split returns the largest power of two smaller than n, n must be at least 2
+func split(n int) int {
+	return 1 << (bits.Len(uint(n-1)) - 1)
+}
+
+func emptyHash(newHash func() hash.Hash) []byte {
+	return newHash().Sum(nil)
+}
+
+func leafHash(newHash func() hash.Hash, data []byte) []byte {
+	h := newHash()
+	h.Write([]byte{leafPrefix})
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func nodeHash(newHash func() hash.Hash, left, right []byte) []byte {
+	h := newHash()
+	h.Write([]byte{nodePrefix})
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
@@ -0,0 +1,157 @@
+package merkle
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vzahanych/data-structures/array"
+)
+
+// Leaves of the RFC 6962 test vectors used by Certificate Transparency implementations
+var rfcLeaves = []string{
+	"",
+	"00",
+	"10",
+	"2021",
+	"3031",
+	"40414243",
+	"5051525354555657",
+	"606162636465666768696a6b6c6d6e6f",
+}
+
+func rfcTree(t *testing.T) *Tree {
+	tree := New(nil, Config{})
+	for _, leaf := range rfcLeaves {
+		data, err := hex.DecodeString(leaf)
+		assert.NoError(t, err)
+		tree.Append(data)
+	}
+	return tree
+}
+
+func TestRoot(t *testing.T) {
+	empty := New(nil, Config{})
+	assert.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", hex.EncodeToString(empty.Root()))
+
+	tree := rfcTree(t)
+	assert.Equal(t, 8, tree.Size())
+	assert.Equal(t, "5dc9da79a70659a9ad559cb701ded9a2ab9d823aad2f4960cfe370eff4604328", hex.EncodeToString(tree.Root()))
+
+	root, err := tree.RootAt(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "6e340b9cffb37a989ca544e6bb780a2c78901d3fb33738768511a30617afa01d", hex.EncodeToString(root))
+	_, err = tree.RootAt(9)
+	assert.Equal(t, ErrInvalidSize, err)
+}
+
+// naiveRoot hashes leaves recursively straight from the RFC 6962 definition
+func naiveRoot(leaves [][]byte) []byte {
+	switch len(leaves) {
+	case 0:
+		return emptyHash(sha256.New)
+	case 1:
+		return leafHash(sha256.New, leaves[0])
+	}
+	k := split(len(leaves))
+	return nodeHash(sha256.New, naiveRoot(leaves[:k]), naiveRoot(leaves[k:]))
+}
+
+func TestIncrementalAppend(t *testing.T) {
+	tree := New(nil, Config{})
+	var leaves [][]byte
+	for i := 0; i < 100; i++ {
+		leaf := []byte(fmt.Sprintf("leaf-%d", i))
+		index, err := tree.Append(leaf)
+		assert.NoError(t, err)
+		assert.Equal(t, i, index)
+		leaves = append(leaves, leaf)
+		assert.Equal(t, naiveRoot(leaves), tree.Root())
+	}
+
+	// Past roots stay available
+	for size := 0; size <= 100; size += 7 {
+		root, err := tree.RootAt(size)
+		assert.NoError(t, err)
+		assert.Equal(t, naiveRoot(leaves[:size]), root)
+	}
+
+	leaf, err := tree.Leaf(42)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("leaf-42"), leaf)
+}
+
+func TestNewOverArray(t *testing.T) {
+	leaves := array.NewArray[[]byte](3, array.ArrayConfig{})
+	leaves.Append([]byte("a"))
+	leaves.Append([]byte("b"))
+	leaves.Append([]byte("c"))
+
+	tree := New(leaves, Config{})
+	assert.Equal(t, naiveRoot([][]byte{[]byte("a"), []byte("b"), []byte("c")}), tree.Root())
+
+	// The full array grows
+	tree.Append([]byte("d"))
+	assert.Equal(t, 4, leaves.Length())
+	assert.Equal(t, naiveRoot([][]byte{[]byte("a"), []byte("b"), []byte("c"), []byte("d")}), tree.Root())
+}
+
+func TestLeavesCopied(t *testing.T) {
+	first := []byte("a")
+	leaves := array.NewArray[[]byte](1, array.ArrayConfig{})
+	leaves.Append(first)
+	tree := New(leaves, Config{})
+
+	second := []byte("b")
+	tree.Append(second)
+	root := tree.Root()
+
+	// Changing the slices of the caller leaves the stored data untouched
+	first[0] = 'x'
+	second[0] = 'y'
+	leaf, err := tree.Leaf(1)
+	assert.NoError(t, err)
+	leaf[0] = 'z'
+
+	for i, expected := range []string{"a", "b"} {
+		leaf, err := tree.Leaf(i)
+		assert.NoError(t, err)
+		assert.Equal(t, []byte(expected), leaf)
+	}
+	assert.Equal(t, root, tree.Root())
+	assert.Equal(t, naiveRoot([][]byte{[]byte("a"), []byte("b")}), root)
+}
+
+func TestPluggableHash(t *testing.T) {
+	tree := New(nil, Config{Hash: sha512.New})
+	tree.Append([]byte("a"))
+	assert.Len(t, tree.Root(), sha512.Size)
+
+	proof, err := tree.InclusionProof(0, 1)
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyInclusion(sha512.New, []byte("a"), 0, 1, proof, tree.Root()))
+	assert.Equal(t, ErrInvalidProof, VerifyInclusion(nil, []byte("a"), 0, 1, proof, tree.Root()))
+}
+
+func TestMetrics(t *testing.T) {
+	tree := New(nil, Config{MetricsEnabled: true})
+	tree.Append([]byte("a"))
+	tree.Append([]byte("b"))
+	tree.InclusionProof(0, 2)
+	tree.ConsistencyProof(1, 2)
+	assert.Equal(t, int64(2), tree.appendCounter.Count())
+	assert.Equal(t, int64(2), tree.proofCounter.Count())
+}
+
+func BenchmarkAppend(b *testing.B) {
+	tree := New(nil, Config{})
+	leaf := []byte("benchmark leaf")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Append(leaf)
+	}
+}
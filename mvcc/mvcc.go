@@ -0,0 +1,340 @@
+package mvcc
+
+import (
+	"cmp"
+	"errors"
+	"iter"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+	"github.com/vzahanych/data-structures/skiplist"
+)
+
+// Errors returned when opening a read transaction
+var (
+	ErrVersionCollected = errors.New("timestamp older than the garbage collected versions")
+	ErrFutureTimestamp  = errors.New("timestamp not committed yet")
+)
+
+type StoreConfig struct {
+	MetricsEnabled bool
+}
+
+type version[V any] struct {
+	ts      uint64
+	value   V
+	deleted bool
+}
+
+// chain holds the versions of a key in increasing timestamp order
+type chain[V any] struct {
+	versions []version[V]
+}
+
+// visible returns the newest version with a timestamp not after ts
+func (c *chain[V]) visible(ts uint64) (version[V], bool) {
+	i := sort.Search(len(c.versions), func(i int) bool { return c.versions[i].ts > ts })
+	if i == 0 {
+		return version[V]{}, false
+	}
+	return c.versions[i-1], true
+}
+
+// Store is an ordered key-value store keeping several versions of each key
+// Every commit is tagged with the next timestamp, and a read transaction sees
+// the store as of the timestamp it was opened at, however many commits follow.
+// Old versions are kept until GC finds that no open reader can see them.
+type Store[K cmp.Ordered, V any] struct {
+	mu            sync.RWMutex
+	data          *skiplist.Map[K, *chain[V]]
+	now           uint64         // Timestamp of the last commit
+	horizon       uint64         // Oldest timestamp still readable
+	readers       map[uint64]int // Number of open readers per timestamp
+	readerCount   int64
+	versionCount  int64
+	config        StoreConfig
+	commitCounter metrics.Counter
+	versionGauge  metrics.Gauge
+	readerGauge   metrics.Gauge
+	gcCounter     metrics.Counter
+	gcDuration    metrics.Timer
+}
+
+// NewStore creates an empty store at timestamp 0
+// The config parameter is used to enable or disable metrics collection
+func NewStore[K cmp.Ordered, V any](config StoreConfig) *Store[K, V] {
+	s := &Store[K, V]{
+		data:    skiplist.New[K, *chain[V]](),
+		readers: make(map[uint64]int),
+		config:  config,
+	}
+
+	// Initialize the metrics only if enabled in the config
+	if s.config.MetricsEnabled {
+		s.commitCounter = metrics.NewCounter()
+		s.versionGauge = metrics.NewGauge()
+		s.readerGauge = metrics.NewGauge()
+		s.gcCounter = metrics.NewCounter()
+		s.gcDuration = metrics.NewTimer()
+		metrics.DefaultRegistry.Register("mvcc.commit", s.commitCounter)
+		metrics.DefaultRegistry.Register("mvcc.versions", s.versionGauge)
+		metrics.DefaultRegistry.Register("mvcc.readers", s.readerGauge)
+		metrics.DefaultRegistry.Register("mvcc.gc.collected", s.gcCounter)
+		metrics.DefaultRegistry.Register("mvcc.gc.duration", s.gcDuration)
+	}
+
+	return s
+}
+
+// Batch collects writes to be committed atomically under a single timestamp
+type Batch[K cmp.Ordered, V any] struct {
+	writes []write[K, V]
+}
+
+type write[K cmp.Ordered, V any] struct {
+	key     K
+	value   V
+	deleted bool
+}
+
+// Put records that key must be set to value
+func (b *Batch[K, V]) Put(key K, value V) {
+	b.writes = append(b.writes, write[K, V]{key: key, value: value})
+}
+
+// Delete records that key must be removed
+func (b *Batch[K, V]) Delete(key K) {
+	b.writes = append(b.writes, write[K, V]{key: key, deleted: true})
+}
+
+// Commit applies the writes of b as a new version and returns its timestamp
+// When a key is written several times, the last write wins. Committing an
+// empty batch does not advance the timestamp.
+func (s *Store[K, V]) Commit(b *Batch[K, V]) uint64 {
+	s.mu.Lock() // Lock for writing
+	defer s.mu.Unlock()
+
+	if len(b.writes) == 0 {
+		return s.now
+	}
+	ts := s.now + 1
+	for _, w := range b.writes {
+		c, found := s.data.Get(w.key)
+		if !found {
+			if w.deleted {
+				continue // Nothing to hide
+			}
+			c = &chain[V]{}
+			s.data.Set(w.key, c)
+		}
+		v := version[V]{ts: ts, value: w.value, deleted: w.deleted}
+		if n := len(c.versions); n > 0 && c.versions[n-1].ts == ts {
+			c.versions[n-1] = v // Written earlier in the same batch
+			continue
+		}
+		c.versions = append(c.versions, v)
+		s.versionCount++
+	}
+	s.now = ts
+
+	// Track metrics if enabled
+	if s.config.MetricsEnabled {
+		s.commitCounter.Inc(1)
+		s.versionGauge.Update(s.versionCount)
+	}
+	return ts
+}
+
+// Put sets key to value in a new version and returns its timestamp
+func (s *Store[K, V]) Put(key K, value V) uint64 {
+	var b Batch[K, V]
+	b.Put(key, value)
+	return s.Commit(&b)
+}
+
+// Delete removes key in a new version and returns its timestamp
+func (s *Store[K, V]) Delete(key K) uint64 {
+	var b Batch[K, V]
+	b.Delete(key)
+	return s.Commit(&b)
+}
+
+// Timestamp returns the timestamp of the last commit
+func (s *Store[K, V]) Timestamp() uint64 {
+	s.mu.RLock() // Lock for reading
+	defer s.mu.RUnlock()
+
+	return s.now
+}
+
+// Versions returns the number of versions held, including deletion markers
+func (s *Store[K, V]) Versions() int {
+	s.mu.RLock() // Lock for reading
+	defer s.mu.RUnlock()
+
+	return int(s.versionCount)
+}
+
+// Begin opens a read transaction at the timestamp of the last commit
+func (s *Store[K, V]) Begin() *ReadTx[K, V] {
+	s.mu.Lock() // Lock for writing, the reader is registered
+	defer s.mu.Unlock()
+
+	return s.begin(s.now)
+}
+
+// BeginAt opens a read transaction at an earlier timestamp
+// It fails with ErrVersionCollected if GC already dropped versions needed at ts.
+func (s *Store[K, V]) BeginAt(ts uint64) (*ReadTx[K, V], error) {
+	s.mu.Lock() // Lock for writing, the reader is registered
+	defer s.mu.Unlock()
+
+	if ts < s.horizon {
+		return nil, ErrVersionCollected
+	}
+	if ts > s.now {
+		return nil, ErrFutureTimestamp
+	}
+	return s.begin(ts), nil
+}
+
+// begin registers a reader at ts, the caller must hold the write lock
+func (s *Store[K, V]) begin(ts uint64) *ReadTx[K, V] {
+	s.readers[ts]++
+	s.readerCount++
+
+	// Track metrics if enabled
+	if s.config.MetricsEnabled {
+		s.readerGauge.Update(s.readerCount)
+	}
+	return &ReadTx[K, V]{store: s, ts: ts}
+}
+
+// GC drops the versions no open reader can see and returns how many were dropped
+// Versions older than the oldest open reader, or than the last commit when
+// there is none, are dropped except for the newest of each key, which that
+// reader still sees. Keys whose newest visible version is a deletion are
+// removed entirely.
+func (s *Store[K, V]) GC() int {
+	start := time.Now()
+
+	s.mu.Lock() // Lock for writing
+	defer s.mu.Unlock()
+
+	watermark := s.now
+	for ts := range s.readers {
+		watermark = min(watermark, ts)
+	}
+
+	collected := 0
+	var emptied []K
+	for key, c := range s.data.All() {
+		i := sort.Search(len(c.versions), func(i int) bool { return c.versions[i].ts > watermark })
+		// Drop everything before the version visible at the watermark, and that version too if it is a deletion
+		drop := max(i-1, 0)
+		if i > 0 && c.versions[i-1].deleted {
+			drop = i
+		}
+		if drop == 0 {
+			continue
+		}
+		clear(c.versions[:drop])
+		c.versions = c.versions[drop:]
+		collected += drop
+		if len(c.versions) == 0 {
+			emptied = append(emptied, key)
+		}
+	}
+	for _, key := range emptied {
+		s.data.Delete(key)
+	}
+	s.horizon = watermark
+	s.versionCount -= int64(collected)
+
+	// Track metrics if enabled
+	if s.config.MetricsEnabled {
+		s.gcCounter.Inc(int64(collected))
+		s.versionGauge.Update(s.versionCount)
+		s.gcDuration.UpdateSince(start)
+	}
+	return collected
+}
+
+// ReadTx is a consistent view of the store at a fixed timestamp
+// It must be closed so that GC can reclaim the versions it pins.
+type ReadTx[K cmp.Ordered, V any] struct {
+	store  *Store[K, V]
+	ts     uint64
+	closed bool
+}
+
+// Timestamp returns the timestamp the transaction reads at
+func (tx *ReadTx[K, V]) Timestamp() uint64 {
+	return tx.ts
+}
+
+// Get returns the value of key as of the transaction's timestamp
+func (tx *ReadTx[K, V]) Get(key K) (V, bool) {
+	tx.store.mu.RLock() // Lock for reading
+	defer tx.store.mu.RUnlock()
+
+	if c, found := tx.store.data.Get(key); found {
+		if v, found := c.visible(tx.ts); found && !v.deleted {
+			return v.value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// All returns an iterator over the entries visible to the transaction, in key order
+// The store is locked for reading during iteration, so the loop body must not
+// commit to it.
+func (tx *ReadTx[K, V]) All() iter.Seq2[K, V] {
+	return tx.entries(tx.store.data.All)
+}
+
+// Range returns an iterator over the visible entries whose keys are in [from, to), in key order
+// The store is locked for reading during iteration, so the loop body must not
+// commit to it.
+func (tx *ReadTx[K, V]) Range(from, to K) iter.Seq2[K, V] {
+	return tx.entries(func() iter.Seq2[K, *chain[V]] { return tx.store.data.Range(from, to) })
+}
+
+func (tx *ReadTx[K, V]) entries(chains func() iter.Seq2[K, *chain[V]]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		tx.store.mu.RLock() // Lock for reading
+		defer tx.store.mu.RUnlock()
+
+		for key, c := range chains() {
+			if v, found := c.visible(tx.ts); found && !v.deleted {
+				if !yield(key, v.value) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Close ends the transaction, releasing the versions it pins
+func (tx *ReadTx[K, V]) Close() {
+	s := tx.store
+	s.mu.Lock() // Lock for writing
+	defer s.mu.Unlock()
+
+	if tx.closed {
+		return
+	}
+	tx.closed = true
+	if s.readers[tx.ts]--; s.readers[tx.ts] == 0 {
+		delete(s.readers, tx.ts)
+	}
+	s.readerCount--
+
+	// Track metrics if enabled
+	if s.config.MetricsEnabled {
+		s.readerGauge.Update(s.readerCount)
+	}
+}
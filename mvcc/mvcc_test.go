@@ -0,0 +1,215 @@
+package mvcc
+
+import (
+	"fmt"
+	"iter"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func collect[K comparable, V any](seq iter.Seq2[K, V]) map[K]V {
+	result := make(map[K]V)
+	for k, v := range seq {
+		result[k] = v
+	}
+	return result
+}
+
+func TestSnapshotIsolation(t *testing.T) {
+	s := NewStore[string, int](StoreConfig{})
+	assert.Equal(t, uint64(1), s.Put("a", 1))
+	assert.Equal(t, uint64(2), s.Put("b", 2))
+
+	tx := s.Begin()
+	defer tx.Close()
+	assert.Equal(t, uint64(2), tx.Timestamp())
+
+	s.Put("a", 10)
+	s.Delete("b")
+	s.Put("c", 3)
+
+	// The transaction keeps seeing the store as of its timestamp
+	value, found := tx.Get("a")
+	assert.True(t, found)
+	assert.Equal(t, 1, value)
+	_, found = tx.Get("c")
+	assert.False(t, found)
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, collect(tx.All()))
+
+	latest := s.Begin()
+	defer latest.Close()
+	assert.Equal(t, map[string]int{"a": 10, "c": 3}, collect(latest.All()))
+}
+
+func TestBatch(t *testing.T) {
+	s := NewStore[int, string](StoreConfig{})
+	var b Batch[int, string]
+	b.Put(1, "one")
+	b.Put(2, "two")
+	b.Put(1, "uno")
+	b.Delete(3)
+	assert.Equal(t, uint64(1), s.Commit(&b))
+	assert.Equal(t, 2, s.Versions())
+
+	// Empty batches do not create a version
+	assert.Equal(t, uint64(1), s.Commit(&Batch[int, string]{}))
+
+	tx := s.Begin()
+	defer tx.Close()
+	assert.Equal(t, map[int]string{1: "uno", 2: "two"}, collect(tx.All()))
+}
+
+func TestBeginAt(t *testing.T) {
+	s := NewStore[int, int](StoreConfig{})
+	for i := 1; i <= 5; i++ {
+		s.Put(0, i)
+	}
+
+	tx, err := s.BeginAt(3)
+	assert.NoError(t, err)
+	value, _ := tx.Get(0)
+	assert.Equal(t, 3, value)
+	tx.Close()
+
+	_, err = s.BeginAt(6)
+	assert.Equal(t, ErrFutureTimestamp, err)
+
+	s.GC()
+	_, err = s.BeginAt(3)
+	assert.Equal(t, ErrVersionCollected, err)
+	tx, err = s.BeginAt(5)
+	assert.NoError(t, err)
+	tx.Close()
+}
+
+func TestRange(t *testing.T) {
+	s := NewStore[int, int](StoreConfig{})
+	for i := 0; i < 10; i++ {
+		s.Put(i, i*i)
+	}
+	s.Delete(4)
+
+	tx := s.Begin()
+	defer tx.Close()
+	var keys []int
+	for k := range tx.Range(2, 7) {
+		keys = append(keys, k)
+	}
+	assert.Equal(t, []int{2, 3, 5, 6}, keys)
+}
+
+func TestGC(t *testing.T) {
+	s := NewStore[string, int](StoreConfig{})
+	s.Put("a", 1)
+	s.Put("a", 2)
+	old := s.Begin() // Sees a=2
+	s.Put("a", 3)
+	s.Put("b", 1)
+	s.Delete("b")
+	assert.Equal(t, 5, s.Versions())
+
+	// a=1 is invisible to every reader, b was created after the oldest one
+	assert.Equal(t, 1, s.GC())
+	value, _ := old.Get("a")
+	assert.Equal(t, 2, value)
+	assert.Equal(t, 4, s.Versions())
+
+	// Once the reader is gone only the latest versions remain, and deleted keys vanish
+	old.Close()
+	old.Close() // Closing twice is harmless
+	assert.Equal(t, 3, s.GC())
+	assert.Equal(t, 1, s.Versions())
+	assert.Equal(t, 1, s.data.Len())
+
+	tx := s.Begin()
+	defer tx.Close()
+	assert.Equal(t, map[string]int{"a": 3}, collect(tx.All()))
+}
+
+func TestConcurrentReadersAndWriters(t *testing.T) {
+	s := NewStore[int, int](StoreConfig{})
+	const keys = 50
+	var b Batch[int, int]
+	for k := 0; k < keys; k++ {
+		b.Put(k, 0)
+	}
+	s.Commit(&b)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		// Every commit sets all keys to the same value
+		for round := 1; round <= 200; round++ {
+			var b Batch[int, int]
+			for k := 0; k < keys; k++ {
+				b.Put(k, round)
+			}
+			s.Commit(&b)
+			if round%20 == 0 {
+				s.GC()
+			}
+		}
+	}()
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				tx := s.Begin()
+				values := collect(tx.All())
+				tx.Close()
+				if !assert.Len(t, values, keys) {
+					return
+				}
+				for _, v := range values {
+					if !assert.Equal(t, values[0], v, fmt.Sprintf("torn read at %d", tx.Timestamp())) {
+						return
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestMetrics(t *testing.T) {
+	s := NewStore[int, int](StoreConfig{MetricsEnabled: true})
+	s.Put(1, 1)
+	s.Put(1, 2)
+	tx := s.Begin()
+	assert.Equal(t, int64(1), s.readerGauge.Value())
+	tx.Close()
+	s.GC()
+
+	assert.Equal(t, int64(2), s.commitCounter.Count())
+	assert.Equal(t, int64(1), s.gcCounter.Count())
+	assert.Equal(t, int64(1), s.versionGauge.Value())
+	assert.Equal(t, int64(0), s.readerGauge.Value())
+	assert.Equal(t, int64(1), s.gcDuration.Count())
+}
+
+func BenchmarkPut(b *testing.B) {
+	s := NewStore[int, int](StoreConfig{})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Put(i%1000, i)
+	}
+}
+
+func BenchmarkGet(b *testing.B) {
+	s := NewStore[int, int](StoreConfig{})
+	for i := 0; i < 10000; i++ {
+		s.Put(i, i)
+	}
+	tx := s.Begin()
+	defer tx.Close()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tx.Get(i % 10000)
+	}
+}
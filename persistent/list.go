@@ -0,0 +1,145 @@
+package persistent
+
+import (
+	"iter"
+)
+
+type cell[T any] struct {
+	value T
+	next  *cell[T]
+}
+
+// List is an immutable singly linked (cons) list
+// Prepend and Pop run in constant time and share the rest of the list with
+// the original, so a List can be handed to concurrent readers without locking.
+type List[T any] struct {
+	head *cell[T]
+	size int
+}
+
+// NewList returns an empty list
+func NewList[T any]() *List[T] {
+	return &List[T]{}
+}
+
+// ListOf returns a list holding values in the given order
+func ListOf[T any](values ...T) *List[T] {
+	var b ListBuilder[T]
+	for _, v := range values {
+		b.Append(v)
+	}
+	return b.List()
+}
+
+// Len returns the number of elements
+func (l *List[T]) Len() int {
+	return l.size
+}
+
+// Prepend returns a new list with value in front of the elements of l
+func (l *List[T]) Prepend(value T) *List[T] {
+	return &List[T]{head: &cell[T]{value: value, next: l.head}, size: l.size + 1}
+}
+
+// Head returns the first element
+func (l *List[T]) Head() (T, error) {
+	if l.head == nil {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return l.head.value, nil
+}
+
+// Pop returns a new list without the first element, along with that element
+func (l *List[T]) Pop() (*List[T], T, error) {
+	if l.head == nil {
+		var zero T
+		return nil, zero, ErrEmpty
+	}
+	return &List[T]{head: l.head.next, size: l.size - 1}, l.head.value, nil
+}
+
+// Get retrieves the element at the specified index in O(index)
+func (l *List[T]) Get(index int) (T, error) {
+	if index < 0 || index >= l.size {
+		var zero T
+		return zero, ErrIndexOutOfBounds
+	}
+	c := l.head
+	for ; index > 0; index-- {
+		c = c.next
+	}
+	return c.value, nil
+}
+
+// Set returns a new list with the element at index replaced
+// The cells before index are copied, the ones after it are shared.
+func (l *List[T]) Set(index int, value T) (*List[T], error) {
+	if index < 0 || index >= l.size {
+		return nil, ErrIndexOutOfBounds
+	}
+	prefix := make([]T, 0, index)
+	c := l.head
+	for ; index > 0; index-- {
+		prefix = append(prefix, c.value)
+		c = c.next
+	}
+	result := &List[T]{head: &cell[T]{value: value, next: c.next}, size: l.size}
+	for i := len(prefix) - 1; i >= 0; i-- {
+		result.head = &cell[T]{value: prefix[i], next: result.head}
+	}
+	return result, nil
+}
+
+// Reverse returns a new list with the elements in reverse order
+func (l *List[T]) Reverse() *List[T] {
+	result := NewList[T]()
+	for c := l.head; c != nil; c = c.next {
+		result = result.Prepend(c.value)
+	}
+	return result
+}
+
+// All returns an iterator over the indexes and elements in order
+func (l *List[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for c := l.head; c != nil; c = c.next {
+			if !yield(i, c.value) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// ListBuilder builds a list in order by appending at the end in constant time
+// A ListBuilder is not safe for concurrent use and must not be used after List.
+type ListBuilder[T any] struct {
+	head *cell[T]
+	last *cell[T]
+	size int
+	done bool
+}
+
+// Append adds value at the end of the list being built
+func (b *ListBuilder[T]) Append(value T) error {
+	if b.done {
+		return ErrTransientUsed
+	}
+	c := &cell[T]{value: value}
+	if b.last == nil {
+		b.head = c
+	} else {
+		b.last.next = c
+	}
+	b.last = c
+	b.size++
+	return nil
+}
+
+// List returns the built list and ends the builder
+func (b *ListBuilder[T]) List() *List[T] {
+	b.done = true
+	return &List[T]{head: b.head, size: b.size}
+}
@@ -0,0 +1,78 @@
+package persistent
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func collectList[T any](l *List[T]) []T {
+	var values []T
+	for _, value := range l.All() {
+		values = append(values, value)
+	}
+	return values
+}
+
+func TestListPrependPop(t *testing.T) {
+	empty := NewList[int]()
+	one := empty.Prepend(1)
+	two := one.Prepend(2)
+
+	assert.Equal(t, 0, empty.Len())
+	assert.Equal(t, []int{1}, collectList(one))
+	assert.Equal(t, []int{2, 1}, collectList(two))
+
+	rest, head, err := two.Pop()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, head)
+	assert.Equal(t, []int{1}, collectList(rest))
+	assert.Equal(t, []int{2, 1}, collectList(two))
+
+	_, _, err = empty.Pop()
+	assert.Equal(t, ErrEmpty, err)
+	_, err = empty.Head()
+	assert.Equal(t, ErrEmpty, err)
+}
+
+func TestListSetGet(t *testing.T) {
+	l := ListOf(1, 2, 3, 4)
+
+	updated, err := l.Set(2, 30)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 30, 4}, collectList(updated))
+	assert.Equal(t, []int{1, 2, 3, 4}, collectList(l))
+
+	value, err := updated.Get(2)
+	assert.NoError(t, err)
+	assert.Equal(t, 30, value)
+
+	_, err = l.Get(4)
+	assert.Equal(t, ErrIndexOutOfBounds, err)
+	_, err = l.Set(-1, 0)
+	assert.Equal(t, ErrIndexOutOfBounds, err)
+}
+
+func TestListReverse(t *testing.T) {
+	assert.Equal(t, []int{3, 2, 1}, collectList(ListOf(1, 2, 3).Reverse()))
+}
+
+func TestListBuilder(t *testing.T) {
+	var b ListBuilder[string]
+	assert.NoError(t, b.Append("a"))
+	assert.NoError(t, b.Append("b"))
+
+	l := b.List()
+	assert.Equal(t, []string{"a", "b"}, collectList(l))
+	assert.Equal(t, 2, l.Len())
+	assert.Equal(t, ErrTransientUsed, b.Append("c"))
+}
+
+func BenchmarkListPrepend(b *testing.B) {
+	l := NewList[int]()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l = l.Prepend(i)
+	}
+}
@@ -0,0 +1,325 @@
+package persistent
+
+import (
+	"errors"
+	"iter"
+	"slices"
+)
+
+// Errors returned by the persistent structures
+var (
+	ErrIndexOutOfBounds = errors.New("index out of bounds")
+	ErrEmpty            = errors.New("collection is empty")
+	ErrTransientUsed    = errors.New("transient used after Persistent")
+)
+
+const (
+	bits  = 5
+	width = 1 << bits
+	mask  = width - 1
+)
+
+// owner marks the nodes a transient may modify in place
+type owner struct{}
+
+// node is either an inner node holding children or a leaf holding values
+type node[T any] struct {
+	owner    *owner
+	children []*node[T]
+	values   []T
+}
+
+func (n *node[T]) clone(o *owner) *node[T] {
+	return &node[T]{
+		owner:    o,
+		children: slices.Clone(n.children),
+		values:   slices.Clone(n.values),
+	}
+}
+
+// Vector is an immutable vector implemented as a 32-way trie
+// The last (up to 32) elements are kept in a tail outside the trie so that
+// Append and Pop usually only copy the tail. Every modification returns a new
+// vector sharing unchanged nodes with the old one, so a Vector can be handed
+// to concurrent readers without locking.
+type Vector[T any] struct {
+	count int
+	shift uint
+	root  *node[T]
+	tail  []T
+}
+
+// NewVector returns an empty vector
+func NewVector[T any]() *Vector[T] {
+	return &Vector[T]{
+		shift: bits,
+		root:  &node[T]{children: make([]*node[T], width)},
+	}
+}
+
+// VectorOf returns a vector holding values
+func VectorOf[T any](values ...T) *Vector[T] {
+	t := NewVector[T]().Transient()
+	for _, v := range values {
+		t.Append(v)
+	}
+	return t.Persistent()
+}
+
+// Len returns the number of elements
+func (v *Vector[T]) Len() int {
+	return v.count
+}
+
+// Get retrieves the element at the specified index
+func (v *Vector[T]) Get(index int) (T, error) {
+	if index < 0 || index >= v.count {
+		var zero T
+		return zero, ErrIndexOutOfBounds
+	}
+	return leafFor(v.root, v.shift, v.tail, v.count, index)[index&mask], nil
+}
+
+// Set returns a new vector with the element at index replaced
+func (v *Vector[T]) Set(index int, value T) (*Vector[T], error) {
+	if index < 0 || index >= v.count {
+		return nil, ErrIndexOutOfBounds
+	}
+	result := *v
+	if index >= tailOffset(v.count) {
+		result.tail = slices.Clone(v.tail)
+		result.tail[index&mask] = value
+	} else {
+		result.root = assoc(nil, v.shift, v.root, index, value)
+	}
+	return &result, nil
+}
+
+// Append returns a new vector with value added at the end
+func (v *Vector[T]) Append(value T) *Vector[T] {
+	result := *v
+	if v.count-tailOffset(v.count) < width {
+		result.tail = make([]T, len(v.tail)+1, width)
+		copy(result.tail, v.tail)
+		result.tail[len(v.tail)] = value
+	} else {
+		result.root, result.shift = pushTail(nil, v.count, v.shift, v.root, v.tail)
+		result.tail = make([]T, 1, width)
+		result.tail[0] = value
+	}
+	result.count++
+	return &result
+}
+
+// Pop returns a new vector without the last element, along with that element
+func (v *Vector[T]) Pop() (*Vector[T], T, error) {
+	var last T
+	if v.count == 0 {
+		return nil, last, ErrEmpty
+	}
+	last = v.tail[len(v.tail)-1]
+	if v.count == 1 {
+		return NewVector[T](), last, nil
+	}
+
+	result := *v
+	if v.count-tailOffset(v.count) > 1 {
+		result.tail = slices.Clone(v.tail[:len(v.tail)-1])
+	} else {
+		result.tail = leafFor(v.root, v.shift, v.tail, v.count, v.count-2)
+		result.root, result.shift = popTail(nil, v.count, v.shift, v.root)
+	}
+	result.count--
+	return &result, last, nil
+}
+
+// All returns an iterator over the indexes and elements in order
+func (v *Vector[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for base := 0; base < v.count; base += width {
+			for i, value := range leafFor(v.root, v.shift, v.tail, v.count, base) {
+				if !yield(base+i, value) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Transient returns a mutable builder initialized with the contents of the vector
+// The vector itself is left untouched.
+func (v *Vector[T]) Transient() *TransientVector[T] {
+	tail := make([]T, len(v.tail), width)
+	copy(tail, v.tail)
+	return &TransientVector[T]{
+		count: v.count,
+		shift: v.shift,
+		root:  v.root,
+		tail:  tail,
+		owner: &owner{},
+	}
+}
+
+// TransientVector is a mutable vector for efficient batch construction
+// It modifies in place the nodes it created and copies shared ones on first
+// write. A TransientVector is not safe for concurrent use and must not be
+// used after Persistent.
+type TransientVector[T any] struct {
+	count int
+	shift uint
+	root  *node[T]
+	tail  []T
+	owner *owner
+}
+
+// Len returns the number of elements
+func (t *TransientVector[T]) Len() int {
+	return t.count
+}
+
+// Append adds value at the end
+func (t *TransientVector[T]) Append(value T) error {
+	if t.owner == nil {
+		return ErrTransientUsed
+	}
+	if t.count-tailOffset(t.count) < width {
+		t.tail = append(t.tail, value)
+	} else {
+		t.root, t.shift = pushTail(t.owner, t.count, t.shift, t.root, t.tail)
+		t.tail = make([]T, 1, width)
+		t.tail[0] = value
+	}
+	t.count++
+	return nil
+}
+
+// Set replaces the element at index
+func (t *TransientVector[T]) Set(index int, value T) error {
+	if t.owner == nil {
+		return ErrTransientUsed
+	}
+	if index < 0 || index >= t.count {
+		return ErrIndexOutOfBounds
+	}
+	if index >= tailOffset(t.count) {
+		t.tail[index&mask] = value
+	} else {
+		t.root = assoc(t.owner, t.shift, t.root, index, value)
+	}
+	return nil
+}
+
+// Persistent returns an immutable vector holding the current contents and ends the transient
+func (t *TransientVector[T]) Persistent() *Vector[T] {
+	t.owner = nil
+	// The tail keeps spare capacity that persistent appends never write into
+	return &Vector[T]{count: t.count, shift: t.shift, root: t.root, tail: t.tail[:len(t.tail):len(t.tail)]}
+}
+
+// tailOffset returns the index of the first element stored in the tail
+func tailOffset(count int) int {
+	if count < width {
+		return 0
+	}
+	return ((count - 1) >> bits) << bits
+}
+
+// leafFor returns the 32-element block holding index
+func leafFor[T any](root *node[T], shift uint, tail []T, count, index int) []T {
+	if index >= tailOffset(count) {
+		return tail
+	}
+	n := root
+	for level := shift; level > 0; level -= bits {
+		n = n.children[(index>>level)&mask]
+	}
+	return n.values
+}
+
+// editable returns n itself if it belongs to o, or a copy owned by o
+func editable[T any](o *owner, n *node[T]) *node[T] {
+	if o != nil && n.owner == o {
+		return n
+	}
+	return n.clone(o)
+}
+
+func assoc[T any](o *owner, level uint, n *node[T], index int, value T) *node[T] {
+	result := editable(o, n)
+	if level == 0 {
+		result.values[index&mask] = value
+		return result
+	}
+	sub := (index >> level) & mask
+	result.children[sub] = assoc(o, level-bits, n.children[sub], index, value)
+	return result
+}
+
+// pushTail moves a full tail into the trie, growing the trie by one level when the root is full
+func pushTail[T any](o *owner, count int, shift uint, root *node[T], tail []T) (*node[T], uint) {
+	leaf := &node[T]{owner: o, values: slices.Clip(tail)}
+	if o == nil {
+		leaf.values = slices.Clone(tail)
+	}
+	if (count >> bits) > (1 << shift) {
+		newRoot := &node[T]{owner: o, children: make([]*node[T], width)}
+		newRoot.children[0] = root
+		newRoot.children[1] = newPath(o, shift, leaf)
+		return newRoot, shift + bits
+	}
+	return pushLeaf(o, count, shift, root, leaf), shift
+}
+
+func pushLeaf[T any](o *owner, count int, level uint, parent, leaf *node[T]) *node[T] {
+	result := editable(o, parent)
+	sub := ((count - 1) >> level) & mask
+	if level == bits {
+		result.children[sub] = leaf
+	} else if child := parent.children[sub]; child != nil {
+		result.children[sub] = pushLeaf(o, count, level-bits, child, leaf)
+	} else {
+		result.children[sub] = newPath(o, level-bits, leaf)
+	}
+	return result
+}
+
+// newPath wraps leaf in single-child inner nodes up to level
+func newPath[T any](o *owner, level uint, leaf *node[T]) *node[T] {
+	if level == 0 {
+		return leaf
+	}
+	n := &node[T]{owner: o, children: make([]*node[T], width)}
+	n.children[0] = newPath(o, level-bits, leaf)
+	return n
+}
+
+// popTail removes the rightmost leaf, shrinking the trie by one level when possible
+func popTail[T any](o *owner, count int, shift uint, root *node[T]) (*node[T], uint) {
+	newRoot := popLeaf(o, count, shift, root)
+	if newRoot == nil {
+		return &node[T]{owner: o, children: make([]*node[T], width)}, bits
+	}
+	if shift > bits && newRoot.children[1] == nil {
+		return newRoot.children[0], shift - bits
+	}
+	return newRoot, shift
+}
+
+func popLeaf[T any](o *owner, count int, level uint, n *node[T]) *node[T] {
+	sub := ((count - 2) >> level) & mask
+	if level > bits {
+		child := popLeaf(o, count, level-bits, n.children[sub])
+		if child == nil && sub == 0 {
+			return nil
+		}
+		result := editable(o, n)
+		result.children[sub] = child
+		return result
+	}
+	if sub == 0 {
+		return nil
+	}
+	result := editable(o, n)
+	result.children[sub] = nil
+	return result
+}
@@ -0,0 +1,190 @@
+package persistent
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vzahanych/data-structures/array"
+)
+
+func collectVector[T any](v *Vector[T]) []T {
+	var values []T
+	for _, value := range v.All() {
+		values = append(values, value)
+	}
+	return values
+}
+
+func TestVectorAppendGet(t *testing.T) {
+	v := NewVector[int]()
+	versions := []*Vector[int]{v}
+	// Crosses the tail, one-level and two-level trie boundaries
+	for i := 0; i < 33*32+5; i++ {
+		v = v.Append(i)
+		versions = append(versions, v)
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		value, err := v.Get(i)
+		assert.NoError(t, err)
+		assert.Equal(t, i, value)
+	}
+
+	// Older versions are unaffected by later appends
+	for n, version := range versions {
+		assert.Equal(t, n, version.Len())
+	}
+
+	_, err := v.Get(v.Len())
+	assert.Error(t, err)
+	assert.Equal(t, "index out of bounds", err.Error())
+}
+
+func TestVectorSet(t *testing.T) {
+	original := VectorOf(make([]int, 2000)...)
+
+	updated, err := original.Set(5, 50)
+	assert.NoError(t, err)
+	updated, err = updated.Set(1999, 1999)
+	assert.NoError(t, err)
+
+	value, _ := updated.Get(5)
+	assert.Equal(t, 50, value)
+	value, _ = updated.Get(1999)
+	assert.Equal(t, 1999, value)
+
+	// Structural sharing must not leak changes into the original
+	value, _ = original.Get(5)
+	assert.Equal(t, 0, value)
+	value, _ = original.Get(1999)
+	assert.Equal(t, 0, value)
+
+	_, err = original.Set(-1, 0)
+	assert.Equal(t, ErrIndexOutOfBounds, err)
+}
+
+func TestVectorPop(t *testing.T) {
+	n := 32*32 + 32 + 3
+	values := make([]int, n)
+	for i := range values {
+		values[i] = i
+	}
+	v := VectorOf(values...)
+	original := v
+
+	for i := n - 1; i >= 0; i-- {
+		var last int
+		var err error
+		v, last, err = v.Pop()
+		assert.NoError(t, err)
+		assert.Equal(t, i, last)
+		assert.Equal(t, i, v.Len())
+		if i > 0 && i%100 == 0 {
+			assert.Equal(t, values[:i], collectVector(v))
+		}
+	}
+	_, _, err := v.Pop()
+	assert.Equal(t, ErrEmpty, err)
+
+	// Popping back to empty and appending again behaves like a fresh vector
+	v = v.Append(7)
+	value, _ := v.Get(0)
+	assert.Equal(t, 7, value)
+	assert.Equal(t, values, collectVector(original))
+}
+
+func TestVectorRandomized(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	v := NewVector[int]()
+	var model []int
+
+	for round := 0; round < 5000; round++ {
+		switch op := rng.IntN(10); {
+		case op < 6:
+			v = v.Append(round)
+			model = append(model, round)
+		case op < 8 && len(model) > 0:
+			i := rng.IntN(len(model))
+			v, _ = v.Set(i, -round)
+			model[i] = -round
+		case len(model) > 0:
+			var last int
+			v, last, _ = v.Pop()
+			assert.Equal(t, model[len(model)-1], last)
+			model = model[:len(model)-1]
+		}
+	}
+	assert.Equal(t, len(model), v.Len())
+	for i, expected := range model {
+		value, _ := v.Get(i)
+		assert.Equal(t, expected, value)
+	}
+}
+
+func TestTransient(t *testing.T) {
+	base := VectorOf(1, 2, 3)
+	tr := base.Transient()
+	for i := 4; i <= 2000; i++ {
+		assert.NoError(t, tr.Append(i))
+	}
+	assert.NoError(t, tr.Set(0, 100))
+	assert.NoError(t, tr.Set(1500, -1))
+	assert.Equal(t, ErrIndexOutOfBounds, tr.Set(2000, 0))
+
+	built := tr.Persistent()
+	assert.Equal(t, 2000, built.Len())
+	value, _ := built.Get(0)
+	assert.Equal(t, 100, value)
+	value, _ = built.Get(1500)
+	assert.Equal(t, -1, value)
+	value, _ = built.Get(1999)
+	assert.Equal(t, 2000, value)
+
+	// The source vector is unchanged and the transient is closed
+	assert.Equal(t, []int{1, 2, 3}, collectVector(base))
+	assert.Equal(t, ErrTransientUsed, tr.Append(1))
+
+	// Appending to the result leaves it untouched
+	extended := built.Append(2001)
+	assert.Equal(t, 2000, built.Len())
+	assert.Equal(t, 2001, extended.Len())
+}
+
+func BenchmarkVectorAppend(b *testing.B) {
+	v := NewVector[int]()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v = v.Append(i)
+	}
+}
+
+func BenchmarkTransientAppend(b *testing.B) {
+	tr := NewVector[int]().Transient()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tr.Append(i)
+	}
+}
+
+// BenchmarkArrayAppend is the baseline: appends to a preallocated array.Array
+func BenchmarkArrayAppend(b *testing.B) {
+	config := array.ArrayConfig{MetricsEnabled: false}
+	arr := array.NewArray[int](b.N, config)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		arr.Append(i)
+	}
+}
+
+func BenchmarkVectorGet(b *testing.B) {
+	v := VectorOf(make([]int, 100000)...)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v.Get(i % 100000)
+	}
+}
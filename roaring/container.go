@@ -0,0 +1,357 @@
+package roaring
+
+import (
+	"math/bits"
+	"slices"
+)
+
+const (
+	// arrayMaxSize is the largest cardinality stored as a sorted array;
+	// above it a 1024-word bitmap is smaller
+	arrayMaxSize = 4096
+	bitmapWords  = 1024
+)
+
+// container holds the low 16 bits of the values sharing the same high 16 bits
+// Exactly one of array and bitmap is in use: bitmap is nil for array containers
+type container struct {
+	array  []uint16
+	bitmap []uint64
+	card   int
+}
+
+func newArrayContainer() *container {
+	return &container{}
+}
+
+func (c *container) contains(v uint16) bool {
+	if c.bitmap != nil {
+		return c.bitmap[v>>6]&(1<<(v&63)) != 0
+	}
+	_, found := slices.BinarySearch(c.array, v)
+	return found
+}
+
+// add inserts v and reports whether it was absent
+func (c *container) add(v uint16) bool {
+	if c.bitmap != nil {
+		mask := uint64(1) << (v & 63)
+		if c.bitmap[v>>6]&mask != 0 {
+			return false
+		}
+		c.bitmap[v>>6] |= mask
+		c.card++
+		return true
+	}
+
+	i, found := slices.BinarySearch(c.array, v)
+	if found {
+		return false
+	}
+	if c.card == arrayMaxSize {
+		c.toBitmap()
+		return c.add(v)
+	}
+	c.array = slices.Insert(c.array, i, v)
+	c.card++
+	return true
+}
+
+// remove deletes v and reports whether it was present
+func (c *container) remove(v uint16) bool {
+	if c.bitmap != nil {
+		mask := uint64(1) << (v & 63)
+		if c.bitmap[v>>6]&mask == 0 {
+			return false
+		}
+		c.bitmap[v>>6] &^= mask
+		c.card--
+		if c.card <= arrayMaxSize {
+			c.toArray()
+		}
+		return true
+	}
+
+	i, found := slices.BinarySearch(c.array, v)
+	if !found {
+		return false
+	}
+	c.array = slices.Delete(c.array, i, i+1)
+	c.card--
+	return true
+}
+
+// rank returns the number of values less than or equal to v
+func (c *container) rank(v uint16) int {
+	if c.bitmap != nil {
+		count := 0
+		for _, w := range c.bitmap[:v>>6] {
+			count += bits.OnesCount64(w)
+		}
+		shift := 63 - (v & 63)
+		return count + bits.OnesCount64(c.bitmap[v>>6]<<shift)
+	}
+
+	i, found := slices.BinarySearch(c.array, v)
+	if found {
+		return i + 1
+	}
+	return i
+}
+
+// selectAt returns the j-th smallest value, j must be less than card
+func (c *container) selectAt(j int) uint16 {
+	if c.bitmap == nil {
+		return c.array[j]
+	}
+	for i, w := range c.bitmap {
+		count := bits.OnesCount64(w)
+		if j < count {
+			for ; j > 0; j-- {
+				w &= w - 1
+			}
+			return uint16(i*64 + bits.TrailingZeros64(w))
+		}
+		j -= count
+	}
+	panic("roaring: select out of range")
+}
+
+// each calls yield for every value in increasing order and stops when it returns false
+func (c *container) each(yield func(uint16) bool) bool {
+	if c.bitmap == nil {
+		for _, v := range c.array {
+			if !yield(v) {
+				return false
+			}
+		}
+		return true
+	}
+	for i, w := range c.bitmap {
+		for w != 0 {
+			if !yield(uint16(i*64 + bits.TrailingZeros64(w))) {
+				return false
+			}
+			w &= w - 1
+		}
+	}
+	return true
+}
+
+func (c *container) clone() *container {
+	return &container{
+		array:  slices.Clone(c.array),
+		bitmap: slices.Clone(c.bitmap),
+		card:   c.card,
+	}
+}
+
+func (c *container) equal(other *container) bool {
+	if c.card != other.card {
+		return false
+	}
+	if c.bitmap != nil && other.bitmap != nil {
+		return slices.Equal(c.bitmap, other.bitmap)
+	}
+	if c.bitmap == nil && other.bitmap == nil {
+		return slices.Equal(c.array, other.array)
+	}
+	equal := true
+	c.each(func(v uint16) bool {
+		equal = other.contains(v)
+		return equal
+	})
+	return equal
+}
+
+func (c *container) toBitmap() {
+	c.bitmap = c.words()
+	c.array = nil
+}
+
+func (c *container) toArray() {
+	array := make([]uint16, 0, c.card)
+	c.each(func(v uint16) bool {
+		array = append(array, v)
+		return true
+	})
+	c.array = array
+	c.bitmap = nil
+}
+
+// words returns a fresh bitmap representation of the container
+func (c *container) words() []uint64 {
+	if c.bitmap != nil {
+		return slices.Clone(c.bitmap)
+	}
+	words := make([]uint64, bitmapWords)
+	for _, v := range c.array {
+		words[v>>6] |= 1 << (v & 63)
+	}
+	return words
+}
+
+// fromWords builds a container with the representation best suited to its cardinality
+// It returns nil when the words hold no value
+func fromWords(words []uint64) *container {
+	card := 0
+	for _, w := range words {
+		card += bits.OnesCount64(w)
+	}
+	if card == 0 {
+		return nil
+	}
+	c := &container{bitmap: words, card: card}
+	if card <= arrayMaxSize {
+		c.toArray()
+	}
+	return c
+}
+
+// fromArray wraps sorted values, converting them to a bitmap when too many
+// It returns nil when values is empty
+func fromArray(values []uint16) *container {
+	if len(values) == 0 {
+		return nil
+	}
+	c := &container{array: values, card: len(values)}
+	if c.card > arrayMaxSize {
+		c.toBitmap()
+	}
+	return c
+}
+
+func and(a, b *container) *container {
+	switch {
+	case a.bitmap != nil && b.bitmap != nil:
+		words := make([]uint64, bitmapWords)
+		for i := range words {
+			words[i] = a.bitmap[i] & b.bitmap[i]
+		}
+		return fromWords(words)
+	case a.bitmap == nil && b.bitmap == nil:
+		result := make([]uint16, 0, min(a.card, b.card))
+		i, j := 0, 0
+		for i < len(a.array) && j < len(b.array) {
+			switch {
+			case a.array[i] < b.array[j]:
+				i++
+			case a.array[i] > b.array[j]:
+				j++
+			default:
+				result = append(result, a.array[i])
+				i++
+				j++
+			}
+		}
+		return fromArray(result)
+	default:
+		// Probe the bitmap with every value of the array
+		array, bitmap := a, b
+		if a.bitmap != nil {
+			array, bitmap = b, a
+		}
+		result := make([]uint16, 0, array.card)
+		for _, v := range array.array {
+			if bitmap.contains(v) {
+				result = append(result, v)
+			}
+		}
+		return fromArray(result)
+	}
+}
+
+func or(a, b *container) *container {
+	if a.bitmap == nil && b.bitmap == nil && a.card+b.card <= arrayMaxSize {
+		result := make([]uint16, 0, a.card+b.card)
+		i, j := 0, 0
+		for i < len(a.array) && j < len(b.array) {
+			switch {
+			case a.array[i] < b.array[j]:
+				result = append(result, a.array[i])
+				i++
+			case a.array[i] > b.array[j]:
+				result = append(result, b.array[j])
+				j++
+			default:
+				result = append(result, a.array[i])
+				i++
+				j++
+			}
+		}
+		result = append(result, a.array[i:]...)
+		result = append(result, b.array[j:]...)
+		return fromArray(result)
+	}
+
+	words := a.words()
+	if b.bitmap != nil {
+		for i, w := range b.bitmap {
+			words[i] |= w
+		}
+	} else {
+		for _, v := range b.array {
+			words[v>>6] |= 1 << (v & 63)
+		}
+	}
+	return fromWords(words)
+}
+
+func xor(a, b *container) *container {
+	if a.bitmap == nil && b.bitmap == nil && a.card+b.card <= arrayMaxSize {
+		result := make([]uint16, 0, a.card+b.card)
+		i, j := 0, 0
+		for i < len(a.array) && j < len(b.array) {
+			switch {
+			case a.array[i] < b.array[j]:
+				result = append(result, a.array[i])
+				i++
+			case a.array[i] > b.array[j]:
+				result = append(result, b.array[j])
+				j++
+			default:
+				i++
+				j++
+			}
+		}
+		result = append(result, a.array[i:]...)
+		result = append(result, b.array[j:]...)
+		return fromArray(result)
+	}
+
+	words := a.words()
+	if b.bitmap != nil {
+		for i, w := range b.bitmap {
+			words[i] ^= w
+		}
+	} else {
+		for _, v := range b.array {
+			words[v>>6] ^= 1 << (v & 63)
+		}
+	}
+	return fromWords(words)
+}
+
+func andNot(a, b *container) *container {
+	if a.bitmap == nil {
+		result := make([]uint16, 0, a.card)
+		for _, v := range a.array {
+			if !b.contains(v) {
+				result = append(result, v)
+			}
+		}
+		return fromArray(result)
+	}
+
+	words := a.words()
+	if b.bitmap != nil {
+		for i, w := range b.bitmap {
+			words[i] &^= w
+		}
+	} else {
+		for _, v := range b.array {
+			words[v>>6] &^= 1 << (v & 63)
+		}
+	}
+	return fromWords(words)
+}
@@ -0,0 +1,239 @@
+package roaring
+
+import (
+	"iter"
+	"slices"
+)
+
+// Bitmap is a compressed set of uint32 values
+// Values are partitioned by their high 16 bits into containers that are
+// either sorted arrays (sparse chunks) or 65536-bit bitmaps (dense chunks).
+// A Bitmap is not safe for concurrent use; callers that share one between
+// goroutines must provide their own locking.
+type Bitmap struct {
+	keys       []uint16
+	containers []*container
+}
+
+// New creates an empty bitmap
+func New() *Bitmap {
+	return &Bitmap{}
+}
+
+// BitmapOf creates a bitmap containing the given values
+func BitmapOf(values ...uint32) *Bitmap {
+	b := New()
+	for _, v := range values {
+		b.Add(v)
+	}
+	return b
+}
+
+// Add inserts x into the bitmap
+func (b *Bitmap) Add(x uint32) {
+	high := uint16(x >> 16)
+	i, found := slices.BinarySearch(b.keys, high)
+	if !found {
+		b.keys = slices.Insert(b.keys, i, high)
+		b.containers = slices.Insert(b.containers, i, newArrayContainer())
+	}
+	b.containers[i].add(uint16(x))
+}
+
+// Remove deletes x from the bitmap
+func (b *Bitmap) Remove(x uint32) {
+	i, found := slices.BinarySearch(b.keys, uint16(x>>16))
+	if !found {
+		return
+	}
+	c := b.containers[i]
+	if c.remove(uint16(x)) && c.card == 0 {
+		b.keys = slices.Delete(b.keys, i, i+1)
+		b.containers = slices.Delete(b.containers, i, i+1)
+	}
+}
+
+// Contains reports whether x is in the bitmap
+func (b *Bitmap) Contains(x uint32) bool {
+	i, found := slices.BinarySearch(b.keys, uint16(x>>16))
+	return found && b.containers[i].contains(uint16(x))
+}
+
+// Cardinality returns the number of values in the bitmap
+func (b *Bitmap) Cardinality() uint64 {
+	var count uint64
+	for _, c := range b.containers {
+		count += uint64(c.card)
+	}
+	return count
+}
+
+// IsEmpty reports whether the bitmap has no values
+func (b *Bitmap) IsEmpty() bool {
+	return len(b.keys) == 0
+}
+
+// Clear removes all values from the bitmap
+func (b *Bitmap) Clear() {
+	b.keys = nil
+	b.containers = nil
+}
+
+// Clone returns an independent copy of the bitmap
+func (b *Bitmap) Clone() *Bitmap {
+	c := &Bitmap{
+		keys:       slices.Clone(b.keys),
+		containers: make([]*container, len(b.containers)),
+	}
+	for i, ct := range b.containers {
+		c.containers[i] = ct.clone()
+	}
+	return c
+}
+
+// Equals reports whether both bitmaps contain the same values
+func (b *Bitmap) Equals(other *Bitmap) bool {
+	if !slices.Equal(b.keys, other.keys) {
+		return false
+	}
+	for i, c := range b.containers {
+		if !c.equal(other.containers[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// And returns the intersection of both bitmaps
+func (b *Bitmap) And(other *Bitmap) *Bitmap {
+	result := New()
+	i, j := 0, 0
+	for i < len(b.keys) && j < len(other.keys) {
+		switch {
+		case b.keys[i] < other.keys[j]:
+			i++
+		case b.keys[i] > other.keys[j]:
+			j++
+		default:
+			result.appendContainer(b.keys[i], and(b.containers[i], other.containers[j]))
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// Or returns the union of both bitmaps
+func (b *Bitmap) Or(other *Bitmap) *Bitmap {
+	return b.merge(other, or, true)
+}
+
+// Xor returns the symmetric difference of both bitmaps
+func (b *Bitmap) Xor(other *Bitmap) *Bitmap {
+	return b.merge(other, xor, true)
+}
+
+// AndNot returns the values of b that are not in other
+func (b *Bitmap) AndNot(other *Bitmap) *Bitmap {
+	return b.merge(other, andNot, false)
+}
+
+// Rank returns the number of values less than or equal to x
+func (b *Bitmap) Rank(x uint32) uint64 {
+	high := uint16(x >> 16)
+	var count uint64
+	for i, key := range b.keys {
+		if key > high {
+			break
+		}
+		if key < high {
+			count += uint64(b.containers[i].card)
+		} else {
+			count += uint64(b.containers[i].rank(uint16(x)))
+		}
+	}
+	return count
+}
+
+// Select returns the j-th smallest value (counting from zero)
+// The second result is false when the bitmap has j or fewer values
+func (b *Bitmap) Select(j uint64) (uint32, bool) {
+	for i, c := range b.containers {
+		if j < uint64(c.card) {
+			return uint32(b.keys[i])<<16 | uint32(c.selectAt(int(j))), true
+		}
+		j -= uint64(c.card)
+	}
+	return 0, false
+}
+
+// Minimum returns the smallest value, or false when the bitmap is empty
+func (b *Bitmap) Minimum() (uint32, bool) {
+	return b.Select(0)
+}
+
+// Maximum returns the largest value, or false when the bitmap is empty
+func (b *Bitmap) Maximum() (uint32, bool) {
+	if len(b.keys) == 0 {
+		return 0, false
+	}
+	last := len(b.keys) - 1
+	c := b.containers[last]
+	return uint32(b.keys[last])<<16 | uint32(c.selectAt(c.card-1)), true
+}
+
+// All returns an iterator over the values in increasing order
+func (b *Bitmap) All() iter.Seq[uint32] {
+	return func(yield func(uint32) bool) {
+		for i, c := range b.containers {
+			high := uint32(b.keys[i]) << 16
+			if !c.each(func(low uint16) bool { return yield(high | uint32(low)) }) {
+				return
+			}
+		}
+	}
+}
+
+// ToArray returns the values in increasing order
+func (b *Bitmap) ToArray() []uint32 {
+	values := make([]uint32, 0, b.Cardinality())
+	for v := range b.All() {
+		values = append(values, v)
+	}
+	return values
+}
+
+// merge combines both bitmaps key by key
+// Containers found in only one operand are copied when keepOther is set,
+// those found only in b are always copied
+func (b *Bitmap) merge(other *Bitmap, op func(a, b *container) *container, keepOther bool) *Bitmap {
+	result := New()
+	i, j := 0, 0
+	for i < len(b.keys) || j < len(other.keys) {
+		switch {
+		case j == len(other.keys) || (i < len(b.keys) && b.keys[i] < other.keys[j]):
+			result.appendContainer(b.keys[i], b.containers[i].clone())
+			i++
+		case i == len(b.keys) || b.keys[i] > other.keys[j]:
+			if keepOther {
+				result.appendContainer(other.keys[j], other.containers[j].clone())
+			}
+			j++
+		default:
+			result.appendContainer(b.keys[i], op(b.containers[i], other.containers[j]))
+			i++
+			j++
+		}
+	}
+	return result
+}
+
+// appendContainer adds a container with a key greater than all existing ones
+// Nil containers, produced by operations with an empty result, are skipped
+func (b *Bitmap) appendContainer(key uint16, c *container) {
+	if c == nil {
+		return
+	}
+	b.keys = append(b.keys, key)
+	b.containers = append(b.containers, c)
+}
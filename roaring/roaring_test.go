@@ -0,0 +1,237 @@
+package roaring
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// randomBitmap builds a bitmap mixing sparse and dense chunks along with the expected set
+func randomBitmap(rng *rand.Rand) (*Bitmap, map[uint32]bool) {
+	b := New()
+	expected := make(map[uint32]bool)
+	for chunk := uint32(0); chunk < 6; chunk++ {
+		count := 100
+		if rng.IntN(2) == 0 {
+			count = 10000 // Dense enough to become a bitmap container
+		}
+		for i := 0; i < count; i++ {
+			v := chunk<<16 | uint32(rng.IntN(1<<16))
+			b.Add(v)
+			expected[v] = true
+		}
+	}
+	return b, expected
+}
+
+func sortedKeys(set map[uint32]bool) []uint32 {
+	values := make([]uint32, 0, len(set))
+	for v := range set {
+		values = append(values, v)
+	}
+	slices.Sort(values)
+	return values
+}
+
+func TestAddContainsRemove(t *testing.T) {
+	b := New()
+
+	b.Add(1)
+	b.Add(70000)
+	b.Add(1)
+	assert.True(t, b.Contains(1))
+	assert.True(t, b.Contains(70000))
+	assert.False(t, b.Contains(2))
+	assert.Equal(t, uint64(2), b.Cardinality())
+
+	b.Remove(70000)
+	b.Remove(12345) // No-op
+	assert.False(t, b.Contains(70000))
+	assert.Equal(t, uint64(1), b.Cardinality())
+
+	b.Remove(1)
+	assert.True(t, b.IsEmpty())
+}
+
+func TestContainerConversion(t *testing.T) {
+	b := New()
+	for i := uint32(0); i < 5000; i++ {
+		b.Add(i * 2)
+	}
+	assert.NotNil(t, b.containers[0].bitmap)
+	assert.Equal(t, uint64(5000), b.Cardinality())
+
+	for i := uint32(0); i < 1000; i++ {
+		b.Remove(i * 2)
+	}
+	assert.Nil(t, b.containers[0].bitmap)
+	assert.Equal(t, uint64(4000), b.Cardinality())
+	assert.True(t, b.Contains(2000*2))
+	assert.False(t, b.Contains(999*2))
+}
+
+func TestSetOperations(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	for round := 0; round < 5; round++ {
+		a, expectedA := randomBitmap(rng)
+		b, expectedB := randomBitmap(rng)
+
+		and, or, xor, andNot := map[uint32]bool{}, map[uint32]bool{}, map[uint32]bool{}, map[uint32]bool{}
+		for v := range expectedA {
+			or[v] = true
+			if expectedB[v] {
+				and[v] = true
+			} else {
+				xor[v] = true
+				andNot[v] = true
+			}
+		}
+		for v := range expectedB {
+			or[v] = true
+			if !expectedA[v] {
+				xor[v] = true
+			}
+		}
+
+		assert.Equal(t, sortedKeys(and), a.And(b).ToArray())
+		assert.Equal(t, sortedKeys(or), a.Or(b).ToArray())
+		assert.Equal(t, sortedKeys(xor), a.Xor(b).ToArray())
+		assert.Equal(t, sortedKeys(andNot), a.AndNot(b).ToArray())
+		assert.Equal(t, sortedKeys(expectedA), a.ToArray())
+	}
+}
+
+func TestRankSelect(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+	b, expected := randomBitmap(rng)
+	values := sortedKeys(expected)
+
+	for j, v := range values {
+		got, ok := b.Select(uint64(j))
+		assert.True(t, ok)
+		assert.Equal(t, v, got)
+		assert.Equal(t, uint64(j+1), b.Rank(v))
+	}
+	_, ok := b.Select(uint64(len(values)))
+	assert.False(t, ok)
+	assert.Equal(t, uint64(len(values)), b.Rank(0xffffffff))
+
+	minimum, _ := b.Minimum()
+	maximum, _ := b.Maximum()
+	assert.Equal(t, values[0], minimum)
+	assert.Equal(t, values[len(values)-1], maximum)
+}
+
+func TestIterator(t *testing.T) {
+	b := BitmapOf(5, 1, 1<<20, 3)
+
+	assert.Equal(t, []uint32{1, 3, 5, 1 << 20}, slices.Collect(b.All()))
+
+	var seen []uint32
+	for v := range b.All() {
+		if v > 3 {
+			break
+		}
+		seen = append(seen, v)
+	}
+	assert.Equal(t, []uint32{1, 3}, seen)
+}
+
+func TestCloneEquals(t *testing.T) {
+	b := BitmapOf(1, 2, 3)
+	c := b.Clone()
+	assert.True(t, b.Equals(c))
+
+	c.Add(4)
+	assert.False(t, b.Equals(c))
+	assert.False(t, b.Contains(4))
+}
+
+func TestSerializationFormat(t *testing.T) {
+	// Byte layout defined by the portable roaring format specification
+	expected := []byte{
+		0x3a, 0x30, 0x00, 0x00, // Cookie 12346
+		0x01, 0x00, 0x00, 0x00, // One container
+		0x00, 0x00, 0x02, 0x00, // Key 0, cardinality 3
+		0x10, 0x00, 0x00, 0x00, // Container offset 16
+		0x01, 0x00, 0x02, 0x00, 0x03, 0x00, // Values 1, 2, 3
+	}
+
+	data, err := BitmapOf(1, 2, 3).MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, expected, data)
+}
+
+func TestSerializationRoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewPCG(5, 6))
+	b, _ := randomBitmap(rng)
+
+	var buf bytes.Buffer
+	n, err := b.WriteTo(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(b.SerializedSize()), n)
+
+	restored := New()
+	read, err := restored.ReadFrom(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, n, read)
+	assert.True(t, b.Equals(restored))
+}
+
+func TestReadRunContainers(t *testing.T) {
+	// Two containers, the first one encoded as runs [10, 14] and [100, 5099]
+	var data []byte
+	data = binary.LittleEndian.AppendUint32(data, serialCookie|(2-1)<<16)
+	data = append(data, 0x01) // Run flags: only the first container
+	data = binary.LittleEndian.AppendUint16(data, 0)
+	data = binary.LittleEndian.AppendUint16(data, 5+5000-1)
+	data = binary.LittleEndian.AppendUint16(data, 1)
+	data = binary.LittleEndian.AppendUint16(data, 1-1)
+	data = binary.LittleEndian.AppendUint16(data, 2) // Two runs
+	data = binary.LittleEndian.AppendUint16(data, 10)
+	data = binary.LittleEndian.AppendUint16(data, 4)
+	data = binary.LittleEndian.AppendUint16(data, 100)
+	data = binary.LittleEndian.AppendUint16(data, 4999)
+	data = binary.LittleEndian.AppendUint16(data, 7) // Array container holding 65543
+
+	b := New()
+	assert.NoError(t, b.UnmarshalBinary(data))
+	assert.Equal(t, uint64(5006), b.Cardinality())
+	assert.True(t, b.Contains(10))
+	assert.True(t, b.Contains(14))
+	assert.False(t, b.Contains(15))
+	assert.True(t, b.Contains(5099))
+	assert.True(t, b.Contains(65543))
+}
+
+func TestInvalidData(t *testing.T) {
+	b := New()
+	assert.Equal(t, ErrInvalidFormat, b.UnmarshalBinary([]byte{1, 2, 3, 4}))
+
+	data, _ := BitmapOf(1, 2, 3).MarshalBinary()
+	assert.Error(t, b.UnmarshalBinary(data[:len(data)-1]))
+}
+
+func BenchmarkAdd(b *testing.B) {
+	bitmap := New()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		bitmap.Add(uint32(i) * 7)
+	}
+}
+
+func BenchmarkAnd(b *testing.B) {
+	rng := rand.New(rand.NewPCG(7, 8))
+	x, _ := randomBitmap(rng)
+	y, _ := randomBitmap(rng)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x.And(y)
+	}
+}
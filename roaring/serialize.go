@@ -0,0 +1,256 @@
+package roaring
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// Cookies of the portable roaring format shared with the C, Java and Go implementations
+// See https://github.com/RoaringBitmap/RoaringFormatSpec
+const (
+	serialCookieNoRunContainer = 12346
+	serialCookie               = 12347
+	noOffsetThreshold          = 4
+)
+
+// ErrInvalidFormat is returned when decoding data that is not a portable roaring bitmap
+var ErrInvalidFormat = errors.New("invalid roaring bitmap format")
+
+// SerializedSize returns the number of bytes written by WriteTo
+func (b *Bitmap) SerializedSize() int {
+	size := 8 + 8*len(b.keys) // Cookie, container count, descriptive header and offsets
+	for _, c := range b.containers {
+		size += containerSize(c)
+	}
+	return size
+}
+
+// MarshalBinary encodes the bitmap in the portable roaring format
+func (b *Bitmap) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Grow(b.SerializedSize())
+	if _, err := b.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary replaces the bitmap contents with data in the portable roaring format
+func (b *Bitmap) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	if _, err := b.ReadFrom(r); err != nil {
+		return err
+	}
+	if r.Len() != 0 {
+		return ErrInvalidFormat
+	}
+	return nil
+}
+
+// WriteTo writes the bitmap in the portable roaring format
+// Containers are always written as arrays or bitmaps, never as runs, which
+// every compliant reader accepts.
+func (b *Bitmap) WriteTo(w io.Writer) (int64, error) {
+	n := len(b.keys)
+	header := make([]byte, 0, 8+8*n)
+	header = binary.LittleEndian.AppendUint32(header, serialCookieNoRunContainer)
+	header = binary.LittleEndian.AppendUint32(header, uint32(n))
+	for i, key := range b.keys {
+		header = binary.LittleEndian.AppendUint16(header, key)
+		header = binary.LittleEndian.AppendUint16(header, uint16(b.containers[i].card-1))
+	}
+	offset := uint32(8 + 8*n)
+	for _, c := range b.containers {
+		header = binary.LittleEndian.AppendUint32(header, offset)
+		offset += uint32(containerSize(c))
+	}
+
+	written, err := w.Write(header)
+	total := int64(written)
+	if err != nil {
+		return total, err
+	}
+
+	for _, c := range b.containers {
+		var data []byte
+		if c.bitmap != nil {
+			data = make([]byte, 0, 8*bitmapWords)
+			for _, word := range c.bitmap {
+				data = binary.LittleEndian.AppendUint64(data, word)
+			}
+		} else {
+			data = make([]byte, 0, 2*c.card)
+			for _, v := range c.array {
+				data = binary.LittleEndian.AppendUint16(data, v)
+			}
+		}
+		written, err = w.Write(data)
+		total += int64(written)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// ReadFrom replaces the bitmap contents with a bitmap in the portable roaring format
+// Run containers written by other implementations are converted to arrays or bitmaps.
+func (b *Bitmap) ReadFrom(r io.Reader) (int64, error) {
+	cr := &countingReader{r: r}
+
+	cookie, err := cr.uint32()
+	if err != nil {
+		return cr.n, err
+	}
+
+	var size int
+	var runFlags []byte
+	switch {
+	case cookie&0xffff == serialCookie:
+		size = int(cookie>>16) + 1
+		runFlags = make([]byte, (size+7)/8)
+		if err := cr.read(runFlags); err != nil {
+			return cr.n, err
+		}
+	case cookie == serialCookieNoRunContainer:
+		count, err := cr.uint32()
+		if err != nil {
+			return cr.n, err
+		}
+		if count > 1<<16 {
+			return cr.n, ErrInvalidFormat
+		}
+		size = int(count)
+	default:
+		return cr.n, ErrInvalidFormat
+	}
+
+	descriptive := make([]byte, 4*size)
+	if err := cr.read(descriptive); err != nil {
+		return cr.n, err
+	}
+	// Offsets are only needed for random access, containers are read in order
+	if runFlags == nil || size >= noOffsetThreshold {
+		if err := cr.read(make([]byte, 4*size)); err != nil {
+			return cr.n, err
+		}
+	}
+
+	keys := make([]uint16, size)
+	containers := make([]*container, size)
+	for i := 0; i < size; i++ {
+		keys[i] = binary.LittleEndian.Uint16(descriptive[4*i:])
+		card := int(binary.LittleEndian.Uint16(descriptive[4*i+2:])) + 1
+		if i > 0 && keys[i] <= keys[i-1] {
+			return cr.n, ErrInvalidFormat
+		}
+
+		var c *container
+		switch {
+		case runFlags != nil && runFlags[i/8]&(1<<(i%8)) != 0:
+			c, err = cr.runContainer()
+		case card > arrayMaxSize:
+			c, err = cr.bitmapContainer()
+		default:
+			c, err = cr.arrayContainer(card)
+		}
+		if err != nil {
+			return cr.n, err
+		}
+		if c == nil || c.card != card {
+			return cr.n, ErrInvalidFormat
+		}
+		containers[i] = c
+	}
+
+	b.keys = keys
+	b.containers = containers
+	return cr.n, nil
+}
+
+// containerSize returns the serialized size of a container in bytes
+func containerSize(c *container) int {
+	if c.bitmap != nil {
+		return 8 * bitmapWords
+	}
+	return 2 * c.card
+}
+
+// countingReader tracks the number of bytes consumed for ReadFrom
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) read(buf []byte) error {
+	n, err := io.ReadFull(cr.r, buf)
+	cr.n += int64(n)
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+func (cr *countingReader) uint32() (uint32, error) {
+	var buf [4]byte
+	if err := cr.read(buf[:]); err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(buf[:]), nil
+}
+
+func (cr *countingReader) arrayContainer(card int) (*container, error) {
+	buf := make([]byte, 2*card)
+	if err := cr.read(buf); err != nil {
+		return nil, err
+	}
+	values := make([]uint16, card)
+	for i := range values {
+		values[i] = binary.LittleEndian.Uint16(buf[2*i:])
+		if i > 0 && values[i] <= values[i-1] {
+			return nil, ErrInvalidFormat
+		}
+	}
+	return fromArray(values), nil
+}
+
+func (cr *countingReader) bitmapContainer() (*container, error) {
+	buf := make([]byte, 8*bitmapWords)
+	if err := cr.read(buf); err != nil {
+		return nil, err
+	}
+	words := make([]uint64, bitmapWords)
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint64(buf[8*i:])
+	}
+	return fromWords(words), nil
+}
+
+// runContainer decodes a run container, stored as a run count followed by
+// (start, length-1) pairs, into its array or bitmap equivalent
+func (cr *countingReader) runContainer() (*container, error) {
+	var countBuf [2]byte
+	if err := cr.read(countBuf[:]); err != nil {
+		return nil, err
+	}
+	runs := int(binary.LittleEndian.Uint16(countBuf[:]))
+	buf := make([]byte, 4*runs)
+	if err := cr.read(buf); err != nil {
+		return nil, err
+	}
+
+	words := make([]uint64, bitmapWords)
+	for i := 0; i < runs; i++ {
+		start := int(binary.LittleEndian.Uint16(buf[4*i:]))
+		end := start + int(binary.LittleEndian.Uint16(buf[4*i+2:]))
+		if end > 0xffff {
+			return nil, ErrInvalidFormat
+		}
+		for v := start; v <= end; v++ {
+			words[v>>6] |= 1 << (v & 63)
+		}
+	}
+	return fromWords(words), nil
+}
@@ -0,0 +1,513 @@
+package rope
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"iter"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+// Errors returned by Rope
+var (
+	ErrIndexOutOfBounds = errors.New("index out of bounds")
+	ErrInvalidRange     = errors.New("invalid range")
+)
+
+// maxLeaf is the size up to which adjacent chunks are merged into a single leaf
+const maxLeaf = 1024
+
+type Config struct {
+	MetricsEnabled bool
+}
+
+// node is a leaf holding a chunk of text or an inner node concatenating two subtrees
+// Nodes are never modified once built, so subtrees are freely shared
+// between ropes, clones and readers.
+type node struct {
+	left, right *node
+	data        []byte
+	length      int
+	newlines    int
+	height      int
+}
+
+func leaf(data []byte) *node {
+	return &node{data: data, length: len(data), newlines: bytes.Count(data, []byte{'\n'})}
+}
+
+func inner(left, right *node) *node {
+	return &node{
+		left:     left,
+		right:    right,
+		length:   left.length + right.length,
+		newlines: left.newlines + right.newlines,
+		height:   max(left.height, right.height) + 1,
+	}
+}
+
+func (n *node) isLeaf() bool {
+	return n.left == nil
+}
+
+func height(n *node) int {
+	if n == nil {
+		return -1
+	}
+	return n.height
+}
+
+// Rope is a text buffer stored as a balanced tree of chunks
+// Insert, Delete, Slice and Index run in O(log n) plus the size of the text
+// they copy, instead of shifting the whole buffer. Offsets are byte offsets.
+type Rope struct {
+	root          *node
+	mu            sync.RWMutex
+	config        Config
+	insertCounter metrics.Counter
+	deleteCounter metrics.Counter
+	sliceCounter  metrics.Counter
+}
+
+// New creates a rope holding text
+// The config parameter is used to enable or disable metrics collection
+func New(text string, config Config) *Rope {
+	r := &Rope{root: build([]byte(text)), config: config}
+
+	// Initialize the metrics only if enabled in the config
+	if r.config.MetricsEnabled {
+		r.insertCounter = metrics.NewCounter()
+		r.deleteCounter = metrics.NewCounter()
+		r.sliceCounter = metrics.NewCounter()
+		metrics.DefaultRegistry.Register("rope.insert", r.insertCounter)
+		metrics.DefaultRegistry.Register("rope.delete", r.deleteCounter)
+		metrics.DefaultRegistry.Register("rope.slice", r.sliceCounter)
+	}
+
+	return r
+}
+
+// build returns a balanced tree holding data split in leaves, or nil for empty data
+func build(data []byte) *node {
+	if len(data) == 0 {
+		return nil
+	}
+	if len(data) <= maxLeaf {
+		return leaf(data)
+	}
+	// Split on a leaf boundary so that every leaf but the last is full
+	leaves := (len(data) + maxLeaf - 1) / maxLeaf
+	mid := leaves / 2 * maxLeaf
+	return inner(build(data[:mid]), build(data[mid:]))
+}
+
+// Len returns the length of the text in bytes
+func (r *Rope) Len() int {
+	r.mu.RLock() // Lock for reading
+	defer r.mu.RUnlock()
+
+	return length(r.root)
+}
+
+func length(n *node) int {
+	if n == nil {
+		return 0
+	}
+	return n.length
+}
+
+// Clone returns an independent copy of the rope in O(1)
+func (r *Rope) Clone() *Rope {
+	r.mu.RLock() // Lock for reading
+	defer r.mu.RUnlock()
+
+	return &Rope{
+		root:          r.root,
+		config:        r.config,
+		insertCounter: r.insertCounter,
+		deleteCounter: r.deleteCounter,
+		sliceCounter:  r.sliceCounter,
+	}
+}
+
+// Insert inserts text at the byte offset at
+func (r *Rope) Insert(at int, text string) error {
+	r.mu.Lock() // Lock for writing
+	defer r.mu.Unlock()
+
+	if at < 0 || at > length(r.root) {
+		return ErrIndexOutOfBounds
+	}
+	left, right := split(r.root, at)
+	r.root = join(join(left, build([]byte(text))), right)
+
+	// Track metrics if enabled
+	if r.config.MetricsEnabled {
+		r.insertCounter.Inc(1)
+	}
+	return nil
+}
+
+// Delete removes n bytes starting at the byte offset at
+func (r *Rope) Delete(at, n int) error {
+	r.mu.Lock() // Lock for writing
+	defer r.mu.Unlock()
+
+	if at < 0 || n < 0 || at+n > length(r.root) {
+		return ErrInvalidRange
+	}
+	left, rest := split(r.root, at)
+	_, right := split(rest, n)
+	r.root = join(left, right)
+
+	// Track metrics if enabled
+	if r.config.MetricsEnabled {
+		r.deleteCounter.Inc(1)
+	}
+	return nil
+}
+
+// Slice returns the text in the byte range [from, to)
+func (r *Rope) Slice(from, to int) (string, error) {
+	r.mu.RLock() // Lock for reading
+	defer r.mu.RUnlock()
+
+	if from < 0 || to < from || to > length(r.root) {
+		return "", ErrInvalidRange
+	}
+	var b bytes.Buffer
+	b.Grow(to - from)
+	collect(r.root, from, to, &b)
+
+	// Track metrics if enabled
+	if r.config.MetricsEnabled {
+		r.sliceCounter.Inc(1)
+	}
+	return b.String(), nil
+}
+
+// collect appends the bytes of n in [from, to) to b
+func collect(n *node, from, to int, b *bytes.Buffer) {
+	if n == nil || from >= to {
+		return
+	}
+	if n.isLeaf() {
+		b.Write(n.data[from:to])
+		return
+	}
+	if from < n.left.length {
+		collect(n.left, from, min(to, n.left.length), b)
+	}
+	if to > n.left.length {
+		collect(n.right, max(from-n.left.length, 0), to-n.left.length, b)
+	}
+}
+
+// String returns the whole text
+func (r *Rope) String() string {
+	r.mu.RLock() // Lock for reading
+	defer r.mu.RUnlock()
+
+	var b bytes.Buffer
+	b.Grow(length(r.root))
+	collect(r.root, 0, length(r.root), &b)
+	return b.String()
+}
+
+// Index returns the byte at offset i
+func (r *Rope) Index(i int) (byte, error) {
+	r.mu.RLock() // Lock for reading
+	defer r.mu.RUnlock()
+
+	if i < 0 || i >= length(r.root) {
+		return 0, ErrIndexOutOfBounds
+	}
+	return suffix(r.root, i)[0], nil
+}
+
+// Lines returns the number of lines, which is one more than the number of newlines
+func (r *Rope) Lines() int {
+	r.mu.RLock() // Lock for reading
+	defer r.mu.RUnlock()
+
+	if r.root == nil {
+		return 1
+	}
+	return r.root.newlines + 1
+}
+
+// Position returns the zero-based line and byte column of the byte offset
+func (r *Rope) Position(offset int) (line, column int, err error) {
+	r.mu.RLock() // Lock for reading
+	defer r.mu.RUnlock()
+
+	if offset < 0 || offset > length(r.root) {
+		return 0, 0, ErrIndexOutOfBounds
+	}
+	line = newlinesBefore(r.root, offset)
+	return line, offset - lineStart(r.root, line), nil
+}
+
+// Offset returns the byte offset of the zero-based line and byte column
+// The column may point at the newline ending the line but not past it.
+func (r *Rope) Offset(line, column int) (int, error) {
+	r.mu.RLock() // Lock for reading
+	defer r.mu.RUnlock()
+
+	lines := 1
+	if r.root != nil {
+		lines += r.root.newlines
+	}
+	if line < 0 || line >= lines || column < 0 {
+		return 0, ErrIndexOutOfBounds
+	}
+	start := lineStart(r.root, line)
+	end := length(r.root)
+	if line+1 < lines {
+		end = lineStart(r.root, line+1) - 1
+	}
+	if start+column > end {
+		return 0, ErrIndexOutOfBounds
+	}
+	return start + column, nil
+}
+
+// newlinesBefore counts the newlines in [0, offset)
+func newlinesBefore(n *node, offset int) int {
+	count := 0
+	for n != nil && offset > 0 {
+		if n.isLeaf() {
+			return count + bytes.Count(n.data[:offset], []byte{'\n'})
+		}
+		if offset <= n.left.length {
+			n = n.left
+		} else {
+			count += n.left.newlines
+			offset -= n.left.length
+			n = n.right
+		}
+	}
+	return count
+}
+
+// lineStart returns the offset following the line-th newline, line must exist
+func lineStart(n *node, line int) int {
+	offset := 0
+	for line > 0 {
+		if n.isLeaf() {
+			for i, c := range n.data {
+				if c == '\n' {
+					if line--; line == 0 {
+						return offset + i + 1
+					}
+				}
+			}
+		}
+		if line <= n.left.newlines {
+			n = n.left
+		} else {
+			line -= n.left.newlines
+			offset += n.left.length
+			n = n.right
+		}
+	}
+	return offset
+}
+
+// Runes returns an iterator over the byte offsets and runes of the text
+// Invalid UTF-8 yields utf8.RuneError for each invalid byte. The rope is
+// locked for reading during iteration, so the loop body must not modify it.
+func (r *Rope) Runes() iter.Seq2[int, rune] {
+	return func(yield func(int, rune) bool) {
+		r.mu.RLock() // Lock for reading
+		defer r.mu.RUnlock()
+
+		// pending holds the start of a rune split across two leaves
+		pending := make([]byte, 0, utf8.UTFMax)
+		offset := 0
+		decode := func(data []byte) ([]byte, bool) {
+			for len(data) > 0 && utf8.FullRune(data) {
+				c, size := utf8.DecodeRune(data)
+				if !yield(offset, c) {
+					return nil, false
+				}
+				offset += size
+				data = data[size:]
+			}
+			return data, true
+		}
+
+		ok := walk(r.root, func(data []byte) bool {
+			for len(pending) > 0 && len(data) > 0 {
+				pending = append(pending, data[0])
+				data = data[1:]
+				rest, ok := decode(pending)
+				if !ok {
+					return false
+				}
+				pending = append(pending[:0], rest...)
+			}
+			rest, ok := decode(data)
+			if !ok {
+				return false
+			}
+			pending = append(pending, rest...)
+			return true
+		})
+		// Whatever is left is a truncated rune
+		for ; ok && len(pending) > 0; pending = pending[1:] {
+			if !yield(offset, utf8.RuneError) {
+				return
+			}
+			offset++
+		}
+	}
+}
+
+// walk calls fn with the leaves of n in order until it returns false
+func walk(n *node, fn func(data []byte) bool) bool {
+	if n == nil {
+		return true
+	}
+	if n.isLeaf() {
+		return fn(n.data)
+	}
+	return walk(n.left, fn) && walk(n.right, fn)
+}
+
+// WriteTo writes the whole text to w
+func (r *Rope) WriteTo(w io.Writer) (int64, error) {
+	return r.Reader().WriteTo(w)
+}
+
+// Reader returns a reader over the current text
+// Later modifications of the rope are not visible to the reader.
+func (r *Rope) Reader() *Reader {
+	r.mu.RLock() // Lock for reading
+	defer r.mu.RUnlock()
+
+	return &Reader{root: r.root}
+}
+
+// Reader implements io.Reader and io.WriterTo over a version of a rope
+type Reader struct {
+	root   *node
+	offset int
+}
+
+// Read reads the next bytes of the text into p
+func (rd *Reader) Read(p []byte) (int, error) {
+	if rd.offset >= length(rd.root) {
+		return 0, io.EOF
+	}
+	n := 0
+	for n < len(p) && rd.offset < length(rd.root) {
+		copied := copy(p[n:], suffix(rd.root, rd.offset))
+		n += copied
+		rd.offset += copied
+	}
+	return n, nil
+}
+
+// suffix returns the bytes of the leaf holding offset i, starting at i
+func suffix(n *node, i int) []byte {
+	for !n.isLeaf() {
+		if i < n.left.length {
+			n = n.left
+		} else {
+			i -= n.left.length
+			n = n.right
+		}
+	}
+	return n.data[i:]
+}
+
+// WriteTo writes the unread text to w
+func (rd *Reader) WriteTo(w io.Writer) (int64, error) {
+	var written int64
+	skip := rd.offset
+	var err error
+	walk(rd.root, func(data []byte) bool {
+		if skip >= len(data) {
+			skip -= len(data)
+			return true
+		}
+		var n int
+		n, err = w.Write(data[skip:])
+		skip = 0
+		written += int64(n)
+		rd.offset += n
+		return err == nil
+	})
+	return written, err
+}
+
+// split returns the trees holding the bytes of n before and after offset i
+func split(n *node, i int) (*node, *node) {
+	if n == nil {
+		return nil, nil
+	}
+	if i <= 0 {
+		return nil, n
+	}
+	if i >= n.length {
+		return n, nil
+	}
+	if n.isLeaf() {
+		return leaf(n.data[:i:i]), leaf(n.data[i:])
+	}
+	if i < n.left.length {
+		left, right := split(n.left, i)
+		return left, join(right, n.right)
+	}
+	left, right := split(n.right, i-n.left.length)
+	return join(n.left, left), right
+}
+
+// join concatenates two trees, keeping the result balanced
+func join(left, right *node) *node {
+	switch {
+	case left == nil:
+		return right
+	case right == nil:
+		return left
+	case left.isLeaf() && right.isLeaf() && left.length+right.length <= maxLeaf:
+		data := make([]byte, 0, left.length+right.length)
+		return leaf(append(append(data, left.data...), right.data...))
+	case left.height > right.height+1:
+		return rebalance(inner(left.left, join(left.right, right)))
+	case right.height > left.height+1:
+		return rebalance(inner(join(left, right.left), right.right))
+	}
+	return inner(left, right)
+}
+
+// rebalance restores the AVL invariant at n after one of its subtrees grew by one level
+func rebalance(n *node) *node {
+	switch balance := height(n.left) - height(n.right); {
+	case balance > 1:
+		l := n.left
+		if height(l.left) < height(l.right) {
+			l = rotateLeft(l)
+		}
+		return rotateRight(inner(l, n.right))
+	case balance < -1:
+		r := n.right
+		if height(r.right) < height(r.left) {
+			r = rotateRight(r)
+		}
+		return rotateLeft(inner(n.left, r))
+	}
+	return n
+}
+
+func rotateLeft(n *node) *node {
+	return inner(inner(n.left, n.right.left), n.right.right)
+}
+
+func rotateRight(n *node) *node {
+	return inner(n.left.left, inner(n.left.right, n.right))
+}
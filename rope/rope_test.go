@@ -0,0 +1,226 @@
+package rope
+
+import (
+	"bytes"
+	"io"
+	"math/rand/v2"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vzahanych/data-structures/array"
+)
+
+// checkBalanced verifies the cached fields and the AVL invariant of every node
+func checkBalanced(t *testing.T, n *node) {
+	if n == nil || n.isLeaf() {
+		return
+	}
+	assert.LessOrEqual(t, abs(n.left.height-n.right.height), 1)
+	assert.Equal(t, n.left.length+n.right.length, n.length)
+	assert.Equal(t, max(n.left.height, n.right.height)+1, n.height)
+	checkBalanced(t, n.left)
+	checkBalanced(t, n.right)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func TestInsertDelete(t *testing.T) {
+	r := New("hello world", Config{})
+	assert.NoError(t, r.Insert(5, ","))
+	assert.NoError(t, r.Insert(r.Len(), "!"))
+	assert.NoError(t, r.Insert(0, ">> "))
+	assert.Equal(t, ">> hello, world!", r.String())
+
+	assert.NoError(t, r.Delete(0, 3))
+	assert.NoError(t, r.Delete(5, 1))
+	assert.Equal(t, "hello world!", r.String())
+
+	assert.Equal(t, ErrIndexOutOfBounds, r.Insert(-1, "x"))
+	assert.Equal(t, ErrIndexOutOfBounds, r.Insert(100, "x"))
+	assert.Equal(t, ErrInvalidRange, r.Delete(10, 5))
+	assert.Equal(t, "hello world!", r.String())
+}
+
+func TestRandomized(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	r := New("", Config{})
+	model := ""
+
+	for round := 0; round < 3000; round++ {
+		if len(model) > 0 && rng.IntN(3) == 0 {
+			at := rng.IntN(len(model))
+			n := rng.IntN(min(len(model)-at, 2000) + 1)
+			assert.NoError(t, r.Delete(at, n))
+			model = model[:at] + model[at+n:]
+		} else {
+			at := rng.IntN(len(model) + 1)
+			text := strings.Repeat(string(rune('a'+round%26)), rng.IntN(1500))
+			assert.NoError(t, r.Insert(at, text))
+			model = model[:at] + text + model[at:]
+		}
+	}
+	assert.Equal(t, len(model), r.Len())
+	assert.Equal(t, model, r.String())
+	checkBalanced(t, r.root)
+
+	for i := 0; i < 100; i++ {
+		from := rng.IntN(len(model) + 1)
+		to := from + rng.IntN(len(model)-from+1)
+		s, err := r.Slice(from, to)
+		assert.NoError(t, err)
+		assert.Equal(t, model[from:to], s)
+
+		if from < len(model) {
+			c, err := r.Index(from)
+			assert.NoError(t, err)
+			assert.Equal(t, model[from], c)
+		}
+	}
+}
+
+func TestLines(t *testing.T) {
+	text := strings.Repeat("first line\nsecond\n\nlast", 200)
+	r := New(text, Config{})
+	assert.Equal(t, strings.Count(text, "\n")+1, r.Lines())
+
+	for _, offset := range []int{0, 5, 11, 17, 18, 19, 2000, len(text)} {
+		line, column, err := r.Position(offset)
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Count(text[:offset], "\n"), line)
+		assert.Equal(t, offset-(strings.LastIndex(text[:offset], "\n")+1), column)
+
+		back, err := r.Offset(line, column)
+		assert.NoError(t, err)
+		assert.Equal(t, offset, back)
+	}
+
+	_, err := r.Offset(1, 7) // Past the end of "second"
+	assert.Equal(t, ErrIndexOutOfBounds, err)
+	_, err = r.Offset(r.Lines(), 0)
+	assert.Equal(t, ErrIndexOutOfBounds, err)
+	_, _, err = r.Position(len(text) + 1)
+	assert.Equal(t, ErrIndexOutOfBounds, err)
+
+	empty := New("", Config{})
+	assert.Equal(t, 1, empty.Lines())
+	offset, err := empty.Offset(0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, offset)
+}
+
+func TestRunes(t *testing.T) {
+	// Multi-byte runes end up split across leaves
+	text := strings.Repeat("aé😀", 1000) + "\xff" + "z" + "\xe2\x82"
+	r := New("", Config{})
+	for i := 0; i < len(text); i += 700 {
+		r.Insert(r.Len(), text[i:min(i+700, len(text))])
+	}
+	assert.Equal(t, text, r.String())
+
+	var offsets []int
+	var runes []rune
+	for offset, c := range r.Runes() {
+		offsets = append(offsets, offset)
+		runes = append(runes, c)
+	}
+	var expectedOffsets []int
+	var expectedRunes []rune
+	for i := 0; i < len(text); {
+		c, size := utf8.DecodeRuneInString(text[i:])
+		expectedOffsets = append(expectedOffsets, i)
+		expectedRunes = append(expectedRunes, c)
+		i += size
+	}
+	assert.Equal(t, expectedOffsets, offsets)
+	assert.Equal(t, expectedRunes, runes)
+
+	// Stopping early
+	count := 0
+	for range r.Runes() {
+		if count++; count == 10 {
+			break
+		}
+	}
+	assert.Equal(t, 10, count)
+}
+
+func TestReader(t *testing.T) {
+	text := strings.Repeat("0123456789", 500)
+	r := New(text, Config{})
+	reader := r.Reader()
+
+	// Modifications after creating the reader are not visible to it
+	r.Delete(0, 100)
+
+	head := make([]byte, 1500)
+	n, err := io.ReadFull(reader, head)
+	assert.NoError(t, err)
+	assert.Equal(t, 1500, n)
+	assert.Equal(t, text[:1500], string(head))
+
+	var rest bytes.Buffer
+	written, err := reader.WriteTo(&rest)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(text)-1500), written)
+	assert.Equal(t, text[1500:], rest.String())
+	_, err = reader.Read(head)
+	assert.Equal(t, io.EOF, err)
+
+	var all bytes.Buffer
+	written, err = r.WriteTo(&all)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(text)-100), written)
+	assert.Equal(t, text[100:], all.String())
+}
+
+func TestClone(t *testing.T) {
+	r := New("shared text", Config{})
+	c := r.Clone()
+	r.Insert(0, "changed ")
+	c.Delete(0, 7)
+	assert.Equal(t, "changed shared text", r.String())
+	assert.Equal(t, "text", c.String())
+}
+
+func TestMetrics(t *testing.T) {
+	r := New("abc", Config{MetricsEnabled: true})
+	r.Insert(1, "x")
+	r.Delete(0, 1)
+	r.Slice(0, 1)
+	assert.Equal(t, int64(1), r.insertCounter.Count())
+	assert.Equal(t, int64(1), r.deleteCounter.Count())
+	assert.Equal(t, int64(1), r.sliceCounter.Count())
+}
+
+const benchmarkSize = 1 << 20
+
+func BenchmarkDeleteMiddle(b *testing.B) {
+	r := New(strings.Repeat("x", benchmarkSize), Config{})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.Delete(r.Len()/2, 1)
+		r.Insert(r.Len()/2, "x")
+	}
+}
+
+// BenchmarkArrayDeleteMiddle is the baseline: array.Array shifts the tail on every Delete
+func BenchmarkArrayDeleteMiddle(b *testing.B) {
+	arr := array.NewArray[byte](benchmarkSize, array.ArrayConfig{})
+	for i := 0; i < benchmarkSize; i++ {
+		arr.Append('x')
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		arr.Delete(arr.Length() / 2)
+		arr.Append('x')
+	}
+}
@@ -0,0 +1,237 @@
+package segtree
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/rcrowley/go-metrics"
+	"github.com/vzahanych/data-structures/array"
+)
+
+// Errors returned by the tree
+var (
+	ErrIndexOutOfBounds = errors.New("index out of bounds")
+	ErrInvalidRange     = errors.New("invalid range")
+	ErrNoRangeUpdate    = errors.New("range updates are not configured")
+)
+
+type TreeConfig[T any] struct {
+	// Apply returns the aggregate of a segment of length n after applying update to it
+	// Apply and Compose are only required for range updates
+	Apply func(aggregate, update T, n int) T
+	// Compose merges two pending updates, newer being applied after older
+	Compose        func(older, newer T) T
+	MetricsEnabled bool
+}
+
+// Tree is a segment tree answering range queries over an associative combine function
+// Range updates are applied lazily: a pending update is stored on the highest
+// node that fully covers it and pushed down only when a query needs its children.
+type Tree[T any] struct {
+	size          int
+	nodes         []T
+	pending       []T
+	hasPending    []bool
+	combine       func(a, b T) T
+	identity      T
+	mu            sync.Mutex
+	config        TreeConfig[T]
+	queryCounter  metrics.Counter
+	updateCounter metrics.Counter
+}
+
+// New builds a tree over values
+// combine must be associative and identity must satisfy combine(identity, x) == x
+func New[T any](values []T, combine func(a, b T) T, identity T, config TreeConfig[T]) *Tree[T] {
+	t := &Tree[T]{
+		size:       len(values),
+		nodes:      make([]T, 4*max(len(values), 1)),
+		pending:    make([]T, 4*max(len(values), 1)),
+		hasPending: make([]bool, 4*max(len(values), 1)),
+		combine:    combine,
+		identity:   identity,
+		config:     config,
+	}
+	if t.size > 0 {
+		t.build(1, 0, t.size, values)
+	}
+
+	// Initialize the metrics only if enabled in the config
+	if t.config.MetricsEnabled {
+		t.queryCounter = metrics.NewCounter()
+		t.updateCounter = metrics.NewCounter()
+		metrics.DefaultRegistry.Register("segtree.query", t.queryCounter)
+		metrics.DefaultRegistry.Register("segtree.update", t.updateCounter)
+	}
+
+	return t
+}
+
+// FromArray builds a tree over the current elements of arr
+func FromArray[T any](arr *array.Array[T], combine func(a, b T) T, identity T, config TreeConfig[T]) *Tree[T] {
+	return New(arr.Values(), combine, identity, config)
+}
+
+// Len returns the number of elements covered by the tree
+func (t *Tree[T]) Len() int {
+	return t.size
+}
+
+// Get retrieves the element at the specified index
+func (t *Tree[T]) Get(index int) (T, error) {
+	return t.Query(index, index+1)
+}
+
+// Set replaces the element at the specified index
+func (t *Tree[T]) Set(index int, value T) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if index < 0 || index >= t.size {
+		return ErrIndexOutOfBounds
+	}
+	t.set(1, 0, t.size, index, value)
+
+	// Track metrics if enabled
+	if t.config.MetricsEnabled {
+		t.updateCounter.Inc(1)
+	}
+	return nil
+}
+
+// Query returns the combination of the elements in [from, to)
+// An empty range yields the identity
+func (t *Tree[T]) Query(from, to int) (T, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if from < 0 || to > t.size {
+		return t.identity, ErrIndexOutOfBounds
+	}
+	if from > to {
+		return t.identity, ErrInvalidRange
+	}
+
+	// Track metrics if enabled
+	if t.config.MetricsEnabled {
+		t.queryCounter.Inc(1)
+	}
+	if from == to {
+		return t.identity, nil
+	}
+	return t.query(1, 0, t.size, from, to), nil
+}
+
+// Update applies update to every element in [from, to)
+// It requires Apply and Compose to be set in the config
+func (t *Tree[T]) Update(from, to int, update T) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.config.Apply == nil || t.config.Compose == nil {
+		return ErrNoRangeUpdate
+	}
+	if from < 0 || to > t.size {
+		return ErrIndexOutOfBounds
+	}
+	if from > to {
+		return ErrInvalidRange
+	}
+	if from < to {
+		t.update(1, 0, t.size, from, to, update)
+	}
+
+	// Track metrics if enabled
+	if t.config.MetricsEnabled {
+		t.updateCounter.Inc(1)
+	}
+	return nil
+}
+
+// Node n covers [lo, hi), its children are 2n and 2n+1
+
+func (t *Tree[T]) build(n, lo, hi int, values []T) {
+	if hi-lo == 1 {
+		t.nodes[n] = values[lo]
+		return
+	}
+	mid := (lo + hi) / 2
+	t.build(2*n, lo, mid, values)
+	t.build(2*n+1, mid, hi, values)
+	t.nodes[n] = t.combine(t.nodes[2*n], t.nodes[2*n+1])
+}
+
+func (t *Tree[T]) set(n, lo, hi, index int, value T) {
+	if hi-lo == 1 {
+		t.nodes[n] = value
+		t.hasPending[n] = false
+		return
+	}
+	t.push(n, lo, hi)
+	mid := (lo + hi) / 2
+	if index < mid {
+		t.set(2*n, lo, mid, index, value)
+	} else {
+		t.set(2*n+1, mid, hi, index, value)
+	}
+	t.nodes[n] = t.combine(t.nodes[2*n], t.nodes[2*n+1])
+}
+
+func (t *Tree[T]) query(n, lo, hi, from, to int) T {
+	if from <= lo && hi <= to {
+		return t.nodes[n]
+	}
+	t.push(n, lo, hi)
+	mid := (lo + hi) / 2
+	switch {
+	case to <= mid:
+		return t.query(2*n, lo, mid, from, to)
+	case from >= mid:
+		return t.query(2*n+1, mid, hi, from, to)
+	default:
+		return t.combine(t.query(2*n, lo, mid, from, to), t.query(2*n+1, mid, hi, from, to))
+	}
+}
+
+func (t *Tree[T]) update(n, lo, hi, from, to int, update T) {
+	if from <= lo && hi <= to {
+		t.applyTo(n, lo, hi, update)
+		return
+	}
+	t.push(n, lo, hi)
+	mid := (lo + hi) / 2
+	if from < mid {
+		t.update(2*n, lo, mid, from, to, update)
+	}
+	if to > mid {
+		t.update(2*n+1, mid, hi, from, to, update)
+	}
+	t.nodes[n] = t.combine(t.nodes[2*n], t.nodes[2*n+1])
+}
+
+// applyTo updates the aggregate of node n and records the update for its children
+func (t *Tree[T]) applyTo(n, lo, hi int, update T) {
+	t.nodes[n] = t.config.Apply(t.nodes[n], update, hi-lo)
+	if hi-lo == 1 {
+		return
+	}
+	if t.hasPending[n] {
+		t.pending[n] = t.config.Compose(t.pending[n], update)
+	} else {
+		t.pending[n] = update
+		t.hasPending[n] = true
+	}
+}
+
+// push propagates the pending update of node n to its children
+func (t *Tree[T]) push(n, lo, hi int) {
+	if !t.hasPending[n] {
+		return
+	}
+	mid := (lo + hi) / 2
+	t.applyTo(2*n, lo, mid, t.pending[n])
+	t.applyTo(2*n+1, mid, hi, t.pending[n])
+	var zero T
+	t.pending[n] = zero
+	t.hasPending[n] = false
+}
@@ -0,0 +1,147 @@
+package segtree
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vzahanych/data-structures/array"
+)
+
+func sum(a, b int) int { return a + b }
+
+// Range add over sums: every element in a segment of length n grows by update
+func sumConfig() TreeConfig[int] {
+	return TreeConfig[int]{
+		Apply:   func(aggregate, update, n int) int { return aggregate + update*n },
+		Compose: func(older, newer int) int { return older + newer },
+	}
+}
+
+func TestQuery(t *testing.T) {
+	tree := New([]int{1, 2, 3, 4, 5}, sum, 0, TreeConfig[int]{})
+
+	value, err := tree.Query(0, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 15, value)
+
+	value, err = tree.Query(1, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, value)
+
+	value, err = tree.Query(2, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, value)
+
+	_, err = tree.Query(0, 6)
+	assert.Error(t, err)
+	assert.Equal(t, "index out of bounds", err.Error())
+
+	_, err = tree.Query(3, 1)
+	assert.Equal(t, ErrInvalidRange, err)
+}
+
+func TestGetSet(t *testing.T) {
+	tree := New([]int{1, 2, 3}, sum, 0, TreeConfig[int]{})
+
+	assert.NoError(t, tree.Set(1, 10))
+	value, err := tree.Get(1)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, value)
+
+	value, _ = tree.Query(0, 3)
+	assert.Equal(t, 14, value)
+
+	_, err = tree.Get(3)
+	assert.Equal(t, ErrIndexOutOfBounds, err)
+	assert.Equal(t, ErrIndexOutOfBounds, tree.Set(-1, 0))
+}
+
+func TestMinQuery(t *testing.T) {
+	minimum := func(a, b int) int { return min(a, b) }
+	tree := New([]int{5, 3, 8, 1, 9}, minimum, math.MaxInt, TreeConfig[int]{})
+
+	value, _ := tree.Query(0, 3)
+	assert.Equal(t, 3, value)
+	value, _ = tree.Query(2, 5)
+	assert.Equal(t, 1, value)
+}
+
+func TestRangeUpdate(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	values := make([]int, 100)
+	for i := range values {
+		values[i] = rng.IntN(100)
+	}
+	tree := New(append([]int(nil), values...), sum, 0, sumConfig())
+
+	// Compare against a plain slice after random updates and queries
+	for round := 0; round < 500; round++ {
+		from := rng.IntN(len(values))
+		to := from + rng.IntN(len(values)-from+1)
+		if rng.IntN(2) == 0 {
+			delta := rng.IntN(21) - 10
+			assert.NoError(t, tree.Update(from, to, delta))
+			for i := from; i < to; i++ {
+				values[i] += delta
+			}
+		} else {
+			expected := 0
+			for i := from; i < to; i++ {
+				expected += values[i]
+			}
+			got, err := tree.Query(from, to)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, got)
+		}
+	}
+}
+
+func TestUpdateWithoutApply(t *testing.T) {
+	tree := New([]int{1, 2, 3}, sum, 0, TreeConfig[int]{})
+	assert.Equal(t, ErrNoRangeUpdate, tree.Update(0, 3, 1))
+}
+
+func TestFromArray(t *testing.T) {
+	arr := array.NewArray[int](5, array.ArrayConfig{MetricsEnabled: false})
+	arr.Append(4)
+	arr.Append(5)
+	arr.Append(6)
+
+	tree := FromArray(arr, sum, 0, TreeConfig[int]{})
+	assert.Equal(t, 3, tree.Len())
+	value, _ := tree.Query(0, 3)
+	assert.Equal(t, 15, value)
+}
+
+func TestEmpty(t *testing.T) {
+	tree := New(nil, sum, 0, TreeConfig[int]{})
+
+	value, err := tree.Query(0, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, value)
+
+	_, err = tree.Get(0)
+	assert.Equal(t, ErrIndexOutOfBounds, err)
+}
+
+func BenchmarkQuery(b *testing.B) {
+	values := make([]int, 100000)
+	tree := New(values, sum, 0, sumConfig())
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Query(i%50000, 50000+i%50000)
+	}
+}
+
+func BenchmarkUpdate(b *testing.B) {
+	values := make([]int, 100000)
+	tree := New(values, sum, 0, sumConfig())
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Update(i%50000, 50000+i%50000, 1)
+	}
+}
@@ -0,0 +1,146 @@
+package skiplist
+
+import (
+	"cmp"
+	"iter"
+	"math/rand/v2"
+)
+
+const maxLevel = 32
+
+type node[K cmp.Ordered, V any] struct {
+	key   K
+	value V
+	next  []*node[K, V]
+}
+
+// Map is an ordered map implemented as a skip list
+// Lookups, insertions and deletions run in expected O(log n) and iteration
+// follows key order. A Map is not safe for concurrent use; callers that share
+// one between goroutines must provide their own locking.
+type Map[K cmp.Ordered, V any] struct {
+	head  node[K, V]
+	level int
+	size  int
+}
+
+// New creates an empty map
+func New[K cmp.Ordered, V any]() *Map[K, V] {
+	return &Map[K, V]{head: node[K, V]{next: make([]*node[K, V], maxLevel)}, level: 1}
+}
+
+// Len returns the number of entries
+func (m *Map[K, V]) Len() int {
+	return m.size
+}
+
+// Get returns the value stored for key
+func (m *Map[K, V]) Get(key K) (V, bool) {
+	if n := m.seek(key); n != nil && n.key == key {
+		return n.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Set stores value for key, replacing any previous value
+func (m *Map[K, V]) Set(key K, value V) {
+	var update [maxLevel]*node[K, V]
+	n := m.predecessors(key, &update)
+	if n != nil && n.key == key {
+		n.value = value
+		return
+	}
+
+	level := randomLevel()
+	for ; m.level < level; m.level++ {
+		update[m.level] = &m.head
+	}
+	n = &node[K, V]{key: key, value: value, next: make([]*node[K, V], level)}
+	for i := 0; i < level; i++ {
+		n.next[i] = update[i].next[i]
+		update[i].next[i] = n
+	}
+	m.size++
+}
+
+// Delete removes key and reports whether it was present
+func (m *Map[K, V]) Delete(key K) bool {
+	var update [maxLevel]*node[K, V]
+	n := m.predecessors(key, &update)
+	if n == nil || n.key != key {
+		return false
+	}
+	for i := 0; i < len(n.next); i++ {
+		update[i].next[i] = n.next[i]
+	}
+	for m.level > 1 && m.head.next[m.level-1] == nil {
+		m.level--
+	}
+	m.size--
+	return true
+}
+
+// Min returns the entry with the smallest key
+func (m *Map[K, V]) Min() (K, V, bool) {
+	if n := m.head.next[0]; n != nil {
+		return n.key, n.value, true
+	}
+	var key K
+	var value V
+	return key, value, false
+}
+
+// All returns an iterator over the entries in key order
+func (m *Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for n := m.head.next[0]; n != nil; n = n.next[0] {
+			if !yield(n.key, n.value) {
+				return
+			}
+		}
+	}
+}
+
+// Range returns an iterator over the entries whose keys are in [from, to), in key order
+func (m *Map[K, V]) Range(from, to K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for n := m.seek(from); n != nil && n.key < to; n = n.next[0] {
+			if !yield(n.key, n.value) {
+				return
+			}
+		}
+	}
+}
+
+// seek returns the first node whose key is not less than key
+func (m *Map[K, V]) seek(key K) *node[K, V] {
+	x := &m.head
+	for i := m.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && x.next[i].key < key {
+			x = x.next[i]
+		}
+	}
+	return x.next[0]
+}
+
+// predecessors fills update with the last node before key on every level and returns the node following it
+func (m *Map[K, V]) predecessors(key K, update *[maxLevel]*node[K, V]) *node[K, V] {
+	x := &m.head
+	for i := m.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && x.next[i].key < key {
+			x = x.next[i]
+		}
+		update[i] = x
+	}
+	return x.next[0]
+}
+
+// randomLevel draws a level with probability 1/4 of going one level higher
+func randomLevel() int {
+	level := 1
+	for r := rand.Uint64(); level < maxLevel && r&3 == 0; r >>= 2 {
+		level++
+	}
+	return level
+}
@@ -0,0 +1,120 @@
+package skiplist
+
+import (
+	"iter"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func keys[K, V any](seq iter.Seq2[K, V]) []K {
+	var result []K
+	for k := range seq {
+		result = append(result, k)
+	}
+	return result
+}
+
+func TestSetGetDelete(t *testing.T) {
+	m := New[string, int]()
+	m.Set("b", 2)
+	m.Set("a", 1)
+	m.Set("c", 3)
+	m.Set("b", 20)
+	assert.Equal(t, 3, m.Len())
+
+	value, found := m.Get("b")
+	assert.True(t, found)
+	assert.Equal(t, 20, value)
+	_, found = m.Get("d")
+	assert.False(t, found)
+
+	assert.True(t, m.Delete("a"))
+	assert.False(t, m.Delete("a"))
+	assert.Equal(t, []string{"b", "c"}, keys(m.All()))
+
+	key, value, found := m.Min()
+	assert.True(t, found)
+	assert.Equal(t, "b", key)
+	assert.Equal(t, 20, value)
+}
+
+func TestRange(t *testing.T) {
+	m := New[int, int]()
+	for i := 0; i < 100; i += 10 {
+		m.Set(i, i)
+	}
+	assert.Equal(t, []int{20, 30, 40}, keys(m.Range(15, 50)))
+	assert.Equal(t, []int{0, 10}, keys(m.Range(-5, 11)))
+	assert.Empty(t, keys(m.Range(91, 200)))
+
+	// Stopping early
+	count := 0
+	for range m.All() {
+		count++
+		if count == 3 {
+			break
+		}
+	}
+	assert.Equal(t, 3, count)
+}
+
+func TestRandomized(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	m := New[int, int]()
+	model := make(map[int]int)
+
+	for round := 0; round < 20000; round++ {
+		key := rng.IntN(1000)
+		if rng.IntN(3) == 0 {
+			_, present := model[key]
+			assert.Equal(t, present, m.Delete(key))
+			delete(model, key)
+		} else {
+			m.Set(key, round)
+			model[key] = round
+		}
+	}
+	assert.Equal(t, len(model), m.Len())
+
+	expected := make([]int, 0, len(model))
+	for k := range model {
+		expected = append(expected, k)
+	}
+	slices.Sort(expected)
+	assert.Equal(t, expected, keys(m.All()))
+	for k, v := range m.All() {
+		assert.Equal(t, model[k], v)
+	}
+
+	// Emptying the map
+	for _, k := range expected {
+		assert.True(t, m.Delete(k))
+	}
+	assert.Equal(t, 0, m.Len())
+	_, _, found := m.Min()
+	assert.False(t, found)
+}
+
+func BenchmarkSet(b *testing.B) {
+	m := New[int, int]()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Set(i*7919%1000003, i)
+	}
+}
+
+func BenchmarkGet(b *testing.B) {
+	m := New[int, int]()
+	for i := 0; i < 100000; i++ {
+		m.Set(i, i)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Get(i % 100000)
+	}
+}
@@ -0,0 +1,226 @@
+package spatial
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+	"sync"
+	"time"
+)
+
+type KDTreeConfig struct {
+	MetricsEnabled bool
+}
+
+type kdnode[T any] struct {
+	entry       Entry[T]
+	axis        int
+	left, right *kdnode[T] // left holds coordinates below the node on its axis
+}
+
+// KDTree is a k-d tree of points supporting nearest neighbour and radius search
+// Trees built with BuildKDTree are balanced; later inserts are not rebalanced.
+type KDTree[T any] struct {
+	root    *kdnode[T]
+	dims    int
+	size    int
+	mu      sync.RWMutex
+	config  KDTreeConfig
+	metrics *indexMetrics
+}
+
+var _ Index[int] = (*KDTree[int])(nil)
+
+// NewKDTree creates an empty tree of points with dims coordinates
+// The config parameter is used to enable or disable metrics collection.
+// It returns ErrInvalidDimensions if dims is less than 1.
+func NewKDTree[T any](dims int, config KDTreeConfig) (*KDTree[T], error) {
+	if dims < 1 {
+		return nil, ErrInvalidDimensions
+	}
+	return &KDTree[T]{
+		dims:    dims,
+		config:  config,
+		metrics: newIndexMetrics("kdtree", config.MetricsEnabled),
+	}, nil
+}
+
+// BuildKDTree creates a balanced tree from point entries
+func BuildKDTree[T any](dims int, entries []Entry[T], config KDTreeConfig) (*KDTree[T], error) {
+	t, err := NewKDTree[T](dims, config)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if err := checkRect(e.Bounds, dims); err != nil {
+			return nil, err
+		}
+		if !e.Bounds.IsPoint() {
+			return nil, ErrNotAPoint
+		}
+	}
+	t.root = t.build(slices.Clone(entries), 0)
+	t.size = len(entries)
+	return t, nil
+}
+
+// build creates a subtree splitting on the median of each level's axis
+func (t *KDTree[T]) build(entries []Entry[T], depth int) *kdnode[T] {
+	if len(entries) == 0 {
+		return nil
+	}
+	axis := depth % t.dims
+	slices.SortFunc(entries, func(a, b Entry[T]) int {
+		return cmp.Compare(a.Bounds.Min[axis], b.Bounds.Min[axis])
+	})
+	median := len(entries) / 2
+	// Equal coordinates must go right, as Insert does
+	for median > 0 && entries[median-1].Bounds.Min[axis] == entries[median].Bounds.Min[axis] {
+		median--
+	}
+	return &kdnode[T]{
+		entry: entries[median],
+		axis:  axis,
+		left:  t.build(entries[:median], depth+1),
+		right: t.build(entries[median+1:], depth+1),
+	}
+}
+
+// Insert adds a value at the point described by bounds
+func (t *KDTree[T]) Insert(bounds Rect, value T) error {
+	if err := checkRect(bounds, t.dims); err != nil {
+		return err
+	}
+	if !bounds.IsPoint() {
+		return ErrNotAPoint
+	}
+
+	t.mu.Lock() // Lock for writing
+	defer t.mu.Unlock()
+
+	entry := Entry[T]{Bounds: bounds, Value: value}
+	link := &t.root
+	depth := 0
+	for *link != nil {
+		n := *link
+		if bounds.Min[n.axis] < n.entry.Bounds.Min[n.axis] {
+			link = &n.left
+		} else {
+			link = &n.right
+		}
+		depth++
+	}
+	*link = &kdnode[T]{entry: entry, axis: depth % t.dims}
+	t.size++
+	t.metrics.insert()
+	return nil
+}
+
+// InsertPoint adds a value at p
+func (t *KDTree[T]) InsertPoint(p Point, value T) error {
+	return t.Insert(p.Rect(), value)
+}
+
+// Search returns an iterator over the points inside query
+// The tree is read-locked while the iteration runs, so the loop body must not modify the tree.
+func (t *KDTree[T]) Search(query Rect) iter.Seq[Entry[T]] {
+	return func(yield func(Entry[T]) bool) {
+		if checkRect(query, t.dims) != nil {
+			return
+		}
+		t.mu.RLock() // Lock for reading
+		defer t.mu.RUnlock()
+		defer t.metrics.query(time.Now())
+
+		var search func(n *kdnode[T]) bool
+		search = func(n *kdnode[T]) bool {
+			if n == nil {
+				return true
+			}
+			coordinate := n.entry.Bounds.Min[n.axis]
+			if query.Min[n.axis] < coordinate && !search(n.left) {
+				return false
+			}
+			if query.Contains(n.entry.Bounds.Min) && !yield(n.entry) {
+				return false
+			}
+			if query.Max[n.axis] >= coordinate {
+				return search(n.right)
+			}
+			return true
+		}
+		search(t.root)
+	}
+}
+
+// Within returns an iterator over the points at distance radius or less from p
+// The tree is read-locked while the iteration runs, so the loop body must not modify the tree.
+func (t *KDTree[T]) Within(p Point, radius float64) iter.Seq[Entry[T]] {
+	return func(yield func(Entry[T]) bool) {
+		if len(p) != t.dims {
+			return
+		}
+		t.mu.RLock() // Lock for reading
+		defer t.mu.RUnlock()
+		defer t.metrics.query(time.Now())
+
+		radius2 := radius * radius
+		var search func(n *kdnode[T]) bool
+		search = func(n *kdnode[T]) bool {
+			if n == nil {
+				return true
+			}
+			diff := p[n.axis] - n.entry.Bounds.Min[n.axis]
+			if diff-radius < 0 && !search(n.left) {
+				return false
+			}
+			if n.entry.Bounds.distance2(p) <= radius2 && !yield(n.entry) {
+				return false
+			}
+			if diff+radius >= 0 {
+				return search(n.right)
+			}
+			return true
+		}
+		search(t.root)
+	}
+}
+
+// Nearest returns up to k points ordered by increasing distance to p
+func (t *KDTree[T]) Nearest(p Point, k int) []Entry[T] {
+	if len(p) != t.dims {
+		return nil
+	}
+	t.mu.RLock() // Lock for reading
+	defer t.mu.RUnlock()
+	defer t.metrics.query(time.Now())
+
+	if t.root == nil {
+		return nil
+	}
+	// A subtree on the far side of a splitting plane is at least as far as the plane
+	return bestFirst(t.root, k, func(c candidate[T], push func(candidate[T])) {
+		n := c.node.(*kdnode[T])
+		push(candidate[T]{distance2: n.entry.Bounds.distance2(p), entry: n.entry})
+
+		diff := p[n.axis] - n.entry.Bounds.Min[n.axis]
+		near, far := n.right, n.left
+		if diff < 0 {
+			near, far = n.left, n.right
+		}
+		if near != nil {
+			push(candidate[T]{distance2: c.distance2, node: near})
+		}
+		if far != nil {
+			push(candidate[T]{distance2: max(c.distance2, diff*diff), node: far})
+		}
+	})
+}
+
+// Len returns the number of stored points
+func (t *KDTree[T]) Len() int {
+	t.mu.RLock() // Lock for reading
+	defer t.mu.RUnlock()
+
+	return t.size
+}
@@ -0,0 +1,128 @@
+package spatial
+
+import (
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func randomPoints(rng *rand.Rand, n, dims int) []Entry[int] {
+	entries := make([]Entry[int], n)
+	for i := range entries {
+		p := make(Point, dims)
+		for d := range p {
+			p[d] = rng.Float64() * 100
+		}
+		entries[i] = Entry[int]{Bounds: p.Rect(), Value: i}
+	}
+	return entries
+}
+
+// bruteNearest returns the k closest entries to p by linear scan
+func bruteNearest(entries []Entry[int], p Point, k int) []float64 {
+	distances := make([]float64, len(entries))
+	for i, e := range entries {
+		distances[i] = e.Bounds.Distance(p)
+	}
+	sort.Float64s(distances)
+	return distances[:min(k, len(distances))]
+}
+
+func distancesTo(entries []Entry[int], p Point) []float64 {
+	distances := make([]float64, len(entries))
+	for i, e := range entries {
+		distances[i] = e.Bounds.Distance(p)
+	}
+	return distances
+}
+
+func values(seq func(func(Entry[int]) bool)) []int {
+	var result []int
+	seq(func(e Entry[int]) bool {
+		result = append(result, e.Value)
+		return true
+	})
+	slices.Sort(result)
+	return result
+}
+
+func TestKDTreeNearest(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	entries := randomPoints(rng, 1000, 3)
+	tree, err := BuildKDTree(3, entries, KDTreeConfig{MetricsEnabled: false})
+	assert.NoError(t, err)
+	assert.Equal(t, 1000, tree.Len())
+
+	for i := 0; i < 50; i++ {
+		p := Point{rng.Float64() * 100, rng.Float64() * 100, rng.Float64() * 100}
+		assert.Equal(t, bruteNearest(entries, p, 5), distancesTo(tree.Nearest(p, 5), p))
+	}
+}
+
+func TestKDTreeInsertAndSearch(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+	entries := randomPoints(rng, 500, 2)
+	tree, err := NewKDTree[int](2, KDTreeConfig{})
+	assert.NoError(t, err)
+	for _, e := range entries {
+		assert.NoError(t, tree.Insert(e.Bounds, e.Value))
+	}
+
+	query := Rect{Min: Point{20, 30}, Max: Point{60, 50}}
+	var expected []int
+	for _, e := range entries {
+		if query.Contains(e.Bounds.Min) {
+			expected = append(expected, e.Value)
+		}
+	}
+	assert.Equal(t, expected, values(tree.Search(query)))
+
+	p := Point{50, 50}
+	assert.Equal(t, bruteNearest(entries, p, 3), distancesTo(tree.Nearest(p, 3), p))
+}
+
+func TestKDTreeWithin(t *testing.T) {
+	rng := rand.New(rand.NewPCG(5, 6))
+	entries := randomPoints(rng, 500, 2)
+	tree, _ := BuildKDTree(2, entries, KDTreeConfig{})
+
+	p := Point{40, 60}
+	var expected []int
+	for _, e := range entries {
+		if e.Bounds.Distance(p) <= 10 {
+			expected = append(expected, e.Value)
+		}
+	}
+	assert.NotEmpty(t, expected)
+	assert.Equal(t, expected, values(tree.Within(p, 10)))
+}
+
+func TestKDTreeInvalidInput(t *testing.T) {
+	tree, _ := NewKDTree[int](2, KDTreeConfig{})
+	assert.Equal(t, ErrDimensionMismatch, tree.InsertPoint(Point{1, 2, 3}, 0))
+	assert.Equal(t, ErrNotAPoint, tree.Insert(Rect{Min: Point{0, 0}, Max: Point{1, 1}}, 0))
+	assert.Nil(t, tree.Nearest(Point{1}, 1))
+	assert.Nil(t, tree.Nearest(Point{1, 1}, 1))
+}
+
+func TestKDTreeInvalidDimensions(t *testing.T) {
+	for _, dims := range []int{0, -1} {
+		_, err := BuildKDTree[int](dims, nil, KDTreeConfig{})
+		assert.Equal(t, ErrInvalidDimensions, err)
+		_, err = NewKDTree[int](dims, KDTreeConfig{})
+		assert.Equal(t, ErrInvalidDimensions, err)
+	}
+}
+
+func BenchmarkKDTreeNearest(b *testing.B) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	tree, _ := BuildKDTree(2, randomPoints(rng, 100000, 2), KDTreeConfig{})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.Nearest(Point{float64(i % 100), float64(i % 97)}, 10)
+	}
+}
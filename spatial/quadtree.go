@@ -0,0 +1,187 @@
+package spatial
+
+import (
+	"iter"
+	"sync"
+	"time"
+)
+
+const (
+	defaultNodeCapacity = 8
+	defaultMaxDepth     = 16
+)
+
+type QuadTreeConfig struct {
+	// NodeCapacity is the number of points a leaf holds before splitting (default 8)
+	NodeCapacity int
+	// MaxDepth bounds the depth of the tree so that many equal points cannot split forever (default 16)
+	MaxDepth       int
+	MetricsEnabled bool
+}
+
+type qnode[T any] struct {
+	bounds   Rect
+	entries  []Entry[T]
+	children *[4]*qnode[T] // nil for leaves
+}
+
+// QuadTree is a point quadtree over a fixed 2-D region
+type QuadTree[T any] struct {
+	root    *qnode[T]
+	size    int
+	mu      sync.RWMutex
+	config  QuadTreeConfig
+	metrics *indexMetrics
+}
+
+var _ Index[int] = (*QuadTree[int])(nil)
+
+// NewQuadTree creates an empty quadtree covering bounds, which must be 2-D
+// The config parameter selects the node capacity and enables metrics collection
+func NewQuadTree[T any](bounds Rect, config QuadTreeConfig) (*QuadTree[T], error) {
+	if err := checkRect(bounds, 2); err != nil {
+		return nil, err
+	}
+	if config.NodeCapacity <= 0 {
+		config.NodeCapacity = defaultNodeCapacity
+	}
+	if config.MaxDepth <= 0 {
+		config.MaxDepth = defaultMaxDepth
+	}
+	return &QuadTree[T]{
+		root:    &qnode[T]{bounds: bounds},
+		config:  config,
+		metrics: newIndexMetrics("quadtree", config.MetricsEnabled),
+	}, nil
+}
+
+// Insert adds a value at the point described by bounds
+// It returns ErrOutOfBounds for points outside the region of the tree
+func (t *QuadTree[T]) Insert(bounds Rect, value T) error {
+	if err := checkRect(bounds, 2); err != nil {
+		return err
+	}
+	if !bounds.IsPoint() {
+		return ErrNotAPoint
+	}
+
+	t.mu.Lock() // Lock for writing
+	defer t.mu.Unlock()
+
+	if !t.root.bounds.Contains(bounds.Min) {
+		return ErrOutOfBounds
+	}
+
+	entry := Entry[T]{Bounds: bounds, Value: value}
+	n := t.root
+	depth := 0
+	for n.children != nil {
+		n = n.children[quadrant(n.bounds, bounds.Min)]
+		depth++
+	}
+	n.entries = append(n.entries, entry)
+	if len(n.entries) > t.config.NodeCapacity && depth < t.config.MaxDepth {
+		t.split(n)
+	}
+
+	t.size++
+	t.metrics.insert()
+	return nil
+}
+
+// InsertPoint adds a value at p
+func (t *QuadTree[T]) InsertPoint(p Point, value T) error {
+	return t.Insert(p.Rect(), value)
+}
+
+// split turns a leaf into an inner node and distributes its points to four children
+func (t *QuadTree[T]) split(n *qnode[T]) {
+	midX, midY := n.bounds.center(0), n.bounds.center(1)
+	n.children = &[4]*qnode[T]{
+		{bounds: Rect{Min: Point{n.bounds.Min[0], n.bounds.Min[1]}, Max: Point{midX, midY}}},
+		{bounds: Rect{Min: Point{midX, n.bounds.Min[1]}, Max: Point{n.bounds.Max[0], midY}}},
+		{bounds: Rect{Min: Point{n.bounds.Min[0], midY}, Max: Point{midX, n.bounds.Max[1]}}},
+		{bounds: Rect{Min: Point{midX, midY}, Max: Point{n.bounds.Max[0], n.bounds.Max[1]}}},
+	}
+	for _, e := range n.entries {
+		child := n.children[quadrant(n.bounds, e.Bounds.Min)]
+		child.entries = append(child.entries, e)
+	}
+	n.entries = nil
+}
+
+// quadrant returns the index of the child of a node with the given bounds that holds p
+func quadrant(bounds Rect, p Point) int {
+	index := 0
+	if p[0] >= bounds.center(0) {
+		index |= 1
+	}
+	if p[1] >= bounds.center(1) {
+		index |= 2
+	}
+	return index
+}
+
+// Search returns an iterator over the points inside query
+// The tree is read-locked while the iteration runs, so the loop body must not modify the tree.
+func (t *QuadTree[T]) Search(query Rect) iter.Seq[Entry[T]] {
+	return func(yield func(Entry[T]) bool) {
+		if checkRect(query, 2) != nil {
+			return
+		}
+		t.mu.RLock() // Lock for reading
+		defer t.mu.RUnlock()
+		defer t.metrics.query(time.Now())
+
+		var search func(n *qnode[T]) bool
+		search = func(n *qnode[T]) bool {
+			if !n.bounds.Intersects(query) {
+				return true
+			}
+			for _, e := range n.entries {
+				if query.Contains(e.Bounds.Min) && !yield(e) {
+					return false
+				}
+			}
+			if n.children != nil {
+				for _, child := range n.children {
+					if !search(child) {
+						return false
+					}
+				}
+			}
+			return true
+		}
+		search(t.root)
+	}
+}
+
+// Nearest returns up to k points ordered by increasing distance to p
+func (t *QuadTree[T]) Nearest(p Point, k int) []Entry[T] {
+	if len(p) != 2 {
+		return nil
+	}
+	t.mu.RLock() // Lock for reading
+	defer t.mu.RUnlock()
+	defer t.metrics.query(time.Now())
+
+	return bestFirst(t.root, k, func(c candidate[T], push func(candidate[T])) {
+		n := c.node.(*qnode[T])
+		for _, e := range n.entries {
+			push(candidate[T]{distance2: e.Bounds.distance2(p), entry: e})
+		}
+		if n.children != nil {
+			for _, child := range n.children {
+				push(candidate[T]{distance2: child.bounds.distance2(p), node: child})
+			}
+		}
+	})
+}
+
+// Len returns the number of stored points
+func (t *QuadTree[T]) Len() int {
+	t.mu.RLock() // Lock for reading
+	defer t.mu.RUnlock()
+
+	return t.size
+}
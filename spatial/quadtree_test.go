@@ -0,0 +1,62 @@
+package spatial
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuadTreeSearchAndNearest(t *testing.T) {
+	rng := rand.New(rand.NewPCG(7, 8))
+	entries := randomPoints(rng, 2000, 2)
+	tree, err := NewQuadTree[int](Rect{Min: Point{0, 0}, Max: Point{100, 100}}, QuadTreeConfig{MetricsEnabled: false})
+	assert.NoError(t, err)
+	for _, e := range entries {
+		assert.NoError(t, tree.Insert(e.Bounds, e.Value))
+	}
+	assert.Equal(t, 2000, tree.Len())
+
+	query := Rect{Min: Point{10, 10}, Max: Point{35, 80}}
+	var expected []int
+	for _, e := range entries {
+		if query.Contains(e.Bounds.Min) {
+			expected = append(expected, e.Value)
+		}
+	}
+	assert.Equal(t, expected, values(tree.Search(query)))
+
+	for i := 0; i < 50; i++ {
+		p := Point{rng.Float64() * 120, rng.Float64() * 120}
+		assert.Equal(t, bruteNearest(entries, p, 4), distancesTo(tree.Nearest(p, 4), p))
+	}
+}
+
+func TestQuadTreeDuplicatePoints(t *testing.T) {
+	tree, _ := NewQuadTree[int](Rect{Min: Point{0, 0}, Max: Point{1, 1}}, QuadTreeConfig{NodeCapacity: 2})
+
+	// Equal points cannot be separated, MaxDepth stops the splitting
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, tree.InsertPoint(Point{0.5, 0.5}, i))
+	}
+	assert.Len(t, values(tree.Search(Point{0.5, 0.5}.Rect())), 100)
+}
+
+func TestQuadTreeInvalidInput(t *testing.T) {
+	_, err := NewQuadTree[int](Rect{Min: Point{0}, Max: Point{1}}, QuadTreeConfig{})
+	assert.Equal(t, ErrDimensionMismatch, err)
+
+	tree, _ := NewQuadTree[int](Rect{Min: Point{0, 0}, Max: Point{1, 1}}, QuadTreeConfig{})
+	assert.Equal(t, ErrOutOfBounds, tree.InsertPoint(Point{2, 0}, 0))
+	assert.Equal(t, ErrNotAPoint, tree.Insert(Rect{Min: Point{0, 0}, Max: Point{1, 1}}, 0))
+}
+
+func BenchmarkQuadTreeInsert(b *testing.B) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	tree, _ := NewQuadTree[int](Rect{Min: Point{0, 0}, Max: Point{100, 100}}, QuadTreeConfig{})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree.InsertPoint(Point{rng.Float64() * 100, rng.Float64() * 100}, i)
+	}
+}
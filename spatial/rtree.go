@@ -0,0 +1,288 @@
+package spatial
+
+import (
+	"cmp"
+	"iter"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+const defaultMaxEntries = 16
+
+type RTreeConfig struct {
+	// MaxEntries is the maximum number of entries per node (default 16)
+	MaxEntries     int
+	MetricsEnabled bool
+}
+
+// rentry is a slot of an R-tree node: a child node in inner nodes, a value in leaves
+type rentry[T any] struct {
+	bounds Rect
+	child  *rnode[T]
+	value  T
+}
+
+type rnode[T any] struct {
+	leaf    bool
+	entries []rentry[T]
+}
+
+func (n *rnode[T]) bounds() Rect {
+	b := n.entries[0].bounds
+	for _, e := range n.entries[1:] {
+		b = b.Union(e.bounds)
+	}
+	return b
+}
+
+// RTree is an R-tree of rectangles supporting intersection and nearest neighbour search
+type RTree[T any] struct {
+	root    *rnode[T]
+	dims    int
+	size    int
+	mu      sync.RWMutex
+	config  RTreeConfig
+	metrics *indexMetrics
+}
+
+var _ Index[int] = (*RTree[int])(nil)
+
+// NewRTree creates an empty tree of rectangles with dims coordinates
+// The config parameter selects the node size and enables metrics collection.
+// It returns ErrInvalidDimensions if dims is less than 1.
+func NewRTree[T any](dims int, config RTreeConfig) (*RTree[T], error) {
+	if dims < 1 {
+		return nil, ErrInvalidDimensions
+	}
+	if config.MaxEntries < 2 {
+		config.MaxEntries = defaultMaxEntries
+	}
+	return &RTree[T]{
+		root:    &rnode[T]{leaf: true},
+		dims:    dims,
+		config:  config,
+		metrics: newIndexMetrics("rtree", config.MetricsEnabled),
+	}, nil
+}
+
+// BulkLoad creates a tree from entries using Sort-Tile-Recursive packing
+// Packed trees have nearly full nodes and little overlap, which makes them
+// faster to search than trees built by repeated inserts.
+func BulkLoad[T any](dims int, entries []Entry[T], config RTreeConfig) (*RTree[T], error) {
+	t, err := NewRTree[T](dims, config)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return t, nil
+	}
+
+	level := make([]rentry[T], len(entries))
+	for i, e := range entries {
+		if err := checkRect(e.Bounds, dims); err != nil {
+			return nil, err
+		}
+		level[i] = rentry[T]{bounds: e.Bounds, value: e.Value}
+	}
+
+	leaf := true
+	for {
+		var nodes []rentry[T]
+		for _, group := range t.tile(level, 0) {
+			n := &rnode[T]{leaf: leaf, entries: group}
+			nodes = append(nodes, rentry[T]{bounds: n.bounds(), child: n})
+		}
+		if len(nodes) == 1 {
+			t.root = nodes[0].child
+			break
+		}
+		level = nodes
+		leaf = false
+	}
+	t.size = len(entries)
+	return t, nil
+}
+
+// tile sorts entries into slabs along dim and recurses on the next dimension
+// until groups of at most MaxEntries entries remain.
+func (t *RTree[T]) tile(entries []rentry[T], dim int) [][]rentry[T] {
+	capacity := t.config.MaxEntries
+	slices.SortFunc(entries, func(a, b rentry[T]) int {
+		return cmp.Compare(a.bounds.center(dim), b.bounds.center(dim))
+	})
+
+	if dim == t.dims-1 {
+		var groups [][]rentry[T]
+		for len(entries) > 0 {
+			n := min(capacity, len(entries))
+			groups = append(groups, entries[:n:n])
+			entries = entries[n:]
+		}
+		return groups
+	}
+
+	// Split into S slabs so that each remaining dimension gets the same number of cuts
+	pages := math.Ceil(float64(len(entries)) / float64(capacity))
+	slabs := math.Ceil(math.Pow(pages, 1/float64(t.dims-dim)))
+	slabSize := capacity * int(math.Ceil(pages/slabs))
+
+	var groups [][]rentry[T]
+	for len(entries) > 0 {
+		n := min(slabSize, len(entries))
+		groups = append(groups, t.tile(entries[:n], dim+1)...)
+		entries = entries[n:]
+	}
+	return groups
+}
+
+// Insert adds a value with the given bounds
+func (t *RTree[T]) Insert(bounds Rect, value T) error {
+	if err := checkRect(bounds, t.dims); err != nil {
+		return err
+	}
+
+	t.mu.Lock() // Lock for writing
+	defer t.mu.Unlock()
+
+	entry := rentry[T]{bounds: bounds, value: value}
+	if sibling := t.insert(t.root, entry); sibling != nil {
+		// The root was split: grow the tree by one level
+		t.root = &rnode[T]{entries: []rentry[T]{
+			{bounds: t.root.bounds(), child: t.root},
+			{bounds: sibling.bounds(), child: sibling},
+		}}
+	}
+	t.size++
+	t.metrics.insert()
+	return nil
+}
+
+// insert adds entry below n and returns the new sibling of n if n had to be split
+func (t *RTree[T]) insert(n *rnode[T], entry rentry[T]) *rnode[T] {
+	if n.leaf {
+		n.entries = append(n.entries, entry)
+	} else {
+		i := chooseSubtree(n, entry.bounds)
+		child := n.entries[i].child
+		sibling := t.insert(child, entry)
+		n.entries[i].bounds = child.bounds()
+		if sibling != nil {
+			n.entries = append(n.entries, rentry[T]{bounds: sibling.bounds(), child: sibling})
+		}
+	}
+
+	if len(n.entries) <= t.config.MaxEntries {
+		return nil
+	}
+	return t.split(n)
+}
+
+// chooseSubtree picks the child needing the least enlargement, preferring smaller ones on ties
+func chooseSubtree[T any](n *rnode[T], bounds Rect) int {
+	best := 0
+	bestEnlargement, bestArea := math.Inf(1), math.Inf(1)
+	for i, e := range n.entries {
+		area := e.bounds.Area()
+		enlargement := e.bounds.Union(bounds).Area() - area
+		if enlargement < bestEnlargement || (enlargement == bestEnlargement && area < bestArea) {
+			best, bestEnlargement, bestArea = i, enlargement, area
+		}
+	}
+	return best
+}
+
+// split moves half of the entries of n, sorted along the axis where they are
+// most spread out, to a new sibling
+func (t *RTree[T]) split(n *rnode[T]) *rnode[T] {
+	axis, widest := 0, -1.0
+	for dim := 0; dim < t.dims; dim++ {
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for _, e := range n.entries {
+			lo = min(lo, e.bounds.center(dim))
+			hi = max(hi, e.bounds.center(dim))
+		}
+		if hi-lo > widest {
+			axis, widest = dim, hi-lo
+		}
+	}
+	slices.SortFunc(n.entries, func(a, b rentry[T]) int {
+		return cmp.Compare(a.bounds.center(axis), b.bounds.center(axis))
+	})
+
+	half := len(n.entries) / 2
+	sibling := &rnode[T]{leaf: n.leaf, entries: slices.Clone(n.entries[half:])}
+	clear(n.entries[half:])
+	n.entries = n.entries[:half]
+	return sibling
+}
+
+// Search returns an iterator over the entries whose bounds intersect query
+// The tree is read-locked while the iteration runs, so the loop body must not modify the tree.
+func (t *RTree[T]) Search(query Rect) iter.Seq[Entry[T]] {
+	return func(yield func(Entry[T]) bool) {
+		if checkRect(query, t.dims) != nil {
+			return
+		}
+		t.mu.RLock() // Lock for reading
+		defer t.mu.RUnlock()
+		defer t.metrics.query(time.Now())
+
+		var search func(n *rnode[T]) bool
+		search = func(n *rnode[T]) bool {
+			for _, e := range n.entries {
+				if !e.bounds.Intersects(query) {
+					continue
+				}
+				if n.leaf {
+					if !yield(Entry[T]{Bounds: e.bounds, Value: e.value}) {
+						return false
+					}
+				} else if !search(e.child) {
+					return false
+				}
+			}
+			return true
+		}
+		search(t.root)
+	}
+}
+
+// Nearest returns up to k entries ordered by increasing distance from p to their bounds
+func (t *RTree[T]) Nearest(p Point, k int) []Entry[T] {
+	if len(p) != t.dims {
+		return nil
+	}
+	t.mu.RLock() // Lock for reading
+	defer t.mu.RUnlock()
+	defer t.metrics.query(time.Now())
+
+	return bestFirst(t.root, k, func(c candidate[T], push func(candidate[T])) {
+		n := c.node.(*rnode[T])
+		for _, e := range n.entries {
+			if n.leaf {
+				push(candidate[T]{distance2: e.bounds.distance2(p), entry: Entry[T]{Bounds: e.bounds, Value: e.value}})
+			} else {
+				push(candidate[T]{distance2: e.bounds.distance2(p), node: e.child})
+			}
+		}
+	})
+}
+
+// Len returns the number of stored entries
+func (t *RTree[T]) Len() int {
+	t.mu.RLock() // Lock for reading
+	defer t.mu.RUnlock()
+
+	return t.size
+}
+
+// height returns the number of levels of the tree
+func (t *RTree[T]) height() int {
+	h := 1
+	for n := t.root; !n.leaf; n = n.entries[0].child {
+		h++
+	}
+	return h
+}
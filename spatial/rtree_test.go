@@ -0,0 +1,118 @@
+package spatial
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func randomRects(rng *rand.Rand, n int) []Entry[int] {
+	entries := make([]Entry[int], n)
+	for i := range entries {
+		x, y := rng.Float64()*100, rng.Float64()*100
+		entries[i] = Entry[int]{
+			Bounds: Rect{Min: Point{x, y}, Max: Point{x + rng.Float64()*5, y + rng.Float64()*5}},
+			Value:  i,
+		}
+	}
+	return entries
+}
+
+func intersecting(entries []Entry[int], query Rect) []int {
+	var result []int
+	for _, e := range entries {
+		if e.Bounds.Intersects(query) {
+			result = append(result, e.Value)
+		}
+	}
+	return result
+}
+
+func TestRTreeBulkLoad(t *testing.T) {
+	rng := rand.New(rand.NewPCG(9, 10))
+	entries := randomRects(rng, 5000)
+	tree, err := BulkLoad(2, entries, RTreeConfig{MaxEntries: 16, MetricsEnabled: false})
+	assert.NoError(t, err)
+	assert.Equal(t, 5000, tree.Len())
+	// 5000 entries packed 16 per node need exactly four levels
+	assert.Equal(t, 4, tree.height())
+
+	for i := 0; i < 20; i++ {
+		x, y := rng.Float64()*100, rng.Float64()*100
+		query := Rect{Min: Point{x, y}, Max: Point{x + 10, y + 10}}
+		assert.Equal(t, intersecting(entries, query), values(tree.Search(query)))
+	}
+}
+
+func TestRTreeInsert(t *testing.T) {
+	rng := rand.New(rand.NewPCG(11, 12))
+	entries := randomRects(rng, 3000)
+	tree, err := NewRTree[int](2, RTreeConfig{MaxEntries: 8})
+	assert.NoError(t, err)
+	for _, e := range entries[:1000] {
+		assert.NoError(t, tree.Insert(e.Bounds, e.Value))
+	}
+
+	// Inserting into a bulk loaded tree keeps it searchable
+	loaded, _ := BulkLoad(2, entries[1000:2000], RTreeConfig{MaxEntries: 8})
+	for _, e := range entries[2000:] {
+		assert.NoError(t, loaded.Insert(e.Bounds, e.Value))
+	}
+
+	query := Rect{Min: Point{25, 25}, Max: Point{75, 40}}
+	assert.Equal(t, intersecting(entries[:1000], query), values(tree.Search(query)))
+	assert.Equal(t, intersecting(entries[1000:], query), values(loaded.Search(query)))
+}
+
+func TestRTreeNearest(t *testing.T) {
+	rng := rand.New(rand.NewPCG(13, 14))
+	entries := randomRects(rng, 2000)
+	tree, _ := BulkLoad(2, entries, RTreeConfig{})
+
+	for i := 0; i < 50; i++ {
+		p := Point{rng.Float64() * 100, rng.Float64() * 100}
+		assert.Equal(t, bruteNearest(entries, p, 6), distancesTo(tree.Nearest(p, 6), p))
+	}
+}
+
+func TestRTreeInvalidDimensions(t *testing.T) {
+	for _, dims := range []int{0, -1} {
+		_, err := BulkLoad[int](dims, nil, RTreeConfig{})
+		assert.Equal(t, ErrInvalidDimensions, err)
+		_, err = BulkLoad(dims, []Entry[int]{{Bounds: Rect{}, Value: 1}}, RTreeConfig{})
+		assert.Equal(t, ErrInvalidDimensions, err)
+		_, err = NewRTree[int](dims, RTreeConfig{})
+		assert.Equal(t, ErrInvalidDimensions, err)
+	}
+}
+
+func TestIndexInterface(t *testing.T) {
+	kd, _ := NewKDTree[int](2, KDTreeConfig{})
+	quad, _ := NewQuadTree[int](Rect{Min: Point{0, 0}, Max: Point{10, 10}}, QuadTreeConfig{})
+	r, _ := NewRTree[int](2, RTreeConfig{})
+
+	for _, index := range []Index[int]{kd, quad, r} {
+		assert.NoError(t, index.Insert(Point{1, 1}.Rect(), 1))
+		assert.NoError(t, index.Insert(Point{5, 5}.Rect(), 2))
+		assert.NoError(t, index.Insert(Point{9, 9}.Rect(), 3))
+
+		assert.Equal(t, 3, index.Len())
+		assert.Equal(t, []int{1, 2}, values(index.Search(Rect{Min: Point{0, 0}, Max: Point{5, 5}})))
+		nearest := index.Nearest(Point{8, 8}, 2)
+		assert.Equal(t, 3, nearest[0].Value)
+		assert.Equal(t, 2, nearest[1].Value)
+	}
+}
+
+func BenchmarkRTreeSearch(b *testing.B) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	tree, _ := BulkLoad(2, randomRects(rng, 100000), RTreeConfig{})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		x := float64(i % 90)
+		for range tree.Search(Rect{Min: Point{x, x}, Max: Point{x + 5, x + 5}}) {
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package spatial
+
+import (
+	"container/heap"
+	"errors"
+	"iter"
+	"math"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+// Errors returned by the indexes
+var (
+	ErrDimensionMismatch = errors.New("dimension mismatch")
+	ErrNotAPoint         = errors.New("bounds must be a point")
+	ErrOutOfBounds       = errors.New("point is outside the index bounds")
+	ErrInvalidDimensions = errors.New("dimensions must be at least 1")
+)
+
+// Point is a position in a k-dimensional space
+type Point []float64
+
+// Rect returns the degenerate rectangle covering only p
+func (p Point) Rect() Rect {
+	return Rect{Min: p, Max: p}
+}
+
+// Rect is an axis-aligned box, bounds are inclusive
+type Rect struct {
+	Min Point
+	Max Point
+}
+
+// Intersects reports whether both rectangles share at least one point
+func (r Rect) Intersects(other Rect) bool {
+	for i := range r.Min {
+		if r.Min[i] > other.Max[i] || other.Min[i] > r.Max[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Contains reports whether p lies inside the rectangle
+func (r Rect) Contains(p Point) bool {
+	for i := range r.Min {
+		if p[i] < r.Min[i] || p[i] > r.Max[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// IsPoint reports whether the rectangle is degenerate
+func (r Rect) IsPoint() bool {
+	for i := range r.Min {
+		if r.Min[i] != r.Max[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Area returns the volume of the rectangle
+func (r Rect) Area() float64 {
+	area := 1.0
+	for i := range r.Min {
+		area *= r.Max[i] - r.Min[i]
+	}
+	return area
+}
+
+// Union returns the smallest rectangle containing both rectangles
+func (r Rect) Union(other Rect) Rect {
+	u := Rect{Min: make(Point, len(r.Min)), Max: make(Point, len(r.Max))}
+	for i := range r.Min {
+		u.Min[i] = min(r.Min[i], other.Min[i])
+		u.Max[i] = max(r.Max[i], other.Max[i])
+	}
+	return u
+}
+
+// Distance returns the Euclidean distance from p to the closest point of the rectangle
+func (r Rect) Distance(p Point) float64 {
+	return math.Sqrt(r.distance2(p))
+}
+
+func (r Rect) distance2(p Point) float64 {
+	d := 0.0
+	for i := range p {
+		switch {
+		case p[i] < r.Min[i]:
+			d += (r.Min[i] - p[i]) * (r.Min[i] - p[i])
+		case p[i] > r.Max[i]:
+			d += (p[i] - r.Max[i]) * (p[i] - r.Max[i])
+		}
+	}
+	return d
+}
+
+func (r Rect) center(dim int) float64 {
+	return (r.Min[dim] + r.Max[dim]) / 2
+}
+
+// Entry is a value stored in an index along with its bounds
+// Point indexes store degenerate bounds where Min equals Max.
+type Entry[T any] struct {
+	Bounds Rect
+	Value  T
+}
+
+// Index is the query interface shared by the spatial indexes
+type Index[T any] interface {
+	// Insert adds a value with the given bounds
+	Insert(bounds Rect, value T) error
+	// Search returns an iterator over the entries whose bounds intersect query
+	Search(query Rect) iter.Seq[Entry[T]]
+	// Nearest returns up to k entries ordered by increasing distance to p
+	Nearest(p Point, k int) []Entry[T]
+	// Len returns the number of stored entries
+	Len() int
+}
+
+// indexMetrics groups the metrics of an index, registered under "spatial.<name>.*"
+// A nil *indexMetrics records nothing.
+type indexMetrics struct {
+	insertCounter metrics.Counter
+	queryCounter  metrics.Counter
+	queryDuration metrics.Timer
+}
+
+func newIndexMetrics(name string, enabled bool) *indexMetrics {
+	if !enabled {
+		return nil
+	}
+	m := &indexMetrics{
+		insertCounter: metrics.NewCounter(),
+		queryCounter:  metrics.NewCounter(),
+		queryDuration: metrics.NewTimer(),
+	}
+	metrics.DefaultRegistry.Register("spatial."+name+".insert", m.insertCounter)
+	metrics.DefaultRegistry.Register("spatial."+name+".query", m.queryCounter)
+	metrics.DefaultRegistry.Register("spatial."+name+".query.duration", m.queryDuration)
+	return m
+}
+
+func (m *indexMetrics) insert() {
+	if m != nil {
+		m.insertCounter.Inc(1)
+	}
+}
+
+// query records a query that started at the given time
+func (m *indexMetrics) query(start time.Time) {
+	if m != nil {
+		m.queryCounter.Inc(1)
+		m.queryDuration.UpdateSince(start)
+	}
+}
+
+// candidate is an element of a best-first nearest neighbour search:
+// either a stored entry or an index node still to be expanded
+type candidate[T any] struct {
+	distance2 float64
+	entry     Entry[T]
+	node      any
+}
+
+// candidateQueue is a min-heap of candidates ordered by distance
+type candidateQueue[T any] []candidate[T]
+
+func (q candidateQueue[T]) Len() int           { return len(q) }
+func (q candidateQueue[T]) Less(i, j int) bool { return q[i].distance2 < q[j].distance2 }
+func (q candidateQueue[T]) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *candidateQueue[T]) Push(x any)        { *q = append(*q, x.(candidate[T])) }
+func (q *candidateQueue[T]) Pop() any {
+	old := *q
+	c := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return c
+}
+
+// checkRect validates the dimensions of a rectangle
+func checkRect(r Rect, dims int) error {
+	if len(r.Min) != dims || len(r.Max) != dims {
+		return ErrDimensionMismatch
+	}
+	return nil
+}
+
+// bestFirst runs a best-first k nearest neighbour search starting at root
+// expand pushes the children of a node candidate, each with a lower bound of its distance to the target.
+// Entries come out of the queue in increasing distance order, so the first k popped are the answer.
+func bestFirst[T any](root any, k int, expand func(c candidate[T], push func(candidate[T]))) []Entry[T] {
+	if k <= 0 {
+		return nil
+	}
+	queue := &candidateQueue[T]{{node: root}}
+	push := func(c candidate[T]) { heap.Push(queue, c) }
+
+	var result []Entry[T]
+	for queue.Len() > 0 && len(result) < k {
+		c := heap.Pop(queue).(candidate[T])
+		if c.node == nil {
+			result = append(result, c.entry)
+			continue
+		}
+		expand(c, push)
+	}
+	return result
+}
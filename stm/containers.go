@@ -0,0 +1,209 @@
+package stm
+
+import (
+	"errors"
+
+	"github.com/vzahanych/data-structures/array"
+	"github.com/vzahanych/data-structures/hamt"
+	"github.com/vzahanych/data-structures/linkedlist"
+	"github.com/vzahanych/data-structures/persistent"
+)
+
+// The transactional containers keep immutable structures in TVars, so that a
+// transaction works on its own version and an abort simply drops it. The
+// containers wrapping an array.Array or a linkedlist.LinkedList also apply
+// every commit to the wrapped container under its own lock.
+
+// Array is a fixed-capacity array usable inside transactions
+type Array[T any] struct {
+	state *TVar[arrayState[T]]
+}
+
+// arrayState keeps the capacity and the elements in one variable so that they are committed together
+type arrayState[T any] struct {
+	capacity int
+	values   *persistent.Vector[T]
+}
+
+// NewArray creates an empty transactional array with a fixed capacity
+func NewArray[T any](capacity int) *Array[T] {
+	return &Array[T]{state: NewTVar(arrayState[T]{capacity: capacity, values: persistent.NewVector[T]()})}
+}
+
+// WrapArray creates a transactional array operating on a
+// It starts with the capacity and elements of a, and every commit changing
+// them replaces the contents of a in a single step, copying every element, so
+// each such commit costs O(n) on top of the transaction itself. Once wrapped,
+// a must only be modified through the transactional array, direct writes are
+// overwritten by the next commit.
+func WrapArray[T any](a *array.Array[T]) *Array[T] {
+	initial := arrayState[T]{capacity: a.Capacity(), values: persistent.VectorOf(a.Values()...)}
+	return &Array[T]{state: newHookedTVar(initial, func(s arrayState[T]) {
+		// The capacity was checked against the elements in the transaction
+		_ = a.Replace(collect(s.values), s.capacity)
+	})}
+}
+
+// Append adds a new element to the array
+func (a *Array[T]) Append(tx *Tx, value T) error {
+	s := a.state.Get(tx)
+	if s.values.Len() >= s.capacity {
+		return errors.New("array is full")
+	}
+	s.values = s.values.Append(value)
+	a.state.Set(tx, s)
+	return nil
+}
+
+// Get retrieves an element at the specified index
+func (a *Array[T]) Get(tx *Tx, index int) (T, error) {
+	value, err := a.state.Get(tx).values.Get(index)
+	if err != nil {
+		return value, errors.New("index out of bounds")
+	}
+	return value, nil
+}
+
+// Length returns the current number of elements in the array
+func (a *Array[T]) Length(tx *Tx) int {
+	return a.state.Get(tx).values.Len()
+}
+
+// Capacity returns the number of elements the array can hold without resizing
+func (a *Array[T]) Capacity(tx *Tx) int {
+	return a.state.Get(tx).capacity
+}
+
+// Resize resizes the array to a new capacity
+func (a *Array[T]) Resize(tx *Tx, newCapacity int) error {
+	s := a.state.Get(tx)
+	if newCapacity < s.values.Len() {
+		return errors.New("new capacity must be greater than or equal to the current size")
+	}
+	s.capacity = newCapacity
+	a.state.Set(tx, s)
+	return nil
+}
+
+// Delete removes the element at the specified index and shifts subsequent elements
+// The elements are copied into a new vector, so Delete takes O(n).
+func (a *Array[T]) Delete(tx *Tx, index int) error {
+	s := a.state.Get(tx)
+	if index < 0 || index >= s.values.Len() {
+		return errors.New("index out of bounds")
+	}
+	s.values = without(s.values, index)
+	a.state.Set(tx, s)
+	return nil
+}
+
+// LinkedList is a list of arbitrary elements usable inside transactions
+type LinkedList struct {
+	values *TVar[*persistent.Vector[interface{}]]
+}
+
+// NewLinkedList creates an empty transactional list
+func NewLinkedList() *LinkedList {
+	return &LinkedList{values: NewTVar(persistent.NewVector[interface{}]())}
+}
+
+// WrapLinkedList creates a transactional list operating on l
+// It starts with the elements of l, and every commit changing them replaces
+// the contents of l in a single step, copying every element, so each such
+// commit costs O(n) on top of the transaction itself. Once wrapped, l must
+// only be modified through the transactional list, direct writes are
+// overwritten by the next commit.
+func WrapLinkedList(l *linkedlist.LinkedList) *LinkedList {
+	initial := persistent.VectorOf(l.Values()...)
+	return &LinkedList{values: newHookedTVar(initial, func(values *persistent.Vector[interface{}]) {
+		l.Replace(collect(values))
+	})}
+}
+
+// Add an element at the end of the list
+func (l *LinkedList) Add(tx *Tx, data interface{}) {
+	l.values.Set(tx, l.values.Get(tx).Append(data))
+}
+
+// Remove the first occurrence of an element and report whether it was found
+// The remaining elements are copied into a new vector, so Remove takes O(n).
+func (l *LinkedList) Remove(tx *Tx, data interface{}) bool {
+	values := l.values.Get(tx)
+	for i, v := range values.All() {
+		if v == data {
+			l.values.Set(tx, without(values, i))
+			return true
+		}
+	}
+	return false
+}
+
+// Contains reports whether the list holds an element
+func (l *LinkedList) Contains(tx *Tx, data interface{}) bool {
+	for _, v := range l.values.Get(tx).All() {
+		if v == data {
+			return true
+		}
+	}
+	return false
+}
+
+// Length returns the number of elements in the list
+func (l *LinkedList) Length(tx *Tx) int {
+	return l.values.Get(tx).Len()
+}
+
+// Values returns the elements of the list in order
+func (l *LinkedList) Values(tx *Tx) []interface{} {
+	return collect(l.values.Get(tx))
+}
+
+// Map is a hash map usable inside transactions
+type Map[K comparable, V any] struct {
+	entries *TVar[*hamt.Map[K, V]]
+}
+
+// NewMap creates an empty transactional map
+func NewMap[K comparable, V any]() *Map[K, V] {
+	return &Map[K, V]{entries: NewTVar(hamt.New[K, V]())}
+}
+
+// Get returns the value stored for key
+func (m *Map[K, V]) Get(tx *Tx, key K) (V, bool) {
+	return m.entries.Get(tx).Get(key)
+}
+
+// Set stores value for key
+func (m *Map[K, V]) Set(tx *Tx, key K, value V) {
+	m.entries.Set(tx, m.entries.Get(tx).Assoc(key, value))
+}
+
+// Delete removes key
+func (m *Map[K, V]) Delete(tx *Tx, key K) {
+	m.entries.Set(tx, m.entries.Get(tx).Dissoc(key))
+}
+
+// Len returns the number of entries
+func (m *Map[K, V]) Len(tx *Tx) int {
+	return m.entries.Get(tx).Len()
+}
+
+// collect returns the elements of values in order
+func collect[T any](values *persistent.Vector[T]) []T {
+	result := make([]T, 0, values.Len())
+	for _, v := range values.All() {
+		result = append(result, v)
+	}
+	return result
+}
+
+// without returns a copy of values without the element at index
+func without[T any](values *persistent.Vector[T], index int) *persistent.Vector[T] {
+	tr := persistent.NewVector[T]().Transient()
+	for i, v := range values.All() {
+		if i != index {
+			tr.Append(v)
+		}
+	}
+	return tr.Persistent()
+}
@@ -0,0 +1,155 @@
+package stm
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vzahanych/data-structures/array"
+	"github.com/vzahanych/data-structures/linkedlist"
+)
+
+func TestArray(t *testing.T) {
+	source := array.NewArray[int](3, array.ArrayConfig{})
+	source.Append(1)
+	source.Append(2)
+	a := WrapArray(source)
+
+	err := Atomically(func(tx *Tx) error {
+		assert.Equal(t, 3, a.Capacity(tx))
+		assert.NoError(t, a.Append(tx, 3))
+		assert.Error(t, a.Append(tx, 4))
+		assert.NoError(t, a.Resize(tx, 4))
+		assert.NoError(t, a.Append(tx, 4))
+		assert.NoError(t, a.Delete(tx, 0))
+		assert.Error(t, a.Delete(tx, 3))
+		assert.Error(t, a.Resize(tx, 2))
+
+		value, err := a.Get(tx, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, value)
+		_, err = a.Get(tx, 3)
+		assert.Error(t, err)
+		assert.Equal(t, 3, a.Length(tx))
+		return nil
+	})
+	assert.NoError(t, err)
+
+	// The commit is applied to the wrapped array
+	assert.Equal(t, 3, source.Length())
+	assert.Equal(t, 4, source.Capacity())
+	value, err := source.Get(0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, value)
+
+	// An aborted transaction leaves it untouched
+	err = Atomically(func(tx *Tx) error {
+		assert.NoError(t, a.Delete(tx, 0))
+		return errors.New("abort")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 3, source.Length())
+}
+
+func TestMoveBetweenLists(t *testing.T) {
+	source, target := linkedlist.NewLinkedList(), linkedlist.NewLinkedList()
+	for i := 0; i < 100; i++ {
+		source.Add(i)
+	}
+	from, to := WrapLinkedList(source), WrapLinkedList(target)
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := w; i < 100; i += 4 {
+				Atomically(func(tx *Tx) error {
+					if from.Remove(tx, i) {
+						to.Add(tx, i)
+					}
+					return nil
+				})
+			}
+		}()
+	}
+
+	// No reader ever sees an element in both lists or in none
+	for i := 0; i < 100; i++ {
+		Atomically(func(tx *Tx) error {
+			assert.Equal(t, 100, from.Length(tx)+to.Length(tx))
+			assert.NotEqual(t, from.Contains(tx, i), to.Contains(tx, i))
+			return nil
+		})
+	}
+	wg.Wait()
+
+	Atomically(func(tx *Tx) error {
+		assert.Equal(t, 0, from.Length(tx))
+		assert.Len(t, to.Values(tx), 100)
+		return nil
+	})
+
+	// Both wrapped lists hold the result of the transactions
+	assert.Equal(t, 0, source.Length())
+	assert.Equal(t, 100, target.Length())
+	Atomically(func(tx *Tx) error {
+		assert.Equal(t, to.Values(tx), target.Values())
+		return nil
+	})
+}
+
+func TestMoveBetweenWrappedLists(t *testing.T) {
+	source, target := linkedlist.NewLinkedList(), linkedlist.NewLinkedList()
+	source.Add("a")
+	source.Add("b")
+	target.Add("c")
+	from, to := WrapLinkedList(source), WrapLinkedList(target)
+
+	err := Atomically(func(tx *Tx) error {
+		if !from.Remove(tx, "a") {
+			return errors.New("missing element")
+		}
+		to.Add(tx, "a")
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{"b"}, source.Values())
+	assert.Equal(t, []interface{}{"c", "a"}, target.Values())
+}
+
+func TestArrayToMap(t *testing.T) {
+	a := NewArray[string](10)
+	m := NewMap[string, int]()
+	Atomically(func(tx *Tx) error {
+		for _, s := range []string{"a", "b", "c"} {
+			a.Append(tx, s)
+		}
+		return nil
+	})
+
+	// Move the first element of the array into the map
+	err := Atomically(func(tx *Tx) error {
+		value, err := a.Get(tx, 0)
+		if err != nil {
+			return err
+		}
+		if err := a.Delete(tx, 0); err != nil {
+			return err
+		}
+		m.Set(tx, value, len(value))
+		return nil
+	})
+	assert.NoError(t, err)
+
+	Atomically(func(tx *Tx) error {
+		assert.Equal(t, 2, a.Length(tx))
+		value, found := m.Get(tx, "a")
+		assert.True(t, found)
+		assert.Equal(t, 1, value)
+		m.Delete(tx, "a")
+		assert.Equal(t, 0, m.Len(tx))
+		return nil
+	})
+}
@@ -0,0 +1,202 @@
+package stm
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrRetry makes Atomically block until another transaction commits, then run the function again
+// Return it when the transaction cannot proceed yet, e.g. when taking from an empty container.
+var ErrRetry = errors.New("stm: retry")
+
+var (
+	// clock is incremented by every committing transaction that writes
+	clock atomic.Uint64
+	// nextID orders variables so that commits lock them consistently
+	nextID atomic.Uint64
+
+	// waitMu and committed wake up the transactions blocked by ErrRetry
+	waitMu    sync.Mutex
+	committed = sync.NewCond(&waitMu)
+)
+
+// conflict is the panic value used to abort a transaction that read a value newer than its start
+type conflict struct{}
+
+// variable is the untyped part of a TVar
+type variable struct {
+	id      uint64
+	mu      sync.Mutex
+	version uint64 // Clock value of the commit that wrote value
+	value   any
+	hook    func(value any) // Called with every committed value, see newHookedTVar
+}
+
+func (v *variable) load() (any, uint64) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return v.value, v.version
+}
+
+// TVar is a transactional variable
+// It can only be read and written inside Atomically, and every transaction
+// sees all the variables it uses as of a single point in time.
+type TVar[T any] struct {
+	v variable
+}
+
+// NewTVar creates a variable holding value
+func NewTVar[T any](value T) *TVar[T] {
+	t := &TVar[T]{}
+	t.v.id = nextID.Add(1)
+	t.v.value = value
+	return t
+}
+
+// newHookedTVar creates a variable calling hook with every value a transaction commits to it
+// The hooks of a transaction run in id order while its variables are locked,
+// so they apply the commits to other structures in the order they happen.
+func newHookedTVar[T any](value T, hook func(T)) *TVar[T] {
+	t := NewTVar(value)
+	t.v.hook = func(value any) { hook(value.(T)) }
+	return t
+}
+
+// Get returns the value of the variable as seen by tx
+func (t *TVar[T]) Get(tx *Tx) T {
+	return tx.get(&t.v).(T)
+}
+
+// Set changes the value of the variable in tx
+// The change becomes visible to other transactions when tx commits.
+func (t *TVar[T]) Set(tx *Tx, value T) {
+	tx.writes[&t.v] = value
+}
+
+// Load returns the latest committed value, outside of any transaction
+func (t *TVar[T]) Load() T {
+	value, _ := t.v.load()
+	return value.(T)
+}
+
+// Tx is a running transaction, only valid inside the function passed to Atomically
+type Tx struct {
+	start  uint64
+	reads  map[*variable]uint64
+	writes map[*variable]any
+}
+
+func (tx *Tx) get(v *variable) any {
+	if value, found := tx.writes[v]; found {
+		return value
+	}
+	value, version := v.load()
+	if version > tx.start {
+		// Committed after tx started: mixing it with older reads could be inconsistent
+		panic(conflict{})
+	}
+	tx.reads[v] = version
+	return value
+}
+
+// Atomically runs fn as a transaction and commits its writes all at once
+// fn is run again, from scratch, when another transaction committed a
+// conflicting write in the meantime, so it must not have side effects other
+// than through TVars. If fn returns ErrRetry, Atomically waits for another
+// commit and runs it again. Any other error aborts the transaction, discarding
+// its writes, and is returned.
+func Atomically(fn func(tx *Tx) error) error {
+	for {
+		tx := &Tx{start: clock.Load(), reads: make(map[*variable]uint64), writes: make(map[*variable]any)}
+		ok, err := run(tx, fn)
+		if !ok {
+			continue
+		}
+		if errors.Is(err, ErrRetry) {
+			wait(tx.start)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if tx.commit() {
+			return nil
+		}
+	}
+}
+
+// run calls fn, returning false if tx was aborted by a conflict
+func run(tx *Tx, fn func(tx *Tx) error) (ok bool, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, aborted := r.(conflict); !aborted {
+				panic(r)
+			}
+			ok = false
+		}
+	}()
+	return true, fn(tx)
+}
+
+// commit validates the reads of tx and publishes its writes, reporting false on conflict
+func (tx *Tx) commit() bool {
+	if len(tx.writes) == 0 {
+		// Every read was consistent with tx.start, there is nothing to publish
+		return true
+	}
+
+	// Lock every variable involved, in id order to avoid deadlocks between committers
+	vars := make([]*variable, 0, len(tx.reads)+len(tx.writes))
+	for v := range tx.writes {
+		vars = append(vars, v)
+	}
+	for v := range tx.reads {
+		if _, written := tx.writes[v]; !written {
+			vars = append(vars, v)
+		}
+	}
+	slices.SortFunc(vars, func(a, b *variable) int { return cmp.Compare(a.id, b.id) })
+	for _, v := range vars {
+		v.mu.Lock()
+	}
+	defer func() {
+		for _, v := range vars {
+			v.mu.Unlock()
+		}
+	}()
+
+	for v, version := range tx.reads {
+		if v.version != version {
+			return false
+		}
+	}
+	version := clock.Add(1)
+	for v, value := range tx.writes {
+		v.value = value
+		v.version = version
+	}
+	for _, v := range vars {
+		if value, written := tx.writes[v]; written && v.hook != nil {
+			v.hook(value)
+		}
+	}
+
+	waitMu.Lock()
+	committed.Broadcast()
+	waitMu.Unlock()
+	return true
+}
+
+// wait blocks until a transaction commits after the clock value start
+func wait(start uint64) {
+	waitMu.Lock()
+	defer waitMu.Unlock()
+
+	for clock.Load() == start {
+		committed.Wait()
+	}
+}
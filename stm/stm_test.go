@@ -0,0 +1,135 @@
+package stm
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAtomically(t *testing.T) {
+	x := NewTVar(1)
+	y := NewTVar("a")
+
+	err := Atomically(func(tx *Tx) error {
+		x.Set(tx, x.Get(tx)+1)
+		y.Set(tx, y.Get(tx)+"b")
+		// Writes are visible inside the transaction before commit
+		assert.Equal(t, 2, x.Get(tx))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, x.Load())
+	assert.Equal(t, "ab", y.Load())
+}
+
+func TestAbort(t *testing.T) {
+	x := NewTVar(1)
+	failure := errors.New("failure")
+
+	err := Atomically(func(tx *Tx) error {
+		x.Set(tx, 100)
+		return failure
+	})
+	assert.Equal(t, failure, err)
+	assert.Equal(t, 1, x.Load())
+}
+
+func TestConcurrentTransfers(t *testing.T) {
+	const accounts, total = 10, 1000
+	balances := make([]*TVar[int], accounts)
+	for i := range balances {
+		balances[i] = NewTVar(total / accounts)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				from, to := balances[(w+i)%accounts], balances[(w+3*i+1)%accounts]
+				Atomically(func(tx *Tx) error {
+					if from == to || from.Get(tx) == 0 {
+						return nil
+					}
+					from.Set(tx, from.Get(tx)-1)
+					to.Set(tx, to.Get(tx)+1)
+					return nil
+				})
+			}
+		}()
+	}
+
+	// Readers always see the total preserved
+	for i := 0; i < 200; i++ {
+		Atomically(func(tx *Tx) error {
+			sum := 0
+			for _, b := range balances {
+				sum += b.Get(tx)
+			}
+			assert.Equal(t, total, sum)
+			return nil
+		})
+	}
+	wg.Wait()
+
+	sum := 0
+	for _, b := range balances {
+		sum += b.Load()
+	}
+	assert.Equal(t, total, sum)
+}
+
+func TestRetry(t *testing.T) {
+	ready := NewTVar(false)
+	done := make(chan struct{})
+
+	go func() {
+		Atomically(func(tx *Tx) error {
+			if !ready.Get(tx) {
+				return ErrRetry
+			}
+			return nil
+		})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		t.Fatal("transaction did not wait")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	Atomically(func(tx *Tx) error {
+		ready.Set(tx, true)
+		return nil
+	})
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("transaction was not woken up")
+	}
+}
+
+func TestPanicPropagates(t *testing.T) {
+	assert.PanicsWithValue(t, "boom", func() {
+		Atomically(func(tx *Tx) error {
+			panic("boom")
+		})
+	})
+}
+
+func BenchmarkAtomically(b *testing.B) {
+	x := NewTVar(0)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Atomically(func(tx *Tx) error {
+			x.Set(tx, x.Get(tx)+1)
+			return nil
+		})
+	}
+}
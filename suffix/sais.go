@@ -0,0 +1,165 @@
+package suffix
+
+// sais computes the suffix array of s, whose symbols are in [0, upper], with SA-IS
+// Suffixes are classified as S-type or L-type, the leftmost S-type suffixes
+// (LMS) are sorted first, recursively when their substrings are not unique,
+// and the order of every other suffix is induced from them in linear time.
+func sais(s []int, upper int) []int {
+	n := len(s)
+	switch n {
+	case 0:
+		return []int{}
+	case 1:
+		return []int{0}
+	case 2:
+		if s[0] < s[1] {
+			return []int{0, 1}
+		}
+		return []int{1, 0}
+	}
+
+	// ls[i] reports whether the suffix at i is S-type, the last one is L-type
+	ls := make([]bool, n)
+	for i := n - 2; i >= 0; i-- {
+		if s[i] == s[i+1] {
+			ls[i] = ls[i+1]
+		} else {
+			ls[i] = s[i] < s[i+1]
+		}
+	}
+
+	// Bucket boundaries: sumL[c] is the start of the bucket of c, sumS[c] the start of its S-type part
+	sumL := make([]int, upper+2)
+	sumS := make([]int, upper+2)
+	for i := 0; i < n; i++ {
+		if !ls[i] {
+			sumS[s[i]]++
+		} else {
+			sumL[s[i]+1]++
+		}
+	}
+	for c := 0; c <= upper; c++ {
+		sumS[c] += sumL[c]
+		sumL[c+1] += sumS[c]
+	}
+
+	sa := make([]int, n)
+	buf := make([]int, upper+2)
+	induce := func(lms []int) {
+		for i := range sa {
+			sa[i] = -1
+		}
+		copy(buf, sumS)
+		for _, d := range lms {
+			if d == n {
+				continue
+			}
+			sa[buf[s[d]]] = d
+			buf[s[d]]++
+		}
+		copy(buf, sumL)
+		sa[buf[s[n-1]]] = n - 1
+		buf[s[n-1]]++
+		for i := 0; i < n; i++ {
+			if v := sa[i]; v >= 1 && !ls[v-1] {
+				sa[buf[s[v-1]]] = v - 1
+				buf[s[v-1]]++
+			}
+		}
+		copy(buf, sumL)
+		for i := n - 1; i >= 0; i-- {
+			if v := sa[i]; v >= 1 && ls[v-1] {
+				buf[s[v-1]+1]--
+				sa[buf[s[v-1]+1]] = v - 1
+			}
+		}
+	}
+
+	lmsMap := make([]int, n+1)
+	for i := range lmsMap {
+		lmsMap[i] = -1
+	}
+	var lms []int
+	for i := 1; i < n; i++ {
+		if !ls[i-1] && ls[i] {
+			lmsMap[i] = len(lms)
+			lms = append(lms, i)
+		}
+	}
+	m := len(lms)
+	induce(lms)
+	if m == 0 {
+		return sa
+	}
+
+	sortedLMS := make([]int, 0, m)
+	for _, v := range sa {
+		if lmsMap[v] != -1 {
+			sortedLMS = append(sortedLMS, v)
+		}
+	}
+
+	// Name the LMS substrings in sorted order, equal substrings share a name
+	recS := make([]int, m)
+	recUpper := 0
+	recS[lmsMap[sortedLMS[0]]] = 0
+	for i := 1; i < m; i++ {
+		l, r := sortedLMS[i-1], sortedLMS[i]
+		endL, endR := n, n
+		if lmsMap[l]+1 < m {
+			endL = lms[lmsMap[l]+1]
+		}
+		if lmsMap[r]+1 < m {
+			endR = lms[lmsMap[r]+1]
+		}
+		same := endL-l == endR-r
+		if same {
+			for l < endL && s[l] == s[r] {
+				l++
+				r++
+			}
+			if l == n || s[l] != s[r] {
+				same = false
+			}
+		}
+		if !same {
+			recUpper++
+		}
+		recS[lmsMap[sortedLMS[i]]] = recUpper
+	}
+
+	recSA := sais(recS, recUpper)
+	for i := range sortedLMS {
+		sortedLMS[i] = lms[recSA[i]]
+	}
+	induce(sortedLMS)
+	return sa
+}
+
+// kasai computes the LCP array of text with Kasai's algorithm in linear time
+// lcp[i] is the length of the longest common prefix of the suffixes at sa[i-1]
+// and sa[i], lcp[0] is zero.
+func kasai(text []byte, sa []int) []int {
+	n := len(text)
+	rank := make([]int, n)
+	for i, offset := range sa {
+		rank[offset] = i
+	}
+	lcp := make([]int, n)
+	h := 0
+	for i := 0; i < n; i++ {
+		if rank[i] == 0 {
+			h = 0
+			continue
+		}
+		j := sa[rank[i]-1]
+		for i+h < n && j+h < n && text[i+h] == text[j+h] {
+			h++
+		}
+		lcp[rank[i]] = h
+		if h > 0 {
+			h--
+		}
+	}
+	return lcp
+}
@@ -0,0 +1,69 @@
+package suffix
+
+import (
+	"bytes"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// naive sorts the suffixes of text by comparison
+func naive(text []byte) []int {
+	sa := make([]int, len(text))
+	for i := range sa {
+		sa[i] = i
+	}
+	slices.SortFunc(sa, func(i, j int) int { return bytes.Compare(text[i:], text[j:]) })
+	return sa
+}
+
+func symbols(text []byte) []int {
+	s := make([]int, len(text))
+	for i, c := range text {
+		s[i] = int(c)
+	}
+	return s
+}
+
+func TestSAIS(t *testing.T) {
+	for _, text := range []string{"", "a", "ab", "ba", "aa", "banana", "mississippi", "abracadabra", "aaaaaaaa", "abababab"} {
+		assert.Equal(t, naive([]byte(text)), sais(symbols([]byte(text)), 255), text)
+	}
+
+	// Small alphabets produce many equal LMS substrings and deep recursion
+	rng := rand.New(rand.NewPCG(1, 2))
+	for round := 0; round < 200; round++ {
+		text := make([]byte, rng.IntN(300))
+		alphabet := 1 + rng.IntN(4)
+		for i := range text {
+			text[i] = byte('a' + rng.IntN(alphabet))
+		}
+		assert.Equal(t, naive(text), sais(symbols(text), 255), string(text))
+	}
+}
+
+func TestKasai(t *testing.T) {
+	text := []byte("banana")
+	sa := sais(symbols(text), 255)
+	assert.Equal(t, []int{5, 3, 1, 0, 4, 2}, sa)
+	// a, ana, anana, banana, na, nana
+	assert.Equal(t, []int{0, 1, 3, 0, 0, 2}, kasai(text, sa))
+
+	rng := rand.New(rand.NewPCG(3, 4))
+	text = make([]byte, 500)
+	for i := range text {
+		text[i] = byte('a' + rng.IntN(3))
+	}
+	sa = sais(symbols(text), 255)
+	lcp := kasai(text, sa)
+	for i := 1; i < len(sa); i++ {
+		a, b := text[sa[i-1]:], text[sa[i]:]
+		l := 0
+		for l < min(len(a), len(b)) && a[l] == b[l] {
+			l++
+		}
+		assert.Equal(t, l, lcp[i])
+	}
+}
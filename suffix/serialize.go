@@ -0,0 +1,179 @@
+package suffix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+)
+
+// ErrInvalidData is returned when decoding data that was not produced by WriteTo or MarshalBinary
+var ErrInvalidData = errors.New("invalid suffix array data")
+
+const (
+	// Header layout: magic(4) version(1) textLength(8) indexLength(8)
+	magic        = "SUFX"
+	version      = 1
+	headerLength = 21
+)
+
+// MarshalBinary encodes the text and its suffix and LCP arrays
+func (a *Array) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := a.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary replaces the index with data produced by MarshalBinary
+// Metrics collection is kept as configured on the receiver
+func (a *Array) UnmarshalBinary(data []byte) error {
+	// Check the lengths announced by the header against the data before decoding
+	if len(data) >= headerLength {
+		length, err := payloadLength(data[:headerLength])
+		if err != nil {
+			return err
+		}
+		switch available := uint64(len(data) - headerLength); {
+		case available < length:
+			return io.ErrUnexpectedEOF
+		case available > length:
+			return ErrInvalidData
+		}
+	}
+
+	r := bytes.NewReader(data)
+	if _, err := a.ReadFrom(r); err != nil {
+		return err
+	}
+	if r.Len() != 0 {
+		return ErrInvalidData
+	}
+	return nil
+}
+
+// WriteTo writes the text followed by the suffix and LCP arrays as uvarints
+// The arrays are stored rather than rebuilt on load.
+func (a *Array) WriteTo(w io.Writer) (int64, error) {
+	a.mu.RLock() // Lock for reading
+	defer a.mu.RUnlock()
+
+	var index []byte
+	for _, v := range a.sa.Snapshot().All() {
+		index = binary.AppendUvarint(index, uint64(v))
+	}
+	for _, v := range a.lcp.Snapshot().All() {
+		index = binary.AppendUvarint(index, uint64(v))
+	}
+
+	header := make([]byte, headerLength)
+	copy(header, magic)
+	header[4] = version
+	binary.LittleEndian.PutUint64(header[5:], uint64(len(a.text)))
+	binary.LittleEndian.PutUint64(header[13:], uint64(len(index)))
+
+	var total int64
+	for _, data := range [][]byte{header, a.text, index} {
+		written, err := w.Write(data)
+		total += int64(written)
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// ReadFrom replaces the index with one written by WriteTo
+// The suffix array is checked to be a permutation of the text offsets but its order is trusted.
+func (a *Array) ReadFrom(r io.Reader) (int64, error) {
+	header := make([]byte, headerLength)
+	n, err := io.ReadFull(r, header)
+	total := int64(n)
+	if err != nil {
+		return total, unexpectedEOF(err)
+	}
+	length, err := payloadLength(header)
+	if err != nil {
+		return total, err
+	}
+
+	// The buffer grows as data arrives rather than trusting the header, so a
+	// short stream cannot make it allocate the announced length upfront
+	data, err := io.ReadAll(io.LimitReader(r, int64(length)))
+	total += int64(len(data))
+	if err != nil {
+		return total, err
+	}
+	if uint64(len(data)) != length {
+		return total, io.ErrUnexpectedEOF
+	}
+	textLength := binary.LittleEndian.Uint64(header[5:])
+	text, index := data[:textLength], data[textLength:]
+
+	sa, index, err := decode(index, len(text))
+	if err != nil {
+		return total, err
+	}
+	lcp, index, err := decode(index, len(text))
+	if err != nil || len(index) != 0 {
+		return total, ErrInvalidData
+	}
+	seen := make([]bool, len(text))
+	for _, offset := range sa {
+		if offset >= len(text) || seen[offset] {
+			return total, ErrInvalidData
+		}
+		seen[offset] = true
+	}
+	for i, l := range lcp {
+		if l > len(text)-sa[i] {
+			return total, ErrInvalidData
+		}
+	}
+
+	a.mu.Lock() // Lock for writing
+	defer a.mu.Unlock()
+
+	a.set(text, sa, lcp)
+	if a.config.MetricsEnabled && a.lookupCounter == nil {
+		a.initMetrics()
+	}
+	return total, nil
+}
+
+// payloadLength checks a header and returns the number of bytes following it
+func payloadLength(header []byte) (uint64, error) {
+	if string(header[:4]) != magic || header[4] != version {
+		return 0, ErrInvalidData
+	}
+	textLength := binary.LittleEndian.Uint64(header[5:])
+	indexLength := binary.LittleEndian.Uint64(header[13:])
+	// Every entry of both arrays takes at least one byte and at most ten
+	if textLength > math.MaxInt64/21 || indexLength < 2*textLength || indexLength > 20*textLength {
+		return 0, ErrInvalidData
+	}
+	return textLength + indexLength, nil
+}
+
+// decode reads n uvarints from data and returns them with the remaining bytes
+func decode(data []byte, n int) ([]int, []byte, error) {
+	values := make([]int, n)
+	for i := range values {
+		v, size := binary.Uvarint(data)
+		if size <= 0 || v > uint64(n) {
+			return nil, nil, ErrInvalidData
+		}
+		values[i] = int(v)
+		data = data[size:]
+	}
+	return values, data, nil
+}
+
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,78 @@
+package suffix
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSerialization(t *testing.T) {
+	a := New([]byte("mississippi"), Config{})
+	data, err := a.MarshalBinary()
+	assert.NoError(t, err)
+
+	var decoded Array
+	assert.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, 11, decoded.Len())
+	assert.Equal(t, []int{2, 5}, decoded.Lookup([]byte("ssi")))
+	offset, length := decoded.LongestRepeat()
+	assert.Equal(t, "issi", "mississippi"[offset:offset+length])
+
+	// WriteTo and ReadFrom report the bytes consumed and stop at the end of the index
+	var buf bytes.Buffer
+	written, err := a.WriteTo(&buf)
+	assert.NoError(t, err)
+	buf.WriteString("trailing")
+	read, err := decoded.ReadFrom(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, written, read)
+	assert.Equal(t, "trailing", buf.String())
+
+	empty, err := New(nil, Config{}).MarshalBinary()
+	assert.NoError(t, err)
+	assert.NoError(t, decoded.UnmarshalBinary(empty))
+	assert.Equal(t, 0, decoded.Len())
+}
+
+func TestSerializationInvalid(t *testing.T) {
+	data, _ := New([]byte("banana"), Config{}).MarshalBinary()
+	var a Array
+
+	assert.Equal(t, io.ErrUnexpectedEOF, a.UnmarshalBinary(data[:10]))
+	assert.Equal(t, io.ErrUnexpectedEOF, a.UnmarshalBinary(data[:len(data)-1]))
+	assert.Equal(t, ErrInvalidData, a.UnmarshalBinary(append(data, 0)))
+
+	corrupt := bytes.Clone(data)
+	corrupt[0] = 'X'
+	assert.Equal(t, ErrInvalidData, a.UnmarshalBinary(corrupt))
+
+	// Suffix array entries repeating an offset
+	corrupt = bytes.Clone(data)
+	corrupt[headerLength+6] = corrupt[headerLength+7]
+	assert.Equal(t, ErrInvalidData, a.UnmarshalBinary(corrupt))
+}
+
+func TestSerializationOversizedHeader(t *testing.T) {
+	// A header announcing far more data than follows must fail without allocating it
+	header := make([]byte, headerLength)
+	copy(header, magic)
+	header[4] = version
+	binary.LittleEndian.PutUint64(header[5:], 1<<45)
+	binary.LittleEndian.PutUint64(header[13:], 2<<45)
+
+	var a Array
+	assert.Equal(t, io.ErrUnexpectedEOF, a.UnmarshalBinary(header))
+	_, err := a.ReadFrom(bytes.NewReader(append(header, "banana"...)))
+	assert.Equal(t, io.ErrUnexpectedEOF, err)
+
+	// Lengths whose sum does not fit are rejected outright
+	binary.LittleEndian.PutUint64(header[5:], math.MaxUint64/2)
+	binary.LittleEndian.PutUint64(header[13:], math.MaxUint64)
+	assert.Equal(t, ErrInvalidData, a.UnmarshalBinary(header))
+	_, err = a.ReadFrom(bytes.NewReader(header))
+	assert.Equal(t, ErrInvalidData, err)
+}
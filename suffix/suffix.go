@@ -0,0 +1,170 @@
+package suffix
+
+import (
+	"bytes"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+	"github.com/vzahanych/data-structures/array"
+)
+
+type Config struct {
+	MetricsEnabled bool
+}
+
+// Array is a suffix array over a text along with its LCP array
+// The suffix array lists the offsets of all suffixes of the text in
+// lexicographic order, so the occurrences of any pattern form a contiguous
+// range of it. Both integer arrays are stored in array.Array.
+type Array struct {
+	text          []byte
+	sa            *array.Array[int]
+	lcp           *array.Array[int]
+	mu            sync.RWMutex
+	config        Config
+	lookupCounter metrics.Counter
+	buildTimer    metrics.Timer
+}
+
+// New builds the suffix and LCP arrays of text in linear time
+// The text is retained and must not be modified afterwards.
+// The config parameter is used to enable or disable metrics collection
+func New(text []byte, config Config) *Array {
+	a := &Array{config: config}
+
+	// Initialize the metrics only if enabled in the config
+	if a.config.MetricsEnabled {
+		a.initMetrics()
+	}
+
+	start := time.Now()
+	s := make([]int, len(text))
+	for i, c := range text {
+		s[i] = int(c)
+	}
+	sa := sais(s, 255)
+	a.set(text, sa, kasai(text, sa))
+
+	// Track metrics if enabled
+	if a.config.MetricsEnabled {
+		a.buildTimer.UpdateSince(start)
+	}
+	return a
+}
+
+func (a *Array) initMetrics() {
+	a.lookupCounter = metrics.NewCounter()
+	a.buildTimer = metrics.NewTimer()
+	metrics.DefaultRegistry.Register("suffix.lookup", a.lookupCounter)
+	metrics.DefaultRegistry.Register("suffix.build", a.buildTimer)
+}
+
+// set stores the text and its arrays
+func (a *Array) set(text []byte, sa, lcp []int) {
+	a.text = text
+	a.sa = fromSlice(sa)
+	a.lcp = fromSlice(lcp)
+}
+
+// fromSlice copies values into an array.Array holding exactly them
+func fromSlice(values []int) *array.Array[int] {
+	arr := array.NewArray[int](len(values), array.ArrayConfig{})
+	for _, v := range values {
+		arr.Append(v)
+	}
+	return arr
+}
+
+// Len returns the length of the indexed text
+func (a *Array) Len() int {
+	a.mu.RLock() // Lock for reading
+	defer a.mu.RUnlock()
+
+	return len(a.text)
+}
+
+// Suffix returns the offset of the i-th smallest suffix
+func (a *Array) Suffix(i int) (int, error) {
+	a.mu.RLock() // Lock for reading
+	defer a.mu.RUnlock()
+
+	return a.sa.Get(i)
+}
+
+// LCP returns the length of the longest common prefix of the (i-1)-th and i-th smallest suffixes
+// LCP(0) is zero.
+func (a *Array) LCP(i int) (int, error) {
+	a.mu.RLock() // Lock for reading
+	defer a.mu.RUnlock()
+
+	return a.lcp.Get(i)
+}
+
+// Lookup returns the offsets of all occurrences of pattern in increasing order
+// An empty pattern matches nothing.
+func (a *Array) Lookup(pattern []byte) []int {
+	a.mu.RLock() // Lock for reading
+	defer a.mu.RUnlock()
+
+	// Track metrics if enabled
+	if a.config.MetricsEnabled {
+		a.lookupCounter.Inc(1)
+	}
+
+	if len(pattern) == 0 {
+		return nil
+	}
+	n := a.sa.Length()
+	// The suffixes starting with pattern are those in [from, to)
+	from := sort.Search(n, func(i int) bool {
+		return bytes.Compare(a.prefix(i, len(pattern)), pattern) >= 0
+	})
+	to := from + sort.Search(n-from, func(i int) bool {
+		return !bytes.HasPrefix(a.suffix(from+i), pattern)
+	})
+	if from == to {
+		return nil
+	}
+
+	offsets := make([]int, 0, to-from)
+	for i := from; i < to; i++ {
+		offset, _ := a.sa.Get(i)
+		offsets = append(offsets, offset)
+	}
+	slices.Sort(offsets)
+	return offsets
+}
+
+// LongestRepeat returns the offset and length of the longest substring occurring at least twice
+// The length is zero when no byte of the text repeats.
+func (a *Array) LongestRepeat() (offset, length int) {
+	a.mu.RLock() // Lock for reading
+	defer a.mu.RUnlock()
+
+	best := 0
+	for i := 1; i < a.lcp.Length(); i++ {
+		if l, _ := a.lcp.Get(i); l > length {
+			best, length = i, l
+		}
+	}
+	if length == 0 {
+		return 0, 0
+	}
+	offset, _ = a.sa.Get(best)
+	return offset, length
+}
+
+// suffix returns the i-th smallest suffix
+func (a *Array) suffix(i int) []byte {
+	offset, _ := a.sa.Get(i)
+	return a.text[offset:]
+}
+
+// prefix returns at most the first n bytes of the i-th smallest suffix
+func (a *Array) prefix(i, n int) []byte {
+	s := a.suffix(i)
+	return s[:min(n, len(s))]
+}
@@ -0,0 +1,95 @@
+package suffix
+
+import (
+	"bytes"
+	"index/suffixarray"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookup(t *testing.T) {
+	a := New([]byte("abracadabra"), Config{})
+	assert.Equal(t, 11, a.Len())
+	assert.Equal(t, []int{0, 3, 5, 7, 10}, a.Lookup([]byte("a")))
+	assert.Equal(t, []int{0, 7}, a.Lookup([]byte("abra")))
+	assert.Equal(t, []int{1, 8}, a.Lookup([]byte("bra")))
+	assert.Equal(t, []int{0}, a.Lookup([]byte("abracadabra")))
+	assert.Nil(t, a.Lookup([]byte("abracadabrab")))
+	assert.Nil(t, a.Lookup([]byte("x")))
+	assert.Nil(t, a.Lookup(nil))
+
+	offset, err := a.Suffix(0)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, offset) // "a"
+	l, err := a.LCP(1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, l) // "a" and "abra"
+	_, err = a.Suffix(11)
+	assert.Error(t, err)
+
+	empty := New(nil, Config{})
+	assert.Nil(t, empty.Lookup([]byte("a")))
+}
+
+func TestLookupRandomized(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	text := make([]byte, 5000)
+	for i := range text {
+		text[i] = byte('a' + rng.IntN(4))
+	}
+	a := New(text, Config{})
+	reference := suffixarray.New(text)
+
+	for i := 0; i < 200; i++ {
+		from := rng.IntN(len(text))
+		pattern := text[from:min(from+1+rng.IntN(8), len(text))]
+		expected := reference.Lookup(pattern, -1)
+		assert.ElementsMatch(t, expected, a.Lookup(pattern))
+		assert.IsIncreasing(t, a.Lookup(pattern))
+	}
+}
+
+func TestLongestRepeat(t *testing.T) {
+	a := New([]byte("banana"), Config{})
+	offset, length := a.LongestRepeat()
+	assert.Equal(t, "ana", "banana"[offset:offset+length])
+
+	text := "the quick brown fox, the quick brown cat"
+	a = New([]byte(text), Config{})
+	offset, length = a.LongestRepeat()
+	assert.Equal(t, "the quick brown ", text[offset:offset+length])
+
+	_, length = New([]byte("abc"), Config{}).LongestRepeat()
+	assert.Equal(t, 0, length)
+	_, length = New(nil, Config{}).LongestRepeat()
+	assert.Equal(t, 0, length)
+}
+
+func TestMetrics(t *testing.T) {
+	a := New([]byte("metrics"), Config{MetricsEnabled: true})
+	a.Lookup([]byte("t"))
+	a.Lookup([]byte("x"))
+	assert.Equal(t, int64(2), a.lookupCounter.Count())
+	assert.Equal(t, int64(1), a.buildTimer.Count())
+}
+
+func BenchmarkNew(b *testing.B) {
+	text := []byte(strings.Repeat("the quick brown fox jumps over the lazy dog ", 25000))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		New(text, Config{})
+	}
+}
+
+func BenchmarkLookup(b *testing.B) {
+	a := New(bytes.Repeat([]byte("the quick brown fox jumps over the lazy dog "), 25000), Config{})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.Lookup([]byte("lazy cat"))
+	}
+}
@@ -0,0 +1,187 @@
+package textbuf
+
+import (
+	"bytes"
+	"iter"
+	"sync"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+const minGap = 64
+
+// GapBuffer is a text buffer keeping a gap of free space at the last edit position
+// Edits close to each other only move the bytes between them, which makes it
+// a good fit for cursor-local editing. Line lookups scan the text.
+type GapBuffer struct {
+	data          []byte
+	gapStart      int
+	gapEnd        int
+	mu            sync.RWMutex
+	config        Config
+	insertCounter metrics.Counter
+	deleteCounter metrics.Counter
+	moveCounter   metrics.Counter
+}
+
+var _ TextBuffer = (*GapBuffer)(nil)
+
+// NewGapBuffer creates a gap buffer holding text
+// The config parameter is used to enable or disable metrics collection
+func NewGapBuffer(text string, config Config) *GapBuffer {
+	data := make([]byte, len(text)+minGap)
+	copy(data, text)
+	g := &GapBuffer{data: data, gapStart: len(text), gapEnd: len(data), config: config}
+
+	// Initialize the metrics only if enabled in the config
+	if g.config.MetricsEnabled {
+		g.insertCounter = metrics.NewCounter()
+		g.deleteCounter = metrics.NewCounter()
+		g.moveCounter = metrics.NewCounter()
+		metrics.DefaultRegistry.Register("textbuf.gap.insert", g.insertCounter)
+		metrics.DefaultRegistry.Register("textbuf.gap.delete", g.deleteCounter)
+		metrics.DefaultRegistry.Register("textbuf.gap.move", g.moveCounter)
+	}
+
+	return g
+}
+
+// Len returns the length of the text in bytes
+func (g *GapBuffer) Len() int {
+	g.mu.RLock() // Lock for reading
+	defer g.mu.RUnlock()
+
+	return g.length()
+}
+
+func (g *GapBuffer) length() int {
+	return len(g.data) - (g.gapEnd - g.gapStart)
+}
+
+// Insert inserts text at the byte offset at, moving the gap there first
+func (g *GapBuffer) Insert(at int, text string) error {
+	g.mu.Lock() // Lock for writing
+	defer g.mu.Unlock()
+
+	if at < 0 || at > g.length() {
+		return ErrIndexOutOfBounds
+	}
+	g.moveGap(at)
+	if g.gapEnd-g.gapStart < len(text) {
+		g.grow(len(text))
+	}
+	copy(g.data[g.gapStart:], text)
+	g.gapStart += len(text)
+
+	// Track metrics if enabled
+	if g.config.MetricsEnabled {
+		g.insertCounter.Inc(1)
+	}
+	return nil
+}
+
+// Delete removes n bytes starting at the byte offset at by widening the gap
+func (g *GapBuffer) Delete(at, n int) error {
+	g.mu.Lock() // Lock for writing
+	defer g.mu.Unlock()
+
+	if at < 0 || n < 0 || at+n > g.length() {
+		return ErrInvalidRange
+	}
+	g.moveGap(at)
+	g.gapEnd += n
+
+	// Track metrics if enabled
+	if g.config.MetricsEnabled {
+		g.deleteCounter.Inc(1)
+	}
+	return nil
+}
+
+// moveGap moves the gap so that it starts at the byte offset at
+func (g *GapBuffer) moveGap(at int) {
+	switch {
+	case at < g.gapStart:
+		// Shift the bytes in [at, gapStart) after the gap
+		n := g.gapStart - at
+		copy(g.data[g.gapEnd-n:g.gapEnd], g.data[at:g.gapStart])
+		g.gapStart, g.gapEnd = at, g.gapEnd-n
+	case at > g.gapStart:
+		// Shift the bytes following the gap before it
+		n := at - g.gapStart
+		copy(g.data[g.gapStart:at], g.data[g.gapEnd:g.gapEnd+n])
+		g.gapStart, g.gapEnd = at, g.gapEnd+n
+	default:
+		return
+	}
+
+	// Track metrics if enabled
+	if g.config.MetricsEnabled {
+		g.moveCounter.Inc(1)
+	}
+}
+
+// grow reallocates the buffer so that the gap can hold at least n bytes
+func (g *GapBuffer) grow(n int) {
+	tail := len(g.data) - g.gapEnd
+	gap := n + max(len(g.data), minGap)
+	data := make([]byte, g.gapStart+gap+tail)
+	copy(data, g.data[:g.gapStart])
+	copy(data[g.gapStart+gap:], g.data[g.gapEnd:])
+	g.data = data
+	g.gapEnd = g.gapStart + gap
+}
+
+// Slice returns the text in the byte range [from, to)
+func (g *GapBuffer) Slice(from, to int) (string, error) {
+	g.mu.RLock() // Lock for reading
+	defer g.mu.RUnlock()
+
+	if from < 0 || to < from || to > g.length() {
+		return "", ErrInvalidRange
+	}
+	return slice(g.segments(), from, to), nil
+}
+
+// String returns the whole text
+func (g *GapBuffer) String() string {
+	g.mu.RLock() // Lock for reading
+	defer g.mu.RUnlock()
+
+	return string(g.data[:g.gapStart]) + string(g.data[g.gapEnd:])
+}
+
+// Lines returns the number of lines, which is one more than the number of newlines
+func (g *GapBuffer) Lines() int {
+	g.mu.RLock() // Lock for reading
+	defer g.mu.RUnlock()
+
+	return bytes.Count(g.data[:g.gapStart], []byte{'\n'}) + bytes.Count(g.data[g.gapEnd:], []byte{'\n'}) + 1
+}
+
+// Position returns the zero-based line and byte column of the byte offset
+func (g *GapBuffer) Position(offset int) (line, column int, err error) {
+	g.mu.RLock() // Lock for reading
+	defer g.mu.RUnlock()
+
+	if offset < 0 || offset > g.length() {
+		return 0, 0, ErrIndexOutOfBounds
+	}
+	line, column = position(g.segments(), offset)
+	return line, column, nil
+}
+
+// Offset returns the byte offset of the zero-based line and byte column
+func (g *GapBuffer) Offset(line, column int) (int, error) {
+	g.mu.RLock() // Lock for reading
+	defer g.mu.RUnlock()
+
+	return offset(g.segments(), g.length(), line, column)
+}
+
+// segments returns the text before and after the gap
+func (g *GapBuffer) segments() iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		_ = yield(g.data[:g.gapStart]) && yield(g.data[g.gapEnd:])
+	}
+}
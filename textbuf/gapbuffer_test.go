@@ -0,0 +1,28 @@
+package textbuf
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGapBufferGrow(t *testing.T) {
+	g := NewGapBuffer("head tail", Config{})
+	text := strings.Repeat("y", 1000)
+	assert.NoError(t, g.Insert(5, text))
+	assert.Equal(t, "head "+text+"tail", g.String())
+	assert.Equal(t, 5+len(text), g.gapStart)
+	assert.GreaterOrEqual(t, g.gapEnd-g.gapStart, minGap)
+}
+
+func TestGapBufferMetrics(t *testing.T) {
+	g := NewGapBuffer("abc", Config{MetricsEnabled: true})
+	g.Insert(3, "d") // The gap is already at the end
+	g.Insert(0, "x")
+	g.Delete(2, 1)
+	assert.Equal(t, int64(2), g.insertCounter.Count())
+	assert.Equal(t, int64(1), g.deleteCounter.Count())
+	assert.Equal(t, int64(2), g.moveCounter.Count())
+	assert.Equal(t, "xacd", g.String())
+}
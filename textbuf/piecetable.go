@@ -0,0 +1,309 @@
+package textbuf
+
+import (
+	"bytes"
+	"iter"
+	"slices"
+	"sync"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+// source identifies the buffer a piece points into
+type source uint8
+
+const (
+	sourceOriginal source = iota
+	sourceAdd
+)
+
+// piece is a span of either the original or the add buffer
+type piece struct {
+	source   source
+	start    int
+	length   int
+	newlines int
+}
+
+// change replaces the pieces removed at index with the pieces inserted
+type change struct {
+	index    int
+	removed  []piece
+	inserted []piece
+}
+
+// PieceTable is a text buffer describing the text as a sequence of pieces
+// The original text is never modified and inserted text is only ever appended
+// to the add buffer, so an edit only replaces a few pieces. Undo and Redo
+// swap those pieces back and are cheap regardless of the text size.
+type PieceTable struct {
+	original      []byte
+	add           []byte
+	pieces        []piece
+	length        int
+	newlines      int
+	undo          []change
+	redo          []change
+	mu            sync.RWMutex
+	config        Config
+	insertCounter metrics.Counter
+	deleteCounter metrics.Counter
+	undoCounter   metrics.Counter
+}
+
+var _ TextBuffer = (*PieceTable)(nil)
+
+// NewPieceTable creates a piece table with text as its original buffer
+// The config parameter is used to set the undo depth and enable or disable metrics collection
+func NewPieceTable(text string, config Config) *PieceTable {
+	if config.MaxUndo <= 0 {
+		config.MaxUndo = defaultMaxUndo
+	}
+	p := &PieceTable{original: []byte(text), config: config}
+	if len(text) > 0 {
+		p.pieces = []piece{{source: sourceOriginal, length: len(text), newlines: bytes.Count(p.original, []byte{'\n'})}}
+	}
+	p.length, p.newlines = totals(p.pieces)
+
+	// Initialize the metrics only if enabled in the config
+	if p.config.MetricsEnabled {
+		p.insertCounter = metrics.NewCounter()
+		p.deleteCounter = metrics.NewCounter()
+		p.undoCounter = metrics.NewCounter()
+		metrics.DefaultRegistry.Register("textbuf.piece.insert", p.insertCounter)
+		metrics.DefaultRegistry.Register("textbuf.piece.delete", p.deleteCounter)
+		metrics.DefaultRegistry.Register("textbuf.piece.undo", p.undoCounter)
+	}
+
+	return p
+}
+
+// Len returns the length of the text in bytes
+func (p *PieceTable) Len() int {
+	p.mu.RLock() // Lock for reading
+	defer p.mu.RUnlock()
+
+	return p.length
+}
+
+// Insert appends text to the add buffer and splices a piece for it in at the byte offset at
+func (p *PieceTable) Insert(at int, text string) error {
+	p.mu.Lock() // Lock for writing
+	defer p.mu.Unlock()
+
+	if at < 0 || at > p.length {
+		return ErrIndexOutOfBounds
+	}
+	if len(text) == 0 {
+		return nil
+	}
+
+	start := len(p.add)
+	p.add = append(p.add, text...)
+	inserted := piece{source: sourceAdd, start: start, length: len(text), newlines: bytes.Count(p.add[start:], []byte{'\n'})}
+
+	i, off := p.locate(at)
+	switch {
+	case off > 0:
+		left, right := p.split(p.pieces[i], off)
+		p.apply(change{index: i, removed: []piece{p.pieces[i]}, inserted: []piece{left, inserted, right}})
+	case i > 0 && p.pieces[i-1].source == sourceAdd && p.pieces[i-1].start+p.pieces[i-1].length == start:
+		// Typing at the end of the previous insertion extends its piece
+		extended := p.pieces[i-1]
+		extended.length += inserted.length
+		extended.newlines += inserted.newlines
+		p.apply(change{index: i - 1, removed: []piece{p.pieces[i-1]}, inserted: []piece{extended}})
+	default:
+		p.apply(change{index: i, inserted: []piece{inserted}})
+	}
+
+	// Track metrics if enabled
+	if p.config.MetricsEnabled {
+		p.insertCounter.Inc(1)
+	}
+	return nil
+}
+
+// Delete removes n bytes starting at the byte offset at by trimming the pieces covering them
+func (p *PieceTable) Delete(at, n int) error {
+	p.mu.Lock() // Lock for writing
+	defer p.mu.Unlock()
+
+	if at < 0 || n < 0 || at+n > p.length {
+		return ErrInvalidRange
+	}
+	if n == 0 {
+		return nil
+	}
+
+	i, off := p.locate(at)
+	j, endOff := p.locate(at + n)
+	var kept []piece
+	if off > 0 {
+		left, _ := p.split(p.pieces[i], off)
+		kept = append(kept, left)
+	}
+	if endOff > 0 {
+		_, right := p.split(p.pieces[j], endOff)
+		kept = append(kept, right)
+		j++
+	}
+	p.apply(change{index: i, removed: slices.Clone(p.pieces[i:j]), inserted: kept})
+
+	// Track metrics if enabled
+	if p.config.MetricsEnabled {
+		p.deleteCounter.Inc(1)
+	}
+	return nil
+}
+
+// Undo reverts the last Insert or Delete
+func (p *PieceTable) Undo() error {
+	p.mu.Lock() // Lock for writing
+	defer p.mu.Unlock()
+
+	if len(p.undo) == 0 {
+		return ErrNothingToUndo
+	}
+	c := p.undo[len(p.undo)-1]
+	p.undo = p.undo[:len(p.undo)-1]
+	p.replace(change{index: c.index, removed: c.inserted, inserted: c.removed})
+	p.redo = append(p.redo, c)
+
+	// Track metrics if enabled
+	if p.config.MetricsEnabled {
+		p.undoCounter.Inc(1)
+	}
+	return nil
+}
+
+// Redo reapplies the last change reverted by Undo
+// Any new edit discards the changes that could be redone.
+func (p *PieceTable) Redo() error {
+	p.mu.Lock() // Lock for writing
+	defer p.mu.Unlock()
+
+	if len(p.redo) == 0 {
+		return ErrNothingToRedo
+	}
+	c := p.redo[len(p.redo)-1]
+	p.redo = p.redo[:len(p.redo)-1]
+	p.replace(c)
+	p.undo = append(p.undo, c)
+	return nil
+}
+
+// apply makes a change and records it for Undo, forgetting what could be redone
+func (p *PieceTable) apply(c change) {
+	p.replace(c)
+	if len(p.undo) == p.config.MaxUndo {
+		p.undo = slices.Delete(p.undo, 0, 1)
+	}
+	p.undo = append(p.undo, c)
+	p.redo = nil
+}
+
+// replace swaps the removed pieces of a change for the inserted ones
+func (p *PieceTable) replace(c change) {
+	p.pieces = slices.Replace(p.pieces, c.index, c.index+len(c.removed), c.inserted...)
+	removedLength, removedNewlines := totals(c.removed)
+	insertedLength, insertedNewlines := totals(c.inserted)
+	p.length += insertedLength - removedLength
+	p.newlines += insertedNewlines - removedNewlines
+}
+
+// locate returns the index of the piece containing the byte offset at and the offset within it
+// An offset on a piece boundary is reported at the start of the following piece.
+func (p *PieceTable) locate(at int) (int, int) {
+	for i, pc := range p.pieces {
+		if at < pc.length {
+			return i, at
+		}
+		at -= pc.length
+	}
+	return len(p.pieces), 0
+}
+
+// split cuts pc in two at the offset off
+func (p *PieceTable) split(pc piece, off int) (piece, piece) {
+	newlines := bytes.Count(p.bytes(pc)[:off], []byte{'\n'})
+	left := piece{source: pc.source, start: pc.start, length: off, newlines: newlines}
+	right := piece{source: pc.source, start: pc.start + off, length: pc.length - off, newlines: pc.newlines - newlines}
+	return left, right
+}
+
+// bytes returns the text a piece refers to
+func (p *PieceTable) bytes(pc piece) []byte {
+	if pc.source == sourceAdd {
+		return p.add[pc.start : pc.start+pc.length]
+	}
+	return p.original[pc.start : pc.start+pc.length]
+}
+
+// totals sums the lengths and newline counts of pieces
+func totals(pieces []piece) (length, newlines int) {
+	for _, pc := range pieces {
+		length += pc.length
+		newlines += pc.newlines
+	}
+	return length, newlines
+}
+
+// Slice returns the text in the byte range [from, to)
+func (p *PieceTable) Slice(from, to int) (string, error) {
+	p.mu.RLock() // Lock for reading
+	defer p.mu.RUnlock()
+
+	if from < 0 || to < from || to > p.length {
+		return "", ErrInvalidRange
+	}
+	return slice(p.segments(), from, to), nil
+}
+
+// String returns the whole text
+func (p *PieceTable) String() string {
+	p.mu.RLock() // Lock for reading
+	defer p.mu.RUnlock()
+
+	return slice(p.segments(), 0, p.length)
+}
+
+// Lines returns the number of lines, which is one more than the number of newlines
+// Every piece caches its newline count, so this does not scan the text.
+func (p *PieceTable) Lines() int {
+	p.mu.RLock() // Lock for reading
+	defer p.mu.RUnlock()
+
+	return p.newlines + 1
+}
+
+// Position returns the zero-based line and byte column of the byte offset
+func (p *PieceTable) Position(offset int) (line, column int, err error) {
+	p.mu.RLock() // Lock for reading
+	defer p.mu.RUnlock()
+
+	if offset < 0 || offset > p.length {
+		return 0, 0, ErrIndexOutOfBounds
+	}
+	line, column = position(p.segments(), offset)
+	return line, column, nil
+}
+
+// Offset returns the byte offset of the zero-based line and byte column
+func (p *PieceTable) Offset(line, column int) (int, error) {
+	p.mu.RLock() // Lock for reading
+	defer p.mu.RUnlock()
+
+	return offset(p.segments(), p.length, line, column)
+}
+
+// segments returns the text of every piece in order
+func (p *PieceTable) segments() iter.Seq[[]byte] {
+	return func(yield func([]byte) bool) {
+		for _, pc := range p.pieces {
+			if !yield(p.bytes(pc)) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package textbuf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPieceTableUndoRedo(t *testing.T) {
+	p := NewPieceTable("original\ntext", Config{})
+	assert.Equal(t, ErrNothingToUndo, p.Undo())
+
+	p.Insert(9, "added\n")
+	p.Delete(0, 4)
+	assert.Equal(t, "inal\nadded\ntext", p.String())
+	assert.Equal(t, 3, p.Lines())
+
+	assert.NoError(t, p.Undo())
+	assert.Equal(t, "original\nadded\ntext", p.String())
+	assert.NoError(t, p.Undo())
+	assert.Equal(t, "original\ntext", p.String())
+	assert.Equal(t, 2, p.Lines())
+	assert.Equal(t, ErrNothingToUndo, p.Undo())
+
+	assert.NoError(t, p.Redo())
+	assert.Equal(t, "original\nadded\ntext", p.String())
+	assert.Equal(t, 3, p.Lines())
+
+	// A new edit discards what could be redone
+	p.Insert(0, ">")
+	assert.Equal(t, ErrNothingToRedo, p.Redo())
+	assert.Equal(t, ">original\nadded\ntext", p.String())
+	assert.Equal(t, "original\ntext", string(p.original))
+}
+
+func TestPieceTableMaxUndo(t *testing.T) {
+	p := NewPieceTable("", Config{MaxUndo: 2})
+	p.Insert(0, "a")
+	p.Insert(0, "b")
+	p.Insert(0, "c")
+	assert.NoError(t, p.Undo())
+	assert.NoError(t, p.Undo())
+	assert.Equal(t, ErrNothingToUndo, p.Undo())
+	assert.Equal(t, "a", p.String())
+}
+
+func TestPieceTableAppendExtendsPiece(t *testing.T) {
+	p := NewPieceTable("abc", Config{})
+	for i, c := range "typed" {
+		p.Insert(1+i, string(c))
+	}
+	assert.Equal(t, "atypedbc", p.String())
+	assert.Len(t, p.pieces, 3)
+	assert.Equal(t, "typed", string(p.add))
+}
+
+func TestPieceTableMetrics(t *testing.T) {
+	p := NewPieceTable("abc", Config{MetricsEnabled: true})
+	p.Insert(1, "x")
+	p.Delete(0, 1)
+	p.Undo()
+	assert.Equal(t, int64(1), p.insertCounter.Count())
+	assert.Equal(t, int64(1), p.deleteCounter.Count())
+	assert.Equal(t, int64(1), p.undoCounter.Count())
+}
@@ -0,0 +1,112 @@
+package textbuf
+
+import (
+	"bytes"
+	"errors"
+	"iter"
+	"strings"
+)
+
+// Errors returned by the text buffers
+var (
+	ErrIndexOutOfBounds = errors.New("index out of bounds")
+	ErrInvalidRange     = errors.New("invalid range")
+	ErrNothingToUndo    = errors.New("nothing to undo")
+	ErrNothingToRedo    = errors.New("nothing to redo")
+)
+
+// TextBuffer is an editable text addressed by byte offsets
+// Lines and columns are zero-based, columns count bytes from the start of
+// the line. rope.Rope implements it as well.
+type TextBuffer interface {
+	// Insert inserts text at the byte offset at
+	Insert(at int, text string) error
+	// Delete removes n bytes starting at the byte offset at
+	Delete(at, n int) error
+	// Slice returns the text in the byte range [from, to)
+	Slice(from, to int) (string, error)
+	// Len returns the length of the text in bytes
+	Len() int
+	// Lines returns the number of lines, which is one more than the number of newlines
+	Lines() int
+	// Position returns the line and column of a byte offset
+	Position(offset int) (line, column int, err error)
+	// Offset returns the byte offset of a line and column
+	Offset(line, column int) (int, error)
+	// String returns the whole text
+	String() string
+}
+
+const defaultMaxUndo = 1000
+
+type Config struct {
+	// MaxUndo bounds the number of edits a PieceTable can undo, the oldest ones are forgotten first (default 1000)
+	MaxUndo        int
+	MetricsEnabled bool
+}
+
+// slice returns the bytes in [from, to) of the text made of segments
+func slice(segments iter.Seq[[]byte], from, to int) string {
+	var b strings.Builder
+	b.Grow(to - from)
+	for segment := range segments {
+		if to <= 0 {
+			break
+		}
+		b.Write(segment[min(from, len(segment)):min(to, len(segment))])
+		from, to = max(from-len(segment), 0), to-len(segment)
+	}
+	return b.String()
+}
+
+// position computes the line and column of offset in the text made of segments
+func position(segments iter.Seq[[]byte], offset int) (line, column int) {
+	lineStart, start := 0, 0
+	for segment := range segments {
+		if start >= offset {
+			break
+		}
+		part := segment[:min(len(segment), offset-start)]
+		line += bytes.Count(part, []byte{'\n'})
+		if i := bytes.LastIndexByte(part, '\n'); i >= 0 {
+			lineStart = start + i + 1
+		}
+		start += len(segment)
+	}
+	return line, offset - lineStart
+}
+
+// offset computes the byte offset of line and column in the text made of segments
+// The column may point at the newline ending the line but not past it.
+func offset(segments iter.Seq[[]byte], length, line, column int) (int, error) {
+	if line < 0 || column < 0 {
+		return 0, ErrIndexOutOfBounds
+	}
+	lineStart, lineEnd := -1, length
+	if line == 0 {
+		lineStart = 0
+	}
+	newlines, start := 0, 0
+	for segment := range segments {
+		for i, c := range segment {
+			if c != '\n' {
+				continue
+			}
+			if newlines == line {
+				lineEnd = start + i
+				break
+			}
+			if newlines++; newlines == line {
+				lineStart = start + i + 1
+			}
+		}
+		if lineEnd < length {
+			break
+		}
+		start += len(segment)
+	}
+	if lineStart < 0 || lineStart+column > lineEnd {
+		return 0, ErrIndexOutOfBounds
+	}
+	return lineStart + column, nil
+}
@@ -0,0 +1,163 @@
+package textbuf
+
+import (
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vzahanych/data-structures/rope"
+)
+
+var _ TextBuffer = (*rope.Rope)(nil)
+
+// implementations creates every TextBuffer holding text
+func implementations(text string) map[string]TextBuffer {
+	return map[string]TextBuffer{
+		"GapBuffer":  NewGapBuffer(text, Config{}),
+		"PieceTable": NewPieceTable(text, Config{}),
+		"Rope":       rope.New(text, rope.Config{}),
+	}
+}
+
+func TestInsertDelete(t *testing.T) {
+	for name, b := range implementations("hello world") {
+		t.Run(name, func(t *testing.T) {
+			assert.NoError(t, b.Insert(5, ","))
+			assert.NoError(t, b.Insert(b.Len(), "!"))
+			assert.NoError(t, b.Insert(0, ">> "))
+			assert.Equal(t, ">> hello, world!", b.String())
+
+			assert.NoError(t, b.Delete(0, 3))
+			assert.NoError(t, b.Delete(5, 1))
+			assert.Equal(t, "hello world!", b.String())
+
+			s, err := b.Slice(6, 11)
+			assert.NoError(t, err)
+			assert.Equal(t, "world", s)
+
+			assert.Equal(t, ErrIndexOutOfBounds.Error(), b.Insert(-1, "x").Error())
+			assert.Equal(t, ErrIndexOutOfBounds.Error(), b.Insert(100, "x").Error())
+			assert.Equal(t, ErrInvalidRange.Error(), b.Delete(10, 5).Error())
+			_, err = b.Slice(5, 4)
+			assert.Equal(t, ErrInvalidRange.Error(), err.Error())
+			assert.Equal(t, "hello world!", b.String())
+		})
+	}
+}
+
+func TestRandomized(t *testing.T) {
+	for name, b := range implementations("") {
+		t.Run(name, func(t *testing.T) {
+			rng := rand.New(rand.NewPCG(1, 2))
+			model := ""
+
+			for round := 0; round < 2000; round++ {
+				if len(model) > 0 && rng.IntN(3) == 0 {
+					at := rng.IntN(len(model))
+					n := rng.IntN(min(len(model)-at, 200) + 1)
+					assert.NoError(t, b.Delete(at, n))
+					model = model[:at] + model[at+n:]
+				} else {
+					at := rng.IntN(len(model) + 1)
+					text := strings.Repeat(string(rune('a'+round%26)), rng.IntN(100)) + strings.Repeat("\n", rng.IntN(2))
+					assert.NoError(t, b.Insert(at, text))
+					model = model[:at] + text + model[at:]
+				}
+			}
+			assert.Equal(t, len(model), b.Len())
+			assert.Equal(t, model, b.String())
+			assert.Equal(t, strings.Count(model, "\n")+1, b.Lines())
+
+			for i := 0; i < 100; i++ {
+				from := rng.IntN(len(model) + 1)
+				to := from + rng.IntN(len(model)-from+1)
+				s, err := b.Slice(from, to)
+				assert.NoError(t, err)
+				assert.Equal(t, model[from:to], s)
+			}
+		})
+	}
+}
+
+func TestLines(t *testing.T) {
+	text := strings.Repeat("first line\nsecond\n\nlast", 50)
+	for name, b := range implementations(text) {
+		t.Run(name, func(t *testing.T) {
+			// Split the text into several pieces and move the gap around
+			assert.NoError(t, b.Insert(300, ""))
+			assert.NoError(t, b.Insert(700, "x"))
+			assert.NoError(t, b.Delete(700, 1))
+			assert.Equal(t, strings.Count(text, "\n")+1, b.Lines())
+
+			for _, offset := range []int{0, 5, 11, 17, 18, 19, 300, 700, len(text)} {
+				line, column, err := b.Position(offset)
+				assert.NoError(t, err)
+				assert.Equal(t, strings.Count(text[:offset], "\n"), line)
+				assert.Equal(t, offset-(strings.LastIndex(text[:offset], "\n")+1), column)
+
+				back, err := b.Offset(line, column)
+				assert.NoError(t, err)
+				assert.Equal(t, offset, back)
+			}
+
+			_, err := b.Offset(1, 7) // Past the end of "second"
+			assert.Equal(t, ErrIndexOutOfBounds.Error(), err.Error())
+			_, err = b.Offset(b.Lines(), 0)
+			assert.Equal(t, ErrIndexOutOfBounds.Error(), err.Error())
+			_, _, err = b.Position(len(text) + 1)
+			assert.Equal(t, ErrIndexOutOfBounds.Error(), err.Error())
+		})
+	}
+
+	for name, b := range implementations("") {
+		assert.Equal(t, 1, b.Lines(), name)
+		offset, err := b.Offset(0, 0)
+		assert.NoError(t, err, name)
+		assert.Equal(t, 0, offset, name)
+	}
+}
+
+// benchmarkDelete deletes at varying offsets of a 1000 byte text, re-inserting to keep its size
+func benchmarkDelete(b *testing.B, buffer TextBuffer) {
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buffer.Delete(i%1000, 1) // Delete bytes at varying offsets
+		buffer.Insert(i%1000, "x")
+	}
+}
+
+// benchmarkInsert inserts next to the previous insertion, like typing, clearing the typed line every 100 bytes
+func benchmarkInsert(b *testing.B, buffer TextBuffer) {
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buffer.Insert(500+i%100, "x")
+		if i%100 == 99 {
+			buffer.Delete(500, 100)
+		}
+	}
+}
+
+func BenchmarkGapBufferDelete(b *testing.B) {
+	benchmarkDelete(b, NewGapBuffer(strings.Repeat("x", 1000), Config{}))
+}
+
+func BenchmarkPieceTableDelete(b *testing.B) {
+	benchmarkDelete(b, NewPieceTable(strings.Repeat("x", 1000), Config{}))
+}
+
+func BenchmarkRopeDelete(b *testing.B) {
+	benchmarkDelete(b, rope.New(strings.Repeat("x", 1000), rope.Config{}))
+}
+
+func BenchmarkGapBufferInsert(b *testing.B) {
+	benchmarkInsert(b, NewGapBuffer(strings.Repeat("x", 1000), Config{}))
+}
+
+func BenchmarkPieceTableInsert(b *testing.B) {
+	benchmarkInsert(b, NewPieceTable(strings.Repeat("x", 1000), Config{}))
+}
+
+func BenchmarkRopeInsert(b *testing.B) {
+	benchmarkInsert(b, rope.New(strings.Repeat("x", 1000), rope.Config{}))
+}
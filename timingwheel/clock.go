@@ -0,0 +1,93 @@
+package timingwheel
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the source of time of a Wheel
+type Clock interface {
+	Now() time.Time
+	// NewTicker returns a channel receiving the time every d and a function stopping it
+	NewTicker(d time.Duration) (<-chan time.Time, func())
+}
+
+// systemClock is the wall clock
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTicker(d time.Duration) (<-chan time.Time, func()) {
+	t := time.NewTicker(d)
+	return t.C, t.Stop
+}
+
+// ManualClock is a Clock that only moves when told to, for deterministic tests
+type ManualClock struct {
+	now     time.Time
+	tickers []*manualTicker
+	mu      sync.Mutex
+}
+
+type manualTicker struct {
+	c      chan time.Time
+	period time.Duration
+	next   time.Time
+}
+
+// NewManualClock creates a manual clock set to now
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+// Now returns the time of the clock
+func (c *ManualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// Add moves the clock forward by d and delivers the ticks that became due
+// Like time.Ticker, a tick is dropped when the previous one was not received.
+func (c *ManualClock) Add(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+	for _, t := range c.tickers {
+		if t.next.After(c.now) {
+			continue
+		}
+		for !t.next.After(c.now) {
+			t.next = t.next.Add(t.period)
+		}
+		select {
+		case t.c <- c.now:
+		default:
+		}
+	}
+}
+
+// NewTicker returns a channel receiving the time whenever Add crosses a multiple of d
+func (c *ManualClock) NewTicker(d time.Duration) (<-chan time.Time, func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &manualTicker{c: make(chan time.Time, 1), period: d, next: c.now.Add(d)}
+	c.tickers = append(c.tickers, t)
+	stop := func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		for i, other := range c.tickers {
+			if other == t {
+				c.tickers = append(c.tickers[:i], c.tickers[i+1:]...)
+				return
+			}
+		}
+	}
+	return t.c, stop
+}
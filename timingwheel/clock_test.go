@@ -0,0 +1,29 @@
+package timingwheel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManualClock(t *testing.T) {
+	clock := NewManualClock(epoch)
+	ticks, stop := clock.NewTicker(10 * time.Millisecond)
+
+	clock.Add(5 * time.Millisecond)
+	assert.Len(t, ticks, 0)
+	clock.Add(5 * time.Millisecond)
+	assert.Equal(t, epoch.Add(10*time.Millisecond), <-ticks)
+
+	// Ticks that are not received are dropped
+	clock.Add(10 * time.Millisecond)
+	clock.Add(10 * time.Millisecond)
+	assert.Len(t, ticks, 1)
+	assert.Equal(t, epoch.Add(20*time.Millisecond), <-ticks)
+
+	stop()
+	clock.Add(time.Second)
+	assert.Len(t, ticks, 0)
+	assert.Equal(t, epoch.Add(1030*time.Millisecond), clock.Now())
+}
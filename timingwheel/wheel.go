@@ -0,0 +1,293 @@
+package timingwheel
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+const (
+	defaultTick = 10 * time.Millisecond
+	slotBits    = 6
+	slots       = 1 << slotBits
+	slotMask    = slots - 1
+	levels      = 8 // Spans 2^48 ticks, longer delays are cascaded again from the top level
+	maxDelta    = 1<<(slotBits*levels) - 1
+)
+
+type Config struct {
+	// Tick is the resolution of the wheel, timers fire on the first tick at or after their deadline (default 10ms)
+	Tick time.Duration
+	// Clock drives the wheel (default the system clock)
+	Clock          Clock
+	MetricsEnabled bool
+}
+
+// Timer is a callback scheduled on a Wheel, it is the handle passed to Cancel and Reset
+type Timer struct {
+	wheel    *Wheel
+	fn       func()
+	deadline time.Time
+	expires  int64 // Tick at which the timer fires
+	level    int
+	bucket   *bucket
+	prev     *Timer
+	next     *Timer
+}
+
+// Deadline returns the time the timer was scheduled for
+func (t *Timer) Deadline() time.Time {
+	t.wheel.mu.RLock() // Lock for reading, Reset changes the deadline
+	defer t.wheel.mu.RUnlock()
+
+	return t.deadline
+}
+
+// bucket is a slot of the wheel: an intrusive doubly linked list of timers
+type bucket struct {
+	head *Timer
+}
+
+func (b *bucket) push(t *Timer) {
+	t.bucket, t.prev, t.next = b, nil, b.head
+	if b.head != nil {
+		b.head.prev = t
+	}
+	b.head = t
+}
+
+func (b *bucket) remove(t *Timer) {
+	if t.prev != nil {
+		t.prev.next = t.next
+	} else {
+		b.head = t.next
+	}
+	if t.next != nil {
+		t.next.prev = t.prev
+	}
+	t.bucket, t.prev, t.next = nil, nil, nil
+}
+
+// Wheel is a hierarchical timing wheel
+// Level 0 has one slot per tick, and each slot of level i covers a whole
+// rotation of level i-1. A timer is stored at the lowest level whose span
+// contains its deadline and moves down a level every time the lower level
+// completes a rotation, so scheduling, cancelling and expiring are O(1)
+// regardless of the number of timers. Ticks during which the lowest levels
+// are empty are skipped rather than processed one by one. Callbacks run on
+// the goroutine calling Advance or Run, one after the other, and may
+// schedule or cancel timers.
+type Wheel struct {
+	wheels       [levels][slots]bucket
+	start        time.Time
+	current      int64 // Next tick to process
+	pending      int
+	counts       [levels]int // Number of timers stored at each level
+	mu           sync.RWMutex
+	config       Config
+	pendingGauge metrics.Gauge
+	lagTimer     metrics.Timer
+	firedCounter metrics.Counter
+}
+
+// New creates an empty wheel starting at the current time of its clock
+// The config parameter sets the tick and the clock and enables metrics collection
+func New(config Config) *Wheel {
+	if config.Tick <= 0 {
+		config.Tick = defaultTick
+	}
+	if config.Clock == nil {
+		config.Clock = systemClock{}
+	}
+	w := &Wheel{start: config.Clock.Now(), config: config}
+
+	// Initialize the metrics only if enabled in the config
+	if w.config.MetricsEnabled {
+		w.pendingGauge = metrics.NewGauge()
+		w.lagTimer = metrics.NewTimer()
+		w.firedCounter = metrics.NewCounter()
+		metrics.DefaultRegistry.Register("timingwheel.pending", w.pendingGauge)
+		metrics.DefaultRegistry.Register("timingwheel.lag", w.lagTimer)
+		metrics.DefaultRegistry.Register("timingwheel.fired", w.firedCounter)
+	}
+
+	return w
+}
+
+// Schedule arranges for fn to be called once d has elapsed
+func (w *Wheel) Schedule(d time.Duration, fn func()) *Timer {
+	t := &Timer{wheel: w, fn: fn}
+	deadline := w.config.Clock.Now().Add(d)
+
+	w.mu.Lock() // Lock for writing
+	defer w.mu.Unlock()
+
+	w.schedule(t, deadline)
+	return t
+}
+
+// Cancel stops t from firing, it returns false when t already fired or was cancelled
+func (w *Wheel) Cancel(t *Timer) bool {
+	w.mu.Lock() // Lock for writing
+	defer w.mu.Unlock()
+
+	if t.bucket == nil {
+		return false
+	}
+	w.unlink(t)
+	w.setPending(w.pending - 1)
+	return true
+}
+
+// Reset reschedules t to fire once d has elapsed, whether or not it already fired
+// It returns whether t was still pending.
+func (w *Wheel) Reset(t *Timer, d time.Duration) bool {
+	deadline := w.config.Clock.Now().Add(d)
+
+	w.mu.Lock() // Lock for writing
+	defer w.mu.Unlock()
+
+	active := t.bucket != nil
+	if active {
+		w.unlink(t)
+		w.setPending(w.pending - 1)
+	}
+	w.schedule(t, deadline)
+	return active
+}
+
+// Len returns the number of pending timers
+func (w *Wheel) Len() int {
+	w.mu.RLock() // Lock for reading
+	defer w.mu.RUnlock()
+
+	return w.pending
+}
+
+// schedule computes the expiry tick of t and stores it
+func (w *Wheel) schedule(t *Timer, deadline time.Time) {
+	t.deadline = deadline
+	// Round up so that a timer never fires before its deadline
+	elapsed := deadline.Sub(w.start)
+	t.expires = int64(elapsed / w.config.Tick)
+	if elapsed%w.config.Tick > 0 {
+		t.expires++
+	}
+	w.add(t)
+	w.setPending(w.pending + 1)
+}
+
+// add stores t at the lowest level spanning its expiry
+func (w *Wheel) add(t *Timer) {
+	expires := max(t.expires, w.current)
+	delta := expires - w.current
+	if delta > maxDelta {
+		// Parked in the farthest top level slot, it is placed again when cascaded
+		delta = maxDelta
+		expires = w.current + maxDelta
+	}
+	level := 0
+	for delta >= 1<<(slotBits*(level+1)) {
+		level++
+	}
+	t.level = level
+	w.counts[level]++
+	w.wheels[level][(expires>>(slotBits*level))&slotMask].push(t)
+}
+
+// unlink removes t from its slot
+func (w *Wheel) unlink(t *Timer) {
+	w.counts[t.level]--
+	t.bucket.remove(t)
+}
+
+// Advance fires the timers whose deadline has passed according to the clock and returns how many fired
+func (w *Wheel) Advance() int {
+	now := w.config.Clock.Now()
+	target := int64(now.Sub(w.start) / w.config.Tick)
+
+	w.mu.Lock() // Lock for writing
+	var expired []*Timer
+	for w.current <= target {
+		if w.pending == len(expired) {
+			// Nothing left to cascade, skip the idle ticks
+			w.current = target + 1
+			break
+		}
+		// When the lowest levels are empty nothing happens until the next cascade into them
+		level := 0
+		for w.counts[level] == 0 {
+			level++
+		}
+		if span := int64(1) << (slotBits * level); w.current&(span-1) != 0 {
+			w.current = min(w.current|(span-1)+1, target+1)
+			continue
+		}
+		expired = w.step(expired)
+	}
+	w.setPending(w.pending - len(expired))
+
+	// Track metrics if enabled, while the lock keeps Reset from changing the deadlines
+	if w.config.MetricsEnabled {
+		for _, t := range expired {
+			w.lagTimer.Update(now.Sub(t.deadline))
+		}
+		w.firedCounter.Inc(int64(len(expired)))
+	}
+	w.mu.Unlock()
+
+	for _, t := range expired {
+		t.fn()
+	}
+	return len(expired)
+}
+
+// step processes the current tick: it cascades the higher levels whose turn
+// has come and collects the level 0 timers of the tick
+func (w *Wheel) step(expired []*Timer) []*Timer {
+	for level := 1; level < levels; level++ {
+		shift := slotBits * level
+		if w.current&(1<<shift-1) != 0 {
+			break
+		}
+		b := &w.wheels[level][(w.current>>shift)&slotMask]
+		for t := b.head; t != nil; t = b.head {
+			w.unlink(t)
+			w.add(t)
+		}
+	}
+
+	b := &w.wheels[0][w.current&slotMask]
+	for t := b.head; t != nil; t = b.head {
+		w.unlink(t)
+		expired = append(expired, t)
+	}
+	w.current++
+	return expired
+}
+
+// Run advances the wheel on every tick of its clock until ctx is done
+func (w *Wheel) Run(ctx context.Context) error {
+	ticks, stop := w.config.Clock.NewTicker(w.config.Tick)
+	defer stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticks:
+			w.Advance()
+		}
+	}
+}
+
+func (w *Wheel) setPending(n int) {
+	w.pending = n
+
+	// Track metrics if enabled
+	if w.config.MetricsEnabled {
+		w.pendingGauge.Update(int64(n))
+	}
+}
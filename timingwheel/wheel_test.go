@@ -0,0 +1,227 @@
+package timingwheel
+
+import (
+	"context"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func newWheel(config Config) (*Wheel, *ManualClock) {
+	clock := NewManualClock(epoch)
+	config.Clock = clock
+	return New(config), clock
+}
+
+func TestSchedule(t *testing.T) {
+	w, clock := newWheel(Config{Tick: time.Millisecond})
+	var fired []string
+	w.Schedule(5*time.Millisecond, func() { fired = append(fired, "5ms") })
+	w.Schedule(time.Millisecond, func() { fired = append(fired, "1ms") })
+	w.Schedule(1500*time.Microsecond, func() { fired = append(fired, "1.5ms") })
+	assert.Equal(t, 3, w.Len())
+
+	assert.Equal(t, 0, w.Advance())
+	clock.Add(time.Millisecond)
+	assert.Equal(t, 1, w.Advance())
+	assert.Equal(t, []string{"1ms"}, fired)
+
+	// Deadlines between ticks round up
+	clock.Add(time.Millisecond)
+	assert.Equal(t, 1, w.Advance())
+	clock.Add(10 * time.Millisecond)
+	assert.Equal(t, 1, w.Advance())
+	assert.Equal(t, []string{"1ms", "1.5ms", "5ms"}, fired)
+	assert.Equal(t, 0, w.Len())
+
+	// A deadline already passed fires on the next tick
+	w.Schedule(-time.Second, func() { fired = append(fired, "past") })
+	clock.Add(time.Millisecond)
+	assert.Equal(t, 1, w.Advance())
+	assert.Equal(t, "past", fired[len(fired)-1])
+}
+
+func TestLongDelays(t *testing.T) {
+	w, clock := newWheel(Config{Tick: time.Millisecond})
+	delays := []time.Duration{
+		63 * time.Millisecond,
+		64 * time.Millisecond,
+		4097 * time.Millisecond,
+		3 * time.Minute,
+		26 * time.Hour,
+	}
+	firedAt := make(map[time.Duration]time.Time)
+	for _, d := range delays {
+		w.Schedule(d, func() { firedAt[d] = clock.Now() })
+	}
+
+	// Advance in uneven steps so that cascades happen in between calls
+	for clock.Now().Before(epoch.Add(27 * time.Hour)) {
+		clock.Add(997 * time.Millisecond)
+		w.Advance()
+	}
+	for _, d := range delays {
+		at, ok := firedAt[d]
+		assert.True(t, ok, d)
+		assert.False(t, at.Before(epoch.Add(d)), d)
+		assert.Less(t, at.Sub(epoch.Add(d)), time.Second, d)
+	}
+}
+
+func TestBeyondTopLevel(t *testing.T) {
+	w, clock := newWheel(Config{Tick: time.Nanosecond})
+	fired := false
+	delay := time.Duration(maxDelta) * 3
+	w.Schedule(delay, func() { fired = true })
+
+	// The timer is parked in the top level and placed again until it is due
+	clock.Add(delay - time.Nanosecond)
+	w.Advance()
+	assert.False(t, fired)
+	clock.Add(time.Nanosecond)
+	w.Advance()
+	assert.True(t, fired)
+}
+
+func TestRandomizedNeverEarly(t *testing.T) {
+	w, clock := newWheel(Config{Tick: time.Millisecond})
+	rng := rand.New(rand.NewPCG(1, 2))
+	remaining := 0
+	for i := 0; i < 5000; i++ {
+		deadline := epoch.Add(time.Duration(rng.Int64N(int64(10 * time.Minute))))
+		remaining++
+		w.Schedule(deadline.Sub(epoch), func() {
+			remaining--
+			assert.False(t, clock.Now().Before(deadline))
+			assert.Less(t, clock.Now().Sub(deadline), 50*time.Millisecond)
+		})
+	}
+	for remaining > 0 {
+		clock.Add(time.Duration(1+rng.IntN(50)) * time.Millisecond)
+		w.Advance()
+	}
+	assert.Equal(t, 0, w.Len())
+}
+
+func TestDeadlineConcurrentReset(t *testing.T) {
+	w, _ := newWheel(Config{Tick: time.Millisecond})
+	timer := w.Schedule(time.Millisecond, func() {})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 1; i <= 100; i++ {
+			w.Reset(timer, time.Duration(i)*time.Millisecond)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		timer.Deadline()
+	}
+	<-done
+	assert.Equal(t, epoch.Add(100*time.Millisecond), timer.Deadline())
+}
+
+func TestCancelReset(t *testing.T) {
+	w, clock := newWheel(Config{Tick: time.Millisecond})
+	fired := 0
+	cancelled := w.Schedule(10*time.Millisecond, func() { fired++ })
+	reset := w.Schedule(10*time.Millisecond, func() { fired++ })
+
+	assert.True(t, w.Cancel(cancelled))
+	assert.False(t, w.Cancel(cancelled))
+	assert.True(t, w.Reset(reset, time.Second))
+	assert.Equal(t, epoch.Add(time.Second), reset.Deadline())
+
+	clock.Add(100 * time.Millisecond)
+	assert.Equal(t, 0, w.Advance())
+	clock.Add(time.Second)
+	assert.Equal(t, 1, w.Advance())
+	assert.False(t, w.Cancel(reset))
+
+	// A fired timer can be reset
+	assert.False(t, w.Reset(reset, time.Millisecond))
+	clock.Add(time.Millisecond)
+	assert.Equal(t, 1, w.Advance())
+	assert.Equal(t, 2, fired)
+}
+
+func TestCallbackReschedules(t *testing.T) {
+	w, clock := newWheel(Config{Tick: time.Millisecond})
+	count := 0
+	var tick func()
+	tick = func() {
+		if count++; count < 3 {
+			w.Schedule(time.Millisecond, tick)
+		}
+	}
+	w.Schedule(time.Millisecond, tick)
+	for i := 0; i < 5; i++ {
+		clock.Add(time.Millisecond)
+		w.Advance()
+	}
+	assert.Equal(t, 3, count)
+}
+
+func TestRun(t *testing.T) {
+	w, clock := newWheel(Config{Tick: time.Millisecond})
+	fired := make(chan struct{})
+	w.Schedule(5*time.Millisecond, func() { close(fired) })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- w.Run(ctx) }()
+
+	for {
+		clock.Add(time.Millisecond)
+		select {
+		case <-fired:
+			cancel()
+			assert.Equal(t, context.Canceled, <-done)
+			return
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	w, clock := newWheel(Config{Tick: time.Millisecond, MetricsEnabled: true})
+	w.Schedule(time.Millisecond, func() {})
+	w.Schedule(time.Millisecond, func() {})
+	assert.Equal(t, int64(2), w.pendingGauge.Value())
+
+	clock.Add(3 * time.Millisecond)
+	w.Advance()
+	assert.Equal(t, int64(0), w.pendingGauge.Value())
+	assert.Equal(t, int64(2), w.firedCounter.Count())
+	assert.Equal(t, int64(2), w.lagTimer.Count())
+	assert.Equal(t, float64(2*time.Millisecond), w.lagTimer.Mean())
+}
+
+func BenchmarkScheduleCancel(b *testing.B) {
+	w, _ := newWheel(Config{Tick: time.Millisecond})
+	// A million connection timeouts already pending
+	for i := 0; i < 1_000_000; i++ {
+		w.Schedule(time.Duration(i%30_000)*time.Millisecond, func() {})
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		t := w.Schedule(30*time.Second, func() {})
+		w.Cancel(t)
+	}
+}
+
+func BenchmarkAdvance(b *testing.B) {
+	w, clock := newWheel(Config{Tick: time.Millisecond})
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		w.Schedule(time.Duration(i%10_000)*time.Millisecond, func() {})
+		clock.Add(time.Millisecond)
+		w.Advance()
+	}
+}
@@ -0,0 +1,202 @@
+package wal
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/vzahanych/data-structures/array"
+)
+
+// Operation codes stored as the first byte of every record
+const (
+	opAppend byte = iota + 1
+	opDelete
+	opResize
+	opAdd
+	opRemove
+)
+
+type arrayState[T any] struct {
+	Capacity int
+	Values   []T
+}
+
+// Array is an array.Array whose mutations are written to a log before being applied
+// Values are encoded with encoding/gob.
+type Array[T any] struct {
+	mu    sync.Mutex // Serializes mutations so that the log and the array apply them in the same order
+	log   *Log
+	array *array.Array[T]
+}
+
+// RecoverArray opens the array stored in dir, replaying its snapshot and log
+// capacity is only used when dir holds no array yet.
+func RecoverArray[T any](dir string, capacity int, arrayConfig array.ArrayConfig, config Config) (*Array[T], error) {
+	log, err := Open(dir, config)
+	if err != nil {
+		return nil, err
+	}
+	a, err := recoverArray[T](log, capacity, arrayConfig)
+	if err != nil {
+		log.Close()
+		return nil, err
+	}
+	return a, nil
+}
+
+func recoverArray[T any](log *Log, capacity int, arrayConfig array.ArrayConfig) (*Array[T], error) {
+	state, found, err := log.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		// Checkpoint right away so that the capacity survives a restart
+		a := &Array[T]{log: log, array: array.NewArray[T](capacity, arrayConfig)}
+		return a, a.Checkpoint()
+	}
+
+	var s arrayState[T]
+	if err := gob.NewDecoder(bytes.NewReader(state)).Decode(&s); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrCorrupt, err)
+	}
+	arr := array.NewArray[T](s.Capacity, arrayConfig)
+	for _, v := range s.Values {
+		if err := arr.Append(v); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrCorrupt, err)
+		}
+	}
+
+	err = log.Replay(func(record []byte) error {
+		if err := applyArray(arr, record); err != nil {
+			return fmt.Errorf("%w: %v", ErrCorrupt, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Array[T]{log: log, array: arr}, nil
+}
+
+func applyArray[T any](arr *array.Array[T], record []byte) error {
+	if len(record) == 0 {
+		return errors.New("empty record")
+	}
+	switch record[0] {
+	case opAppend:
+		var value T
+		if err := gob.NewDecoder(bytes.NewReader(record[1:])).Decode(&value); err != nil {
+			return err
+		}
+		return arr.Append(value)
+	case opDelete, opResize:
+		n, length := binary.Varint(record[1:])
+		if length <= 0 {
+			return errors.New("invalid argument")
+		}
+		if record[0] == opDelete {
+			return arr.Delete(int(n))
+		}
+		return arr.Resize(int(n))
+	}
+	return fmt.Errorf("unknown operation %d", record[0])
+}
+
+// Append logs and adds a new element to the array
+func (a *Array[T]) Append(value T) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// Validate first so that the log only holds operations that succeed
+	if a.array.Length() >= a.array.Capacity() {
+		return errors.New("array is full")
+	}
+	var buf bytes.Buffer
+	buf.WriteByte(opAppend)
+	if err := gob.NewEncoder(&buf).Encode(value); err != nil {
+		return err
+	}
+	if err := a.log.Append(buf.Bytes()); err != nil {
+		return err
+	}
+	return a.array.Append(value)
+}
+
+// Delete logs and removes the element at the specified index
+func (a *Array[T]) Delete(index int) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if index < 0 || index >= a.array.Length() {
+		return errors.New("index out of bounds")
+	}
+	if err := a.log.Append(intRecord(opDelete, index)); err != nil {
+		return err
+	}
+	return a.array.Delete(index)
+}
+
+// Resize logs and resizes the array to a new capacity
+func (a *Array[T]) Resize(newCapacity int) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if newCapacity < a.array.Length() {
+		return errors.New("new capacity must be greater than or equal to the current size")
+	}
+	if err := a.log.Append(intRecord(opResize, newCapacity)); err != nil {
+		return err
+	}
+	return a.array.Resize(newCapacity)
+}
+
+// Get retrieves an element at the specified index
+func (a *Array[T]) Get(index int) (T, error) {
+	return a.array.Get(index)
+}
+
+// Length returns the current number of elements in the array
+func (a *Array[T]) Length() int {
+	return a.array.Length()
+}
+
+// Capacity returns the number of elements the array can hold without resizing
+func (a *Array[T]) Capacity() int {
+	return a.array.Capacity()
+}
+
+// Snapshot returns a read-only view of the current contents
+func (a *Array[T]) Snapshot() *array.Snapshot[T] {
+	return a.array.Snapshot()
+}
+
+// Checkpoint stores the contents of the array and truncates the log
+func (a *Array[T]) Checkpoint() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	s := arrayState[T]{Capacity: a.array.Capacity(), Values: a.array.Values()}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(s); err != nil {
+		return err
+	}
+	return a.log.Checkpoint(buf.Bytes())
+}
+
+// Sync flushes the log to stable storage
+func (a *Array[T]) Sync() error {
+	return a.log.Sync()
+}
+
+// Close flushes and closes the log
+func (a *Array[T]) Close() error {
+	return a.log.Close()
+}
+
+func intRecord(op byte, n int) []byte {
+	return binary.AppendVarint([]byte{op}, int64(n))
+}
@@ -0,0 +1,88 @@
+package wal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vzahanych/data-structures/array"
+)
+
+func TestArrayRecover(t *testing.T) {
+	dir := t.TempDir()
+	a, err := RecoverArray[string](dir, 3, array.ArrayConfig{}, Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, a.Append("a"))
+	assert.NoError(t, a.Append("b"))
+	assert.NoError(t, a.Append("c"))
+	assert.Error(t, a.Append("d")) // Full, not logged
+	assert.NoError(t, a.Resize(5))
+	assert.NoError(t, a.Append("d"))
+	assert.NoError(t, a.Delete(0))
+	assert.Error(t, a.Delete(10)) // Out of bounds, not logged
+	assert.NoError(t, a.Close())
+
+	// The capacity argument is ignored once the array exists
+	a, err = RecoverArray[string](dir, 1, array.ArrayConfig{}, Config{})
+	assert.NoError(t, err)
+	defer a.Close()
+	assert.Equal(t, 5, a.Capacity())
+	assert.Equal(t, 3, a.Length())
+	for i, expected := range []string{"b", "c", "d"} {
+		value, err := a.Get(i)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, value)
+	}
+}
+
+func TestArrayCheckpoint(t *testing.T) {
+	dir := t.TempDir()
+	a, err := RecoverArray[int](dir, 10, array.ArrayConfig{}, Config{})
+	assert.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, a.Append(i))
+	}
+	assert.NoError(t, a.Checkpoint())
+	assert.NoError(t, a.Delete(2))
+	assert.NoError(t, a.Append(5))
+	assert.NoError(t, a.Close())
+
+	a, err = RecoverArray[int](dir, 10, array.ArrayConfig{}, Config{})
+	assert.NoError(t, err)
+	defer a.Close()
+
+	var values []int
+	for _, v := range a.Snapshot().All() {
+		values = append(values, v)
+	}
+	assert.Equal(t, []int{0, 1, 3, 4, 5}, values)
+}
+
+func TestArrayStruct(t *testing.T) {
+	type point struct{ X, Y int }
+
+	dir := t.TempDir()
+	a, err := RecoverArray[point](dir, 2, array.ArrayConfig{}, Config{SyncPolicy: SyncNever})
+	assert.NoError(t, err)
+	assert.NoError(t, a.Append(point{1, 2}))
+	assert.NoError(t, a.Close())
+
+	a, err = RecoverArray[point](dir, 2, array.ArrayConfig{}, Config{SyncPolicy: SyncNever})
+	assert.NoError(t, err)
+	defer a.Close()
+	value, err := a.Get(0)
+	assert.NoError(t, err)
+	assert.Equal(t, point{1, 2}, value)
+}
+
+func BenchmarkArrayAppend(b *testing.B) {
+	a, err := RecoverArray[int](b.TempDir(), b.N, array.ArrayConfig{}, Config{SyncPolicy: SyncNever})
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer a.Close()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.Append(i)
+	}
+}
@@ -0,0 +1,161 @@
+package wal
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/vzahanych/data-structures/linkedlist"
+)
+
+type listState struct {
+	Values []interface{}
+}
+
+// LinkedList is a linkedlist.LinkedList whose mutations are written to a log before being applied
+// Elements are encoded with encoding/gob: types other than the predeclared
+// ones must be registered with gob.Register.
+type LinkedList struct {
+	mu   sync.Mutex // Serializes mutations so that the log and the list apply them in the same order
+	log  *Log
+	list *linkedlist.LinkedList
+}
+
+// RecoverLinkedList opens the list stored in dir, replaying its snapshot and log
+func RecoverLinkedList(dir string, config Config) (*LinkedList, error) {
+	log, err := Open(dir, config)
+	if err != nil {
+		return nil, err
+	}
+	l, err := recoverLinkedList(log)
+	if err != nil {
+		log.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+func recoverLinkedList(log *Log) (*LinkedList, error) {
+	list := linkedlist.NewLinkedList()
+
+	state, found, err := log.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		var s listState
+		if err := gob.NewDecoder(bytes.NewReader(state)).Decode(&s); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrCorrupt, err)
+		}
+		for _, v := range s.Values {
+			list.Add(v)
+		}
+	}
+
+	err = log.Replay(func(record []byte) error {
+		if err := applyList(list, record); err != nil {
+			return fmt.Errorf("%w: %v", ErrCorrupt, err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &LinkedList{log: log, list: list}, nil
+}
+
+func applyList(list *linkedlist.LinkedList, record []byte) error {
+	if len(record) == 0 {
+		return errors.New("empty record")
+	}
+	var data interface{}
+	if err := gob.NewDecoder(bytes.NewReader(record[1:])).Decode(&data); err != nil {
+		return err
+	}
+	switch record[0] {
+	case opAdd:
+		list.Add(data)
+	case opRemove:
+		list.Remove(data)
+	default:
+		return fmt.Errorf("unknown operation %d", record[0])
+	}
+	return nil
+}
+
+// Add logs and adds an element at the end of the list
+func (l *LinkedList) Add(data interface{}) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := l.append(opAdd, data); err != nil {
+		return err
+	}
+	l.list.Add(data)
+	return nil
+}
+
+// Remove logs and removes an element from the list
+// Nothing is logged when the element is absent.
+func (l *LinkedList) Remove(data interface{}) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.list.Find(data) == nil {
+		return nil
+	}
+	if err := l.append(opRemove, data); err != nil {
+		return err
+	}
+	l.list.Remove(data)
+	return nil
+}
+
+func (l *LinkedList) append(op byte, data interface{}) error {
+	var buf bytes.Buffer
+	buf.WriteByte(op)
+	// Encode through a pointer so that the dynamic type is kept
+	if err := gob.NewEncoder(&buf).Encode(&data); err != nil {
+		return err
+	}
+	return l.log.Append(buf.Bytes())
+}
+
+// Find an element in the list
+func (l *LinkedList) Find(data interface{}) *linkedlist.Node {
+	return l.list.Find(data)
+}
+
+// Length returns the number of elements in the list
+func (l *LinkedList) Length() int {
+	return l.list.Length()
+}
+
+// Values returns the elements of the list in order
+func (l *LinkedList) Values() []interface{} {
+	return l.list.Values()
+}
+
+// Checkpoint stores the contents of the list and truncates the log
+func (l *LinkedList) Checkpoint() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(listState{Values: l.list.Values()}); err != nil {
+		return err
+	}
+	return l.log.Checkpoint(buf.Bytes())
+}
+
+// Sync flushes the log to stable storage
+func (l *LinkedList) Sync() error {
+	return l.log.Sync()
+}
+
+// Close flushes and closes the log
+func (l *LinkedList) Close() error {
+	return l.log.Close()
+}
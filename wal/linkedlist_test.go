@@ -0,0 +1,62 @@
+package wal
+
+import (
+	"encoding/gob"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type item struct {
+	Name string
+}
+
+func init() {
+	gob.Register(item{})
+}
+
+func TestLinkedListRecover(t *testing.T) {
+	dir := t.TempDir()
+	l, err := RecoverLinkedList(dir, Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, l.Add(1))
+	assert.NoError(t, l.Add("two"))
+	assert.NoError(t, l.Add(item{"three"}))
+	assert.NoError(t, l.Remove("two"))
+	assert.NoError(t, l.Remove("absent"))
+	assert.NoError(t, l.Close())
+
+	l, err = RecoverLinkedList(dir, Config{})
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{1, item{"three"}}, l.Values())
+	assert.NotNil(t, l.Find(item{"three"}))
+
+	// Replayed elements compare equal to the original ones
+	assert.NoError(t, l.Remove(1))
+	assert.Equal(t, 1, l.Length())
+	assert.NoError(t, l.Close())
+}
+
+func TestLinkedListCheckpoint(t *testing.T) {
+	dir := t.TempDir()
+	l, err := RecoverLinkedList(dir, Config{SegmentSize: 64})
+	assert.NoError(t, err)
+	for i := 0; i < 50; i++ {
+		assert.NoError(t, l.Add(i))
+	}
+	for i := 0; i < 50; i += 2 {
+		assert.NoError(t, l.Remove(i))
+	}
+	assert.NoError(t, l.Checkpoint())
+	assert.NoError(t, l.Add("tail"))
+	assert.NoError(t, l.Close())
+
+	l, err = RecoverLinkedList(dir, Config{SegmentSize: 64})
+	assert.NoError(t, err)
+	defer l.Close()
+
+	values := l.Values()
+	assert.Len(t, values, 26)
+	assert.Equal(t, 1, values[0])
+	assert.Equal(t, "tail", values[25])
+}
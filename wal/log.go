@@ -0,0 +1,465 @@
+package wal
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rcrowley/go-metrics"
+)
+
+// Errors returned by the log
+var (
+	ErrCorrupt = errors.New("wal: corrupt log")
+	ErrClosed  = errors.New("wal: log is closed")
+)
+
+// SyncPolicy controls when appended records are flushed to stable storage
+type SyncPolicy int
+
+const (
+	// SyncAlways fsyncs after every record: no acknowledged write is ever lost
+	SyncAlways SyncPolicy = iota
+	// SyncInterval fsyncs in the background every Config.SyncInterval
+	SyncInterval
+	// SyncNever leaves flushing to the operating system
+	SyncNever
+)
+
+const (
+	defaultSegmentSize  = 16 << 20
+	defaultSyncInterval = 100 * time.Millisecond
+
+	segmentSuffix = ".wal"
+	snapshotName  = "snapshot"
+	snapshotMagic = "WSNP"
+
+	// Record layout: length(4) crc32(4) payload(length)
+	recordHeaderLength = 8
+	// Snapshot layout: magic(4) firstSegment(8) crc32(4) length(4) state(length)
+	snapshotHeaderLength = 20
+)
+
+var crcTable = crc32.MakeTable(crc32.Castagnoli)
+
+type Config struct {
+	SyncPolicy SyncPolicy
+	// SyncInterval is the flush period used by SyncInterval (default 100ms)
+	SyncInterval time.Duration
+	// SegmentSize is the size after which a new segment file is started (default 16 MiB)
+	SegmentSize    int64
+	MetricsEnabled bool
+}
+
+// Log is an append-only, checksummed write-ahead log split into segment files
+// A checkpoint stores a snapshot of the state and drops the segments it covers,
+// so recovery replays the snapshot followed by the remaining records.
+type Log struct {
+	dir           string
+	config        Config
+	mu            sync.Mutex
+	file          *os.File
+	segment       uint64 // Index of the segment being written
+	segmentSize   int64
+	firstSegment  uint64 // First segment not covered by the snapshot
+	closed        bool
+	done          chan struct{}
+	syncer        sync.WaitGroup
+	appendCounter metrics.Counter
+	bytesCounter  metrics.Counter
+	syncCounter   metrics.Counter
+	syncDuration  metrics.Timer
+}
+
+// Open opens or creates the log stored in dir
+// A record left incomplete at the end of the last segment by a crash is discarded.
+func Open(dir string, config Config) (*Log, error) {
+	if config.SyncInterval <= 0 {
+		config.SyncInterval = defaultSyncInterval
+	}
+	if config.SegmentSize <= 0 {
+		config.SegmentSize = defaultSegmentSize
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	l := &Log{dir: dir, config: config, done: make(chan struct{})}
+	_, first, err := l.readSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	l.firstSegment = first
+
+	segments, err := l.segments()
+	if err != nil {
+		return nil, err
+	}
+	l.segment = l.firstSegment
+	if len(segments) > 0 {
+		l.segment = max(l.segment, segments[len(segments)-1])
+	}
+
+	// Only the last segment may end with a torn record, truncate it away
+	path := l.segmentPath(l.segment)
+	if _, err := os.Stat(path); err == nil {
+		valid, err := scanSegment(path, nil)
+		if err != nil && !errors.Is(err, errTorn) {
+			return nil, err
+		}
+		if err := os.Truncate(path, valid); err != nil {
+			return nil, err
+		}
+	}
+	if err := l.openSegment(l.segment); err != nil {
+		return nil, err
+	}
+
+	// Initialize the metrics only if enabled in the config
+	if l.config.MetricsEnabled {
+		l.appendCounter = metrics.NewCounter()
+		l.bytesCounter = metrics.NewCounter()
+		l.syncCounter = metrics.NewCounter()
+		l.syncDuration = metrics.NewTimer()
+		metrics.DefaultRegistry.Register("wal.append", l.appendCounter)
+		metrics.DefaultRegistry.Register("wal.bytes", l.bytesCounter)
+		metrics.DefaultRegistry.Register("wal.sync", l.syncCounter)
+		metrics.DefaultRegistry.Register("wal.sync.duration", l.syncDuration)
+	}
+
+	if config.SyncPolicy == SyncInterval {
+		l.syncer.Add(1)
+		go l.syncLoop()
+	}
+	return l, nil
+}
+
+// Append writes a record to the log, rotating to a new segment when the current one is full
+// With SyncAlways the record is on stable storage when Append returns.
+func (l *Log) Append(record []byte) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return ErrClosed
+	}
+	if l.segmentSize >= l.config.SegmentSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+
+	buf := make([]byte, recordHeaderLength+len(record))
+	binary.LittleEndian.PutUint32(buf, uint32(len(record)))
+	binary.LittleEndian.PutUint32(buf[4:], crc32.Checksum(record, crcTable))
+	copy(buf[recordHeaderLength:], record)
+	if _, err := l.file.Write(buf); err != nil {
+		return err
+	}
+	l.segmentSize += int64(len(buf))
+
+	// Track metrics if enabled
+	if l.config.MetricsEnabled {
+		l.appendCounter.Inc(1)
+		l.bytesCounter.Inc(int64(len(buf)))
+	}
+
+	if l.config.SyncPolicy == SyncAlways {
+		return l.sync()
+	}
+	return nil
+}
+
+// Replay calls fn with every record written since the last checkpoint, in order
+func (l *Log) Replay(fn func(record []byte) error) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	segments, err := l.segments()
+	if err != nil {
+		return err
+	}
+	for _, segment := range segments {
+		if segment < l.firstSegment {
+			continue
+		}
+		if _, err := scanSegment(l.segmentPath(segment), fn); err != nil {
+			if errors.Is(err, errTorn) {
+				return ErrCorrupt
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Snapshot returns the state stored by the last checkpoint
+// The second result is false when no checkpoint was taken yet.
+func (l *Log) Snapshot() ([]byte, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	state, _, err := l.readSnapshot()
+	return state, state != nil, err
+}
+
+// Checkpoint stores state as the new snapshot and deletes the segments it covers
+// state must reflect every record appended so far.
+func (l *Log) Checkpoint(state []byte) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return ErrClosed
+	}
+	// Start a fresh segment so that the snapshot covers whole segments only
+	if err := l.rotate(); err != nil {
+		return err
+	}
+
+	buf := make([]byte, snapshotHeaderLength+len(state))
+	copy(buf, snapshotMagic)
+	binary.LittleEndian.PutUint64(buf[4:], l.segment)
+	binary.LittleEndian.PutUint32(buf[12:], crc32.Checksum(state, crcTable))
+	binary.LittleEndian.PutUint32(buf[16:], uint32(len(state)))
+	copy(buf[snapshotHeaderLength:], state)
+
+	// Write then rename so that a crash leaves either the old or the new snapshot
+	tmp := filepath.Join(l.dir, snapshotName+".tmp")
+	if err := writeFileSync(tmp, buf); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(l.dir, snapshotName)); err != nil {
+		return err
+	}
+	if err := syncDir(l.dir); err != nil {
+		return err
+	}
+	l.firstSegment = l.segment
+
+	segments, err := l.segments()
+	if err != nil {
+		return err
+	}
+	for _, segment := range segments {
+		if segment < l.firstSegment {
+			if err := os.Remove(l.segmentPath(segment)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Sync flushes the current segment to stable storage
+func (l *Log) Sync() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.closed {
+		return ErrClosed
+	}
+	return l.sync()
+}
+
+// Close flushes and closes the log
+func (l *Log) Close() error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil
+	}
+	l.closed = true
+	close(l.done)
+	l.mu.Unlock()
+
+	// Wait for the background syncer outside of the lock it takes
+	l.syncer.Wait()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.sync(); err != nil {
+		l.file.Close()
+		return err
+	}
+	return l.file.Close()
+}
+
+func (l *Log) syncLoop() {
+	defer l.syncer.Done()
+
+	ticker := time.NewTicker(l.config.SyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.done:
+			return
+		case <-ticker.C:
+			l.mu.Lock()
+			if !l.closed {
+				l.sync()
+			}
+			l.mu.Unlock()
+		}
+	}
+}
+
+// sync flushes the current segment, the caller must hold the lock
+func (l *Log) sync() error {
+	start := time.Now()
+	if err := l.file.Sync(); err != nil {
+		return err
+	}
+
+	// Track metrics if enabled
+	if l.config.MetricsEnabled {
+		l.syncCounter.Inc(1)
+		l.syncDuration.UpdateSince(start)
+	}
+	return nil
+}
+
+// rotate closes the current segment and starts the next one, the caller must hold the lock
+func (l *Log) rotate() error {
+	if err := l.sync(); err != nil {
+		return err
+	}
+	if err := l.file.Close(); err != nil {
+		return err
+	}
+	return l.openSegment(l.segment + 1)
+}
+
+func (l *Log) openSegment(segment uint64) error {
+	file, err := os.OpenFile(l.segmentPath(segment), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	l.file = file
+	l.segment = segment
+	l.segmentSize = info.Size()
+	return syncDir(l.dir)
+}
+
+func (l *Log) segmentPath(segment uint64) string {
+	return filepath.Join(l.dir, fmt.Sprintf("%016d%s", segment, segmentSuffix))
+}
+
+// segments returns the indexes of the segment files in increasing order
+func (l *Log) segments() ([]uint64, error) {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return nil, err
+	}
+	var segments []uint64
+	for _, e := range entries {
+		name, found := strings.CutSuffix(e.Name(), segmentSuffix)
+		if !found {
+			continue
+		}
+		segment, err := strconv.ParseUint(name, 10, 64)
+		if err != nil {
+			continue
+		}
+		segments = append(segments, segment)
+	}
+	slices.Sort(segments)
+	return segments, nil
+}
+
+// readSnapshot loads the snapshot file, returning a nil state when there is none
+func (l *Log) readSnapshot() ([]byte, uint64, error) {
+	data, err := os.ReadFile(filepath.Join(l.dir, snapshotName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(data) < snapshotHeaderLength || string(data[:4]) != snapshotMagic {
+		return nil, 0, ErrCorrupt
+	}
+	first := binary.LittleEndian.Uint64(data[4:])
+	checksum := binary.LittleEndian.Uint32(data[12:])
+	length := binary.LittleEndian.Uint32(data[16:])
+	state := data[snapshotHeaderLength:]
+	if uint32(len(state)) != length || crc32.Checksum(state, crcTable) != checksum {
+		return nil, 0, ErrCorrupt
+	}
+	return state, first, nil
+}
+
+// errTorn reports a segment ending with an incomplete or corrupt record
+var errTorn = errors.New("wal: torn record")
+
+// scanSegment reads the records of a segment, calling fn for each one if not nil
+// It returns the length of the valid prefix of the file, and errTorn if the
+// file continues with an invalid record.
+func scanSegment(path string, fn func(record []byte) error) (int64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	var offset int64
+	for int(offset) < len(data) {
+		rest := data[offset:]
+		if len(rest) < recordHeaderLength {
+			return offset, errTorn
+		}
+		length := int64(binary.LittleEndian.Uint32(rest))
+		checksum := binary.LittleEndian.Uint32(rest[4:])
+		if int64(len(rest)-recordHeaderLength) < length {
+			return offset, errTorn
+		}
+		record := rest[recordHeaderLength : recordHeaderLength+length]
+		if crc32.Checksum(record, crcTable) != checksum {
+			return offset, errTorn
+		}
+		if fn != nil {
+			if err := fn(record); err != nil {
+				return offset, err
+			}
+		}
+		offset += recordHeaderLength + length
+	}
+	return offset, nil
+}
+
+func writeFileSync(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// syncDir makes file creations and renames in dir durable
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
@@ -0,0 +1,178 @@
+package wal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func replayAll(t *testing.T, l *Log) []string {
+	var records []string
+	assert.NoError(t, l.Replay(func(record []byte) error {
+		records = append(records, string(record))
+		return nil
+	}))
+	return records
+}
+
+func TestAppendReplay(t *testing.T) {
+	dir := t.TempDir()
+	l, err := Open(dir, Config{})
+	assert.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, l.Append([]byte(fmt.Sprint(i))))
+	}
+	assert.NoError(t, l.Close())
+	assert.Equal(t, ErrClosed, l.Append([]byte("x")))
+
+	l, err = Open(dir, Config{})
+	assert.NoError(t, err)
+	defer l.Close()
+	assert.Equal(t, []string{"0", "1", "2", "3", "4", "5", "6", "7", "8", "9"}, replayAll(t, l))
+}
+
+func TestSegmentRotation(t *testing.T) {
+	dir := t.TempDir()
+	l, err := Open(dir, Config{SegmentSize: 64})
+	assert.NoError(t, err)
+	var expected []string
+	for i := 0; i < 100; i++ {
+		expected = append(expected, fmt.Sprintf("record-%d", i))
+		assert.NoError(t, l.Append([]byte(expected[i])))
+	}
+	segments, err := l.segments()
+	assert.NoError(t, err)
+	assert.Greater(t, len(segments), 10)
+	assert.NoError(t, l.Close())
+
+	l, err = Open(dir, Config{SegmentSize: 64})
+	assert.NoError(t, err)
+	defer l.Close()
+	assert.Equal(t, expected, replayAll(t, l))
+}
+
+func TestTornTail(t *testing.T) {
+	dir := t.TempDir()
+	l, err := Open(dir, Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, l.Append([]byte("kept")))
+	assert.NoError(t, l.Append([]byte("torn")))
+	path := l.segmentPath(l.segment)
+	assert.NoError(t, l.Close())
+
+	// Simulate a crash in the middle of the last write
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.NoError(t, os.Truncate(path, info.Size()-2))
+
+	l, err = Open(dir, Config{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"kept"}, replayAll(t, l))
+
+	// New records follow the valid prefix
+	assert.NoError(t, l.Append([]byte("next")))
+	assert.NoError(t, l.Close())
+	l, err = Open(dir, Config{})
+	assert.NoError(t, err)
+	defer l.Close()
+	assert.Equal(t, []string{"kept", "next"}, replayAll(t, l))
+}
+
+func TestCorruptSegment(t *testing.T) {
+	dir := t.TempDir()
+	l, err := Open(dir, Config{SegmentSize: 16})
+	assert.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, l.Append([]byte("0123456789")))
+	}
+	assert.NoError(t, l.Close())
+
+	// A bad checksum before the last segment is not a torn write
+	path := filepath.Join(dir, fmt.Sprintf("%016d%s", 0, segmentSuffix))
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	data[recordHeaderLength] ^= 0xff
+	assert.NoError(t, os.WriteFile(path, data, 0o644))
+
+	l, err = Open(dir, Config{SegmentSize: 16})
+	assert.NoError(t, err)
+	defer l.Close()
+	assert.ErrorIs(t, l.Replay(func([]byte) error { return nil }), ErrCorrupt)
+}
+
+func TestCheckpoint(t *testing.T) {
+	dir := t.TempDir()
+	l, err := Open(dir, Config{SegmentSize: 32})
+	assert.NoError(t, err)
+	_, found, err := l.Snapshot()
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	for i := 0; i < 20; i++ {
+		assert.NoError(t, l.Append([]byte("before")))
+	}
+	assert.NoError(t, l.Checkpoint([]byte("state")))
+	assert.NoError(t, l.Append([]byte("after")))
+
+	// Covered segments are deleted
+	segments, err := l.segments()
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{l.firstSegment}, segments)
+	assert.NoError(t, l.Close())
+
+	l, err = Open(dir, Config{SegmentSize: 32})
+	assert.NoError(t, err)
+	defer l.Close()
+	state, found, err := l.Snapshot()
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "state", string(state))
+	assert.Equal(t, []string{"after"}, replayAll(t, l))
+}
+
+func TestSyncPolicies(t *testing.T) {
+	for _, policy := range []SyncPolicy{SyncAlways, SyncInterval, SyncNever} {
+		dir := t.TempDir()
+		l, err := Open(dir, Config{SyncPolicy: policy, SyncInterval: time.Millisecond})
+		assert.NoError(t, err)
+		assert.NoError(t, l.Append([]byte("a")))
+		assert.NoError(t, l.Sync())
+		assert.NoError(t, l.Close())
+		assert.NoError(t, l.Close()) // Closing twice is harmless
+
+		l, err = Open(dir, Config{SyncPolicy: policy})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a"}, replayAll(t, l))
+		assert.NoError(t, l.Close())
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	l, err := Open(t.TempDir(), Config{SyncPolicy: SyncAlways, MetricsEnabled: true})
+	assert.NoError(t, err)
+	defer l.Close()
+
+	assert.NoError(t, l.Append([]byte("abcd")))
+	assert.NoError(t, l.Append([]byte("ef")))
+	assert.Equal(t, int64(2), l.appendCounter.Count())
+	assert.Equal(t, int64(2*recordHeaderLength+6), l.bytesCounter.Count())
+	assert.Equal(t, int64(2), l.syncCounter.Count())
+}
+
+func BenchmarkAppend(b *testing.B) {
+	l, err := Open(b.TempDir(), Config{SyncPolicy: SyncNever})
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer l.Close()
+	record := make([]byte, 128)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		l.Append(record)
+	}
+}